@@ -2,9 +2,11 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/eduardolima806/my-chat-server/config"
 	"github.com/eduardolima806/my-chat-server/internal/app"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 )
 
 func main() {
@@ -14,5 +16,7 @@ func main() {
 		log.Fatalf("Config error: %s", err)
 	}
 
-	app.Run(cgf)
+	if err := app.Run(cgf, logger.New(cgf.Log.Level, os.Stdout)); err != nil {
+		log.Fatalf("App error: %s", err)
+	}
 }
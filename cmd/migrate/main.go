@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/eduardolima806/my-chat-server/config"
+	"github.com/eduardolima806/my-chat-server/internal/infra/db"
+	"github.com/eduardolima806/my-chat-server/internal/infra/migration"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+)
+
+// Usage: migrate [-steps n] up|down|status
+func main() {
+	steps := flag.Int("steps", 1, "number of migrations to revert with down")
+	flag.Parse()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	conn, err := db.ConnectToPostgresDb(context.Background(), cfg.PG, logger.New(cfg.Log.Level, os.Stderr))
+	if err != nil {
+		log.Fatalf("Database error: %s", err)
+	}
+	defer conn.Close()
+
+	migrations, err := migration.Embedded()
+	if err != nil {
+		log.Fatalf("Migration error: %s", err)
+	}
+	migrator := migration.NewMigrator(conn, migrations)
+	ctx := context.Background()
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("Migration error: %s", err)
+		}
+		fmt.Printf("Applied migrations: %v\n", applied)
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			log.Fatalf("Migration error: %s", err)
+		}
+		fmt.Printf("Reverted migrations: %v\n", reverted)
+	case "status":
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			log.Fatalf("Migration error: %s", err)
+		}
+		fmt.Printf("Pending migrations: %v\n", pending)
+	default:
+		log.Fatalf("Unknown command %q, expected up, down or status", flag.Arg(0))
+	}
+}
@@ -1,37 +1,137 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type (
 	Config struct {
-		App  `yaml:"app"`
-		HTTP `yaml:"http"`
-		PG   `yaml:"postgres"`
+		App           `yaml:"app"`
+		HTTP          `yaml:"http"`
+		PG            `yaml:"postgres"`
+		Auth          `yaml:"auth"`
+		Presence      `yaml:"presence"`
+		Bus           `yaml:"bus"`
+		Mail          `yaml:"mail"`
+		LoginThrottle `yaml:"login_throttle"`
+		Log           `yaml:"log"`
 	}
 
 	App struct {
-		Name    string `env-required:"true" yaml: "name" env:"APP_NAME"`
-		Version string `env-required:"true" yaml: "version" env:"APP_VERSION"`
+		Name    string `env-required:"true" yaml:"name" env:"APP_NAME"`
+		Version string `env-required:"true" yaml:"version" env:"APP_VERSION"`
 	}
 
+	// HTTP tunes the server. On shutdown it reports not ready for
+	// ShutdownDelay while still serving, so load balancers stop routing to
+	// it, then ShutdownTimeout bounds how long in-flight requests and
+	// realtime connections get to finish. X-Forwarded-For is only believed
+	// from TrustedProxies, addresses or CIDRs, none by default: the client
+	// address keys the login lockout, so it must not be spoofable.
 	HTTP struct {
-		Port string `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+		Port              string        `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+		ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" env-default:"5s"`
+		ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" env-default:"15s"`
+		WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" env-default:"15s"`
+		IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"60s"`
+		ShutdownDelay     time.Duration `yaml:"shutdown_delay" env:"HTTP_SHUTDOWN_DELAY" env-default:"5s"`
+		ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"30s"`
+		TrustedProxies    []string      `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
 	}
 
+	// PG sets up the connection pool. On start the database is pinged, and
+	// pinged again up to ConnectRetries times while it does not answer, the
+	// wait doubling from ConnectRetryInterval.
 	PG struct {
-		DatabaseDriver string `env-required:"true" yaml:"database_driver" env:"DATABASE_DRIVER"`
-		Host           string `env-required:"true" yaml:"db_host" env:"DB_HOST" env-default:"localhost"`
-		Port           string `env-required:"true" yaml:"db_port" env:"DB_PORT" env-default:"5432"`
-		User           string `env-required:"true" yaml:"db_user" env:"DB_USER" env-default:"postgres"`
-		Password       string `env-required:"true" yaml:"db_password" env:"DB_PASSWORD"`
-		Name           string `env-required:"true" yaml:"db_name" env:"DB_NAME" env-default:"postgres"`
+		DatabaseDriver       string        `env-required:"true" yaml:"database_driver" env:"DATABASE_DRIVER"`
+		Host                 string        `env-required:"true" yaml:"db_host" env:"DB_HOST" env-default:"localhost"`
+		Port                 string        `env-required:"true" yaml:"db_port" env:"DB_PORT" env-default:"5432"`
+		User                 string        `env-required:"true" yaml:"db_user" env:"DB_USER" env-default:"postgres"`
+		Password             string        `env-required:"true" yaml:"db_password" env:"DB_PASSWORD"`
+		Name                 string        `env-required:"true" yaml:"db_name" env:"DB_NAME" env-default:"postgres"`
+		SSLMode              string        `yaml:"ssl_mode" env:"DB_SSL_MODE" env-default:"disable"`
+		AutoMigrate          bool          `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE" env-default:"true"`
+		MaxOpenConns         int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" env-default:"25"`
+		MaxIdleConns         int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" env-default:"10"`
+		ConnMaxLifetime      time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" env-default:"30m"`
+		ConnMaxIdleTime      time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" env-default:"5m"`
+		ConnectTimeout       time.Duration `yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT" env-default:"5s"`
+		ConnectRetries       int           `yaml:"connect_retries" env:"DB_CONNECT_RETRIES" env-default:"5"`
+		ConnectRetryInterval time.Duration `yaml:"connect_retry_interval" env:"DB_CONNECT_RETRY_INTERVAL" env-default:"1s"`
+	}
+
+	// Auth has no default JWTSecret: it signs the access tokens, so it must
+	// be set through JWT_SECRET and be at least MinJWTSecretLength bytes.
+	Auth struct {
+		JWTSecret        string        `env-required:"true" yaml:"jwt_secret" env:"JWT_SECRET"`
+		AccessTokenTTL   time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL" env-default:"15m"`
+		RefreshTokenTTL  time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL" env-default:"720h"`
+		PasswordResetTTL time.Duration `yaml:"password_reset_ttl" env:"PASSWORD_RESET_TTL" env-default:"1h"`
+		// RequireVerifiedEmail refuses to log in users who did not verify the
+		// email of the account.
+		RequireVerifiedEmail       bool          `yaml:"require_verified_email" env:"REQUIRE_VERIFIED_EMAIL" env-default:"false"`
+		EmailVerificationTTL       time.Duration `yaml:"email_verification_ttl" env:"EMAIL_VERIFICATION_TTL" env-default:"24h"`
+		VerificationResendInterval time.Duration `yaml:"verification_resend_interval" env:"VERIFICATION_RESEND_INTERVAL" env-default:"1m"`
+	}
+
+	// Presence is shared between instances over the event bus: each sends
+	// the users connected to it every HeartbeatInterval, and what an instance
+	// reported is dropped once three heartbeats went missing.
+	Presence struct {
+		GracePeriod       time.Duration `yaml:"grace_period" env:"PRESENCE_GRACE_PERIOD" env-default:"15s"`
+		LastSeenFlushRate time.Duration `yaml:"last_seen_flush_rate" env:"PRESENCE_LAST_SEEN_FLUSH_RATE" env-default:"1m"`
+		HeartbeatInterval time.Duration `yaml:"heartbeat_interval" env:"PRESENCE_HEARTBEAT_INTERVAL" env-default:"10s"`
+	}
+
+	// Bus selects how events reach connections held by other instances:
+	// "memory" for a single instance, "postgres" for LISTEN/NOTIFY.
+	// EventRetention is how long events are kept for clients that resume.
+	Bus struct {
+		Driver         string        `yaml:"driver" env:"EVENT_BUS_DRIVER" env-default:"memory"`
+		EventRetention time.Duration `yaml:"event_retention" env:"EVENT_RETENTION" env-default:"24h"`
+	}
+
+	// Mail selects where mails go: "log" writes them to stdout, "file" to
+	// one file per mail in Dir. LinkBaseURL is the public address of the
+	// server, used in the links mails carry.
+	Mail struct {
+		Driver      string `yaml:"driver" env:"MAIL_DRIVER" env-default:"log"`
+		From        string `yaml:"from" env:"MAIL_FROM" env-default:"no-reply@chat.local"`
+		Dir         string `yaml:"dir" env:"MAIL_DIR" env-default:"./mails"`
+		LinkBaseURL string `yaml:"link_base_url" env:"MAIL_LINK_BASE_URL" env-default:"http://localhost:8080"`
+	}
+
+	// LoginThrottle locks accounts and client IPs out after repeated failed
+	// logins. Driver selects where failures are counted: "memory" for a
+	// single instance, "postgres" to share them between instances. Lockouts
+	// start at BaseLockout and double with every further failure, up to
+	// MaxLockout.
+	LoginThrottle struct {
+		Driver             string        `yaml:"driver" env:"LOGIN_THROTTLE_DRIVER" env-default:"memory"`
+		AccountMaxFailures int           `yaml:"account_max_failures" env:"LOGIN_THROTTLE_ACCOUNT_MAX_FAILURES" env-default:"5"`
+		IPMaxFailures      int           `yaml:"ip_max_failures" env:"LOGIN_THROTTLE_IP_MAX_FAILURES" env-default:"20"`
+		FailureWindow      time.Duration `yaml:"failure_window" env:"LOGIN_THROTTLE_FAILURE_WINDOW" env-default:"15m"`
+		BaseLockout        time.Duration `yaml:"base_lockout" env:"LOGIN_THROTTLE_BASE_LOCKOUT" env-default:"1m"`
+		MaxLockout         time.Duration `yaml:"max_lockout" env:"LOGIN_THROTTLE_MAX_LOCKOUT" env-default:"1h"`
+	}
+
+	// Log sets the lowest level written out: debug, info, warn or error.
+	Log struct {
+		Level string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
 	}
 )
 
+// MinJWTSecretLength is the shortest JWT secret the server starts with, the
+// 256 bits of the HMAC-SHA256 key signing the tokens.
+const MinJWTSecretLength = 32
+
+// placeholderJWTSecret is the secret earlier versions shipped in config.yml.
+const placeholderJWTSecret = "change-me-in-production"
+
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 
@@ -46,5 +146,21 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.Auth.validate(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
 	return cfg, nil
 }
+
+func (a Auth) validate() error {
+	switch {
+	case a.JWTSecret == "":
+		return errors.New("JWT_SECRET is not set")
+	case a.JWTSecret == placeholderJWTSecret:
+		return errors.New("JWT_SECRET is the placeholder secret, set a random one")
+	case len(a.JWTSecret) < MinJWTSecretLength:
+		return fmt.Errorf("JWT_SECRET is shorter than %d bytes", MinJWTSecretLength)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_A_Long_Random_JWT_Secret_Is_Accepted(t *testing.T) {
+	auth := Auth{JWTSecret: strings.Repeat("k", MinJWTSecretLength)}
+
+	assert.Nil(t, auth.validate())
+}
+
+func Test_If_Get_Error_When_JWT_Secret_Is_Weak(t *testing.T) {
+	for name, secret := range map[string]string{
+		"empty":       "",
+		"placeholder": "change-me-in-production",
+		"too short":   strings.Repeat("k", MinJWTSecretLength-1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			auth := Auth{JWTSecret: secret}
+
+			assert.Error(t, auth.validate())
+		})
+	}
+}
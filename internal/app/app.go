@@ -1,40 +1,253 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/eduardolima806/my-chat-server/config"
 	v1 "github.com/eduardolima806/my-chat-server/internal/controller/http/v1"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/bus"
 	"github.com/eduardolima806/my-chat-server/internal/infra/db"
+	"github.com/eduardolima806/my-chat-server/internal/infra/health"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/migration"
+	"github.com/eduardolima806/my-chat-server/internal/infra/presence"
 	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/infra/throttle"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/conversation_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/event_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/presence_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
 	"github.com/eduardolima806/my-chat-server/internal/usecase/user_usecase"
 	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/eduardolima806/my-chat-server/pkg/httpserver"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
-func Run(cfg *config.Config) {
+// Run serves until the process is told to stop. It returns an error when a
+// dependency fails to start. Everything it starts logs through l.
+func Run(cfg *config.Config, l logger.Interface) error {
+	ctx := context.Background()
 
-	fmt.Printf("Running %s %s\n", cfg.App.Name, cfg.App.Version)
+	l.Info(ctx, "app - starting", "name", cfg.App.Name, "version", cfg.App.Version)
 
-	handler := gin.Default()
-	conn, err := db.ConnectToPostgresDb(cfg.PG)
+	// The router logs requests and recovers panics with middlewares of its
+	// own, in place of those of gin.Default.
+	handler := gin.New()
+	if err := handler.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	conn, err := db.ConnectToPostgresDb(ctx, cfg.PG, l)
+
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Once the server is shut down at the end of Run, the deferred teardown
+	// stops the presence tracker, then the event bus, and the pool they use
+	// last.
+	defer func() {
+		if err := conn.Close(); err != nil {
+			l.Error(ctx, "app - failed to close database connection", "error", err)
+		}
+	}()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(conn, "chat"),
+	)
 
+	migrations, err := migration.Embedded()
 	if err != nil {
-		fmt.Println(fmt.Errorf("failed to connect to database %w", err))
+		return fmt.Errorf("failed to load migrations: %w", err)
 	}
+	migrator := migration.NewMigrator(conn, migrations)
 
-	defer func(conn *sql.DB) {
-		err := conn.Close()
-		if err != nil {
-			panic("ERROR CLOSING POSTGRES CONNECTION")
+	if cfg.PG.AutoMigrate {
+		if err := migrate(ctx, migrator, l); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
 		}
-	}(conn)
+	}
 
 	userRepo := repository.NewUserRepository(conn)
-	passwordHasher := &util.DefaultPasswordHasher{}
-	userUseCase := user_usecase.NewUserBaseUserCase(userRepo, passwordHasher)
-	v1.NewRouter(handler, *userUseCase)
-	// TODO: Should implements in pkg/httpserver ?
-	handler.Run()
+	refreshTokenRepo := repository.NewRefreshTokenRepository(conn)
+	passwordResetTokenRepo := repository.NewPasswordResetTokenRepository(conn)
+	emailVerificationTokenRepo := repository.NewEmailVerificationTokenRepository(conn)
+	roomRepo := repository.NewRoomRepository(conn)
+	roomMemberRepo := repository.NewRoomMemberRepository(conn)
+	messageRepo := repository.NewMessageRepository(conn)
+	conversationRepo := repository.NewConversationRepository(conn)
+	readReceiptRepo := repository.NewReadReceiptRepository(conn)
+	presenceRepo := repository.NewPresenceRepository(conn)
+	auditLogRepo := repository.NewAuditLogRepository(conn)
+	eventLogRepo := repository.NewEventLogRepository(conn)
+	unitOfWork := repository.NewUnitOfWork(conn)
+	passwordHasher := util.NewTimedPasswordHasher(&util.DefaultPasswordHasher{}, registry)
+	tokenManager := util.NewJWTTokenManager(cfg.Auth.JWTSecret, cfg.Auth.AccessTokenTTL)
+	mailer, err := newMailer(cfg.Mail)
+	if err != nil {
+		return fmt.Errorf("failed to start mailer: %w", err)
+	}
+	loginAttemptStore, err := newLoginAttemptStore(cfg.LoginThrottle, conn)
+	if err != nil {
+		return fmt.Errorf("failed to start login throttle: %w", err)
+	}
+	chatHub := hub.NewHub()
+	eventBus, err := newEventBus(cfg.Bus, conn, cfg.PG, l)
+	if err != nil {
+		return fmt.Errorf("failed to start event bus: %w", err)
+	}
+	defer func() {
+		if err := eventBus.Close(); err != nil {
+			l.Error(ctx, "app - failed to close event bus", "error", err)
+		}
+	}()
+	eventBus.Subscribe(chatHub.Deliver)
+	eventPublisher := bus.NewPublisher(eventBus, eventLogRepo, cfg.Bus.EventRetention, l)
+	userUseCase := user_usecase.NewUserBaseUserCase(userRepo, refreshTokenRepo, passwordResetTokenRepo, emailVerificationTokenRepo, loginAttemptStore, auditLogRepo, passwordHasher, tokenManager, mailer, unitOfWork, eventPublisher, user_usecase.UserSettings{
+		RefreshTokenTTL:            cfg.Auth.RefreshTokenTTL,
+		PasswordResetTTL:           cfg.Auth.PasswordResetTTL,
+		EmailVerificationTTL:       cfg.Auth.EmailVerificationTTL,
+		VerificationResendInterval: cfg.Auth.VerificationResendInterval,
+		VerifyEmailURL:             strings.TrimSuffix(cfg.Mail.LinkBaseURL, "/") + "/api/v1/users/verify-email",
+		RequireVerifiedEmail:       cfg.Auth.RequireVerifiedEmail,
+		AccountLockout:             lockoutPolicy(cfg.LoginThrottle, cfg.LoginThrottle.AccountMaxFailures),
+		IPLockout:                  lockoutPolicy(cfg.LoginThrottle, cfg.LoginThrottle.IPMaxFailures),
+	}, user_usecase.NewMetrics(registry), l)
+	roomUseCase := room_usecase.NewRoomBaseUseCase(roomRepo, roomMemberRepo, userRepo, unitOfWork)
+	messageUseCase := message_usecase.NewMessageBaseUseCase(messageRepo, readReceiptRepo, roomMemberRepo, roomUseCase.AuthorizeRoomUseCase, eventPublisher)
+	conversationUseCase := conversation_usecase.NewConversationBaseUseCase(userRepo, roomRepo, roomMemberRepo, conversationRepo, eventPublisher, unitOfWork)
+	tracker := presence.NewTracker(presenceRepo, conversationRepo, eventPublisher, cfg.Presence.GracePeriod, l)
+	// Each instance counts its own connections, and learns those of the
+	// others over the bus.
+	instanceID, err := util.GenerateSecureToken()
+	if err != nil {
+		return fmt.Errorf("failed to name the instance: %w", err)
+	}
+	tracker.Share(eventBus, instanceID, cfg.Presence.HeartbeatInterval)
+	trackerCtx, stopTracker := context.WithCancel(ctx)
+	trackerDone := make(chan struct{})
+	go func() {
+		defer close(trackerDone)
+		tracker.Run(trackerCtx, cfg.Presence.LastSeenFlushRate)
+	}()
+	// Stopping the tracker flushes the last seen times.
+	defer func() {
+		stopTracker()
+		<-trackerDone
+	}()
+	presenceUseCase := presence_usecase.NewPresenceBaseUseCase(conversationRepo, presenceRepo, tracker)
+	eventUseCase := event_usecase.NewEventBaseUseCase(eventLogRepo)
+	checker := health.NewChecker()
+	checker.Register("database", health.Database(conn))
+	checker.Register("migrations", health.Migrations(migrator))
+	checker.Register("event_bus", func(ctx context.Context) error { return eventBus.Ping() })
+	checker.Register("presence_tracker", health.Running(trackerDone))
+	v1.NewRouter(handler, *userUseCase, *roomUseCase, *messageUseCase, *conversationUseCase, *presenceUseCase, *eventUseCase, chatHub, tracker, checker, registry, l)
+
+	httpServer := httpserver.New(handler,
+		httpserver.Port(cfg.HTTP.Port),
+		httpserver.ReadHeaderTimeout(cfg.HTTP.ReadHeaderTimeout),
+		httpserver.ReadTimeout(cfg.HTTP.ReadTimeout),
+		httpserver.WriteTimeout(cfg.HTTP.WriteTimeout),
+		httpserver.IdleTimeout(cfg.HTTP.IdleTimeout),
+		httpserver.ShutdownTimeout(cfg.HTTP.ShutdownTimeout),
+		// WebSockets are hijacked and event streams never end, so the hub
+		// closes them for the server.
+		httpserver.Drain(chatHub.Shutdown),
+	)
+	if err := httpServer.Start(); err != nil {
+		return fmt.Errorf("failed to start http server: %w", err)
+	}
+	l.Info(ctx, "app - listening", "addr", httpServer.Addr())
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	var stopErr error
+	select {
+	case s := <-interrupt:
+		l.Info(ctx, "app - shutting down", "signal", s.String())
+	case err := <-httpServer.Notify():
+		stopErr = fmt.Errorf("http server stopped: %w", err)
+	}
+
+	// Load balancers see the server not ready and stop routing to it while
+	// it still serves, then in-flight requests are drained.
+	checker.ShutDown()
+	time.Sleep(cfg.HTTP.ShutdownDelay)
+
+	if err := httpServer.Shutdown(); err != nil {
+		l.Error(ctx, "app - failed to shut down http server gracefully", "error", err)
+	}
+	return stopErr
+}
+
+// newEventBus picks how events travel between instances. The in-process bus
+// only suits a single instance.
+func newEventBus(busConfig config.Bus, conn *sql.DB, dbConfig config.PG, l logger.Interface) (domain.EventBusInterface, error) {
+	switch busConfig.Driver {
+	case "memory":
+		return bus.NewInProcessBus(), nil
+	case "postgres":
+		return bus.NewPostgresBus(conn, db.ConnectionString(dbConfig), l)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q", busConfig.Driver)
+	}
+}
+
+// newMailer picks where mails go. Both drivers keep mails local, which suits
+// development and tests.
+func newMailer(mailConfig config.Mail) (util.Mailer, error) {
+	switch mailConfig.Driver {
+	case "log":
+		return util.NewLogMailer(mailConfig.From), nil
+	case "file":
+		return util.NewFileMailer(mailConfig.From, mailConfig.Dir), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", mailConfig.Driver)
+	}
+}
+
+// newLoginAttemptStore picks where failed logins are counted. The memory
+// store only suits a single instance, as each would count its own.
+func newLoginAttemptStore(throttleConfig config.LoginThrottle, conn *sql.DB) (domain.LoginAttemptStoreInterface, error) {
+	switch throttleConfig.Driver {
+	case "memory":
+		return throttle.NewInMemoryStore(), nil
+	case "postgres":
+		return throttle.NewPostgresStore(conn), nil
+	default:
+		return nil, fmt.Errorf("unknown login throttle driver %q", throttleConfig.Driver)
+	}
+}
+
+func lockoutPolicy(throttleConfig config.LoginThrottle, maxFailures int) domain.LockoutPolicy {
+	return domain.LockoutPolicy{
+		MaxFailures:   maxFailures,
+		FailureWindow: throttleConfig.FailureWindow,
+		BaseLockout:   throttleConfig.BaseLockout,
+		MaxLockout:    throttleConfig.MaxLockout,
+	}
+}
+
+func migrate(ctx context.Context, migrator *migration.Migrator, l logger.Interface) error {
+	applied, err := migrator.Up(ctx)
+	if len(applied) > 0 {
+		l.Info(ctx, "app - applied migrations", "migrations", applied)
+	}
+	return err
 }
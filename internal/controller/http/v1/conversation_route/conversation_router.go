@@ -0,0 +1,138 @@
+package conversation_route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/conversation_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type conversationRouter struct {
+	useCase conversation_usecase.ConversationBaseUseCase
+	logger  logger.Interface
+}
+
+type openConversationBody struct {
+	Login string `json:"login" binding:"required"`
+}
+
+type peerResponse struct {
+	ID          int32  `json:"id"`
+	UserName    string `json:"userName"`
+	DisplayName string `json:"displayName"`
+}
+
+type conversationResponse struct {
+	ID                int32         `json:"id"`
+	Kind              string        `json:"kind"`
+	Name              string        `json:"name"`
+	Topic             string        `json:"topic"`
+	Peer              *peerResponse `json:"peer,omitempty"`
+	ArchivedAt        *time.Time    `json:"archivedAt,omitempty"`
+	LastActivityAt    *time.Time    `json:"lastActivityAt,omitempty"`
+	LastReadMessageID int64         `json:"lastReadMessageId"`
+	UnreadCount       int           `json:"unreadCount"`
+	Created           time.Time     `json:"created"`
+}
+
+func NewConversationRoute(handler *gin.RouterGroup, conversationUseCase conversation_usecase.ConversationBaseUseCase, authMiddleware gin.HandlerFunc, l logger.Interface) {
+	h := handler.Group("/conversations", authMiddleware)
+	r := &conversationRouter{useCase: conversationUseCase, logger: l}
+
+	{
+		h.GET("", r.listConversations)
+		h.POST("", r.openDirectConversation)
+	}
+}
+
+func (route *conversationRouter) listConversations(ctx *gin.Context) {
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.useCase.ListConversationsUseCase.Execute(ctx.Request.Context(), conversation_usecase.ListConversationsInput{UserID: user.ID})
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	conversations := make([]conversationResponse, 0, len(output.Conversations))
+	for _, conversation := range output.Conversations {
+		response := toConversationResponse(conversation.Room, conversation.Peer)
+		response.LastActivityAt = &conversation.LastActivityAt
+		response.LastReadMessageID = conversation.LastReadMessageID
+		response.UnreadCount = conversation.UnreadCount
+		conversations = append(conversations, response)
+	}
+	ctx.JSON(http.StatusOK, conversations)
+}
+
+func (route *conversationRouter) openDirectConversation(ctx *gin.Context) {
+	var body openConversationBody
+
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - open a conversation route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind conversation data", err))
+		return
+	}
+
+	output, err := route.useCase.OpenDirectConversationUseCase.Execute(ctx.Request.Context(), conversation_usecase.OpenDirectConversationInput{
+		UserID: user.ID,
+		Login:  body.Login,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	status := http.StatusOK
+	if output.Created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, toConversationResponse(output.Room, output.Peer))
+}
+
+func authenticatedUser(ctx *gin.Context) (*domain.User, bool) {
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+	}
+
+	return user, ok
+}
+
+// Direct conversations are named after the other participant rather than
+// their internal room name.
+func toConversationResponse(room *domain.Room, peer *domain.User) conversationResponse {
+	response := conversationResponse{
+		ID:         room.ID,
+		Kind:       string(room.Kind),
+		Name:       room.Name,
+		Topic:      room.Topic,
+		ArchivedAt: room.ArchivedAt,
+		Created:    room.Created,
+	}
+
+	if peer != nil {
+		response.Peer = &peerResponse{ID: peer.ID, UserName: peer.UserName, DisplayName: peer.DisplayName}
+		response.Name = peer.DisplayName
+		if response.Name == "" {
+			response.Name = peer.UserName
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,112 @@
+package conversation_route
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/routetest"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/conversation_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}
+var roomColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created"}
+var conversationColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created", "last_activity_at", "peer_id", "peer_username", "peer_displayname", "last_read_message_id", "unread_count"}
+
+func newTestContext(method string, url string, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	req, _ := http.NewRequestWithContext(c, method, url, bytes.NewBufferString(body))
+	c.Request = req
+	middleware.SetAuthenticatedUser(c, &domain.User{ID: 1, UserName: "eduardolima806"})
+	return c, rec
+}
+
+func newHandler(db *sql.DB) *conversationRouter {
+	return &conversationRouter{
+		useCase: *conversation_usecase.NewConversationBaseUseCase(repository.NewUserRepository(db), repository.NewRoomRepository(db),
+			repository.NewRoomMemberRepository(db), repository.NewConversationRepository(db), hub.NewHub(), repository.NewUnitOfWork(db)),
+		logger: logger.Discard(),
+	}
+}
+
+func Test_Open_Direct_Conversation(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("conversation bind error", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/conversations", `{}`)
+
+		routetest.Serve(c, newHandler(db).openDirectConversation)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("peer does not exists", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/conversations", `{"login": "nobody"}`)
+
+		mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+		routetest.Serve(c, newHandler(db).openDirectConversation)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("existing conversation is returned", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/conversations", `{"login": "joaquim2019"}`)
+
+		mock.ExpectQuery("SELECT (.+) FROM app_user").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "joaquim2019", "", "joaquim@gmail.com", "hash", "", "", nil, time.Now(), nil))
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE name").
+			WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(7, "dm:1:3", "", "private", "direct", 1, nil, time.Now()))
+
+		routetest.Serve(c, newHandler(db).openDirectConversation)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response conversationResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, int32(7), response.ID)
+		assert.Equal(t, "direct", response.Kind)
+		assert.Equal(t, "joaquim2019", response.Name)
+		assert.Equal(t, int32(3), response.Peer.ID)
+	})
+}
+
+func Test_List_Conversations(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	db, mock, _ := sqlmock.New()
+	c, rec := newTestContext(http.MethodGet, "/conversations", "")
+
+	mock.ExpectQuery("SELECT (.+) FROM room_member me").WillReturnRows(sqlmock.NewRows(conversationColumns).
+		AddRow(7, "dm:1:3", "", "private", "direct", 1, nil, time.Now(), time.Now(), 3, "joaquim2019", "Joaquim", 40, 3).
+		AddRow(5, "general", "Anything goes", "public", "channel", 2, nil, time.Now(), time.Now(), nil, nil, nil, 0, 0))
+
+	routetest.Serve(c, newHandler(db).listConversations)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response []conversationResponse
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, "Joaquim", response[0].Name)
+	assert.NotNil(t, response[0].LastActivityAt)
+	assert.Equal(t, 3, response[0].UnreadCount)
+	assert.Equal(t, "general", response[1].Name)
+	assert.Nil(t, response[1].Peer)
+}
@@ -0,0 +1,183 @@
+package event_route
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/presence"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/event_usecase"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ReplayTruncatedEvent tells a resuming client that it missed more than
+	// can be replayed and must reload its conversations.
+	ReplayTruncatedEvent = "replay.truncated"
+
+	lastEventIDHeader = "Last-Event-ID"
+	lastEventIDQuery  = "lastEventId"
+	keepAlivePeriod   = 15 * time.Second
+)
+
+type eventRouter struct {
+	hub          *hub.Hub
+	tracker      *presence.Tracker
+	eventUseCase event_usecase.EventBaseUseCase
+}
+
+type replayTruncatedPayload struct {
+	LastEventID string `json:"lastEventId"`
+}
+
+func NewEventRoute(handler *gin.RouterGroup, chatHub *hub.Hub, tracker *presence.Tracker, eventUseCase event_usecase.EventBaseUseCase, authMiddleware gin.HandlerFunc) {
+	r := &eventRouter{
+		hub:          chatHub,
+		tracker:      tracker,
+		eventUseCase: eventUseCase,
+	}
+
+	handler.GET("/events", authMiddleware, r.stream)
+}
+
+// stream delivers the same events as the WebSocket as server-sent events.
+// Every event kept in the event log carries its id, so a client that
+// reconnects with Last-Event-ID first gets the events it missed replayed from
+// the log, then the live stream.
+func (route *eventRouter) stream(ctx *gin.Context) {
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+		return
+	}
+
+	lastEventID, resume, ok := lastEventIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	// Subscribe before reading the log, so an event kept in between is at
+	// worst both replayed and published, and the live copy is dropped below.
+	subscriber := route.hub.Subscribe(user.ID)
+	defer route.hub.Unsubscribe(subscriber)
+
+	var replay *event_usecase.ReplayEventsOutput
+	if resume {
+		var err error
+		replay, err = route.eventUseCase.ReplayEventsUseCase.Execute(ctx.Request.Context(), event_usecase.ReplayEventsInput{UserID: user.ID, AfterID: lastEventID})
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+
+	route.tracker.Connect(user.ID)
+	defer route.tracker.Disconnect(user.ID)
+
+	// The stream outlives the read and write timeouts of the server, which
+	// would otherwise cut it off. A client that goes away still ends it
+	// through the request context.
+	controller := http.NewResponseController(ctx.Writer)
+	_ = controller.SetReadDeadline(time.Time{})
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	// Event ids are committed in order, so every live event up to the last
+	// one replayed was already sent.
+	cursor := lastEventID
+	if replay != nil {
+		for _, event := range replay.Events {
+			renderEvent(ctx, event)
+			cursor = event.ID
+		}
+
+		if replay.Truncated {
+			event, _ := domain.NewEvent(ReplayTruncatedEvent, replayTruncatedPayload{LastEventID: strconv.FormatInt(cursor, 10)})
+			renderEvent(ctx, event)
+		}
+	}
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(keepAlivePeriod)
+	defer keepAlive.Stop()
+
+	// The token is only checked when the stream opens, so the stream ends
+	// with it and the client reconnects with a fresh one.
+	var expired <-chan time.Time
+	if expiresAt, ok := middleware.AccessTokenExpiresAt(ctx); ok && !expiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(expiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-expired:
+			return
+		case <-keepAlive.C:
+			// A comment line keeps proxies from timing out an idle stream.
+			if _, err := ctx.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		case data, ok := <-subscriber.Messages():
+			if !ok {
+				return
+			}
+
+			var event domain.Event
+			if err := json.Unmarshal(data, &event); err != nil {
+				continue
+			}
+
+			if event.ID > 0 && event.ID <= cursor {
+				continue
+			}
+
+			renderEvent(ctx, event)
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+func lastEventIDParam(ctx *gin.Context) (int64, bool, bool) {
+	raw := ctx.GetHeader(lastEventIDHeader)
+	if raw == "" {
+		raw = ctx.Query(lastEventIDQuery)
+	}
+	if raw == "" {
+		return 0, false, true
+	}
+
+	lastEventID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || lastEventID < 0 {
+		_ = ctx.Error(domain.NewError(domain.ErrBadRequest, "last event id is not valid"))
+		return 0, false, false
+	}
+
+	return lastEventID, true, true
+}
+
+// renderEvent leaves the id out for events that are not kept, so they do not
+// move the Last-Event-ID of the client.
+func renderEvent(ctx *gin.Context, event domain.Event) {
+	id := ""
+	if event.ID > 0 {
+		id = strconv.FormatInt(event.ID, 10)
+	}
+
+	ctx.Render(-1, sse.Event{Id: id, Event: event.Type, Data: event})
+}
@@ -0,0 +1,281 @@
+package event_route
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/presence"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/event_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var eventColumns = []string{"id", "type", "payload"}
+
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+func newTestServer(t *testing.T, chatHub *hub.Hub, db *sql.DB) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.RenderErrors())
+
+	eventUseCase := event_usecase.NewEventBaseUseCase(repository.NewEventLogRepository(db))
+
+	presenceDb, _, _ := sqlmock.New()
+	tracker := presence.NewTracker(repository.NewPresenceRepository(presenceDb), repository.NewConversationRepository(presenceDb), chatHub, time.Minute, logger.Discard())
+
+	// A ttl query parameter stands for the lifetime left to the access token.
+	authenticated := func(c *gin.Context) {
+		middleware.SetAuthenticatedUser(c, &domain.User{ID: 1, UserName: "eduardolima806"})
+		if ttl, err := time.ParseDuration(c.Query("ttl")); err == nil {
+			middleware.SetAccessTokenExpiresAt(c, time.Now().Add(ttl))
+		}
+	}
+	NewEventRoute(engine.Group("/api/v1"), chatHub, tracker, *eventUseCase, authenticated)
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func expectReplay(mock sqlmock.Sqlmock, afterID int64, rows *sqlmock.Rows) {
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE event_log").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, type, payload FROM event_log").WithArgs(int32(1), afterID, event_usecase.MaxReplayEvents+1).WillReturnRows(rows)
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT COALESCE\\(MIN\\(id\\), 0\\) FROM event_log").WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(1))
+}
+
+func openStream(t *testing.T, server *httptest.Server, lastEventID string) (*http.Response, *bufio.Reader) {
+	return openStreamURL(t, server.URL+"/api/v1/events", lastEventID)
+}
+
+func openStreamURL(t *testing.T, url string, lastEventID string) (*http.Response, *bufio.Reader) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request error: %s", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func readEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read error: %s", err)
+		}
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "":
+			if event.event != "" {
+				return event
+			}
+		case strings.HasPrefix(line, "id:"):
+			event.id = strings.TrimPrefix(line, "id:")
+		case strings.HasPrefix(line, "event:"):
+			event.event = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			event.data = strings.TrimPrefix(line, "data:")
+		}
+	}
+}
+
+// waitForEnd reads the stream until the server ends it.
+func waitForEnd(t *testing.T, reader *bufio.Reader) {
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, reader)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream was not ended")
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition was not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func Test_Stream_Delivers_Live_Events(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	chatHub := hub.NewHub()
+	server := newTestServer(t, chatHub, db)
+
+	resp, reader := openStream(t, server, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitFor(t, func() bool { return chatHub.IsOnline(1) })
+
+	receipt, _ := domain.NewEvent(domain.ReceiptUpdatedEvent, message_usecase.ReceiptPayload{RoomID: 5, UserID: 2, MessageID: 42})
+	chatHub.SendToUsers([]int32{1}, receipt)
+
+	event := readEvent(t, reader)
+	assert.Equal(t, domain.ReceiptUpdatedEvent, event.event)
+	assert.Equal(t, "", event.id)
+	assert.Contains(t, event.data, `"type":"receipt.updated"`)
+	assert.Contains(t, event.data, `"messageId":42`)
+}
+
+func Test_Stream_Resumes_From_Last_Event_Id(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	expectReplay(mock, 40, sqlmock.NewRows(eventColumns).
+		AddRow(41, domain.MessageUpdatedEvent, `{"id":7,"body":"edited"}`).
+		AddRow(42, domain.MessageDeletedEvent, `{"id":8}`).
+		AddRow(43, domain.ReceiptUpdatedEvent, `{"roomId":5,"userId":2,"messageId":7}`))
+
+	chatHub := hub.NewHub()
+	server := newTestServer(t, chatHub, db)
+
+	_, reader := openStream(t, server, "40")
+
+	for _, expected := range []sseEvent{
+		{id: "41", event: domain.MessageUpdatedEvent},
+		{id: "42", event: domain.MessageDeletedEvent},
+		{id: "43", event: domain.ReceiptUpdatedEvent},
+	} {
+		event := readEvent(t, reader)
+		assert.Equal(t, expected.id, event.id)
+		assert.Equal(t, expected.event, event.event)
+		assert.Contains(t, event.data, `"id":`+expected.id)
+	}
+
+	waitFor(t, func() bool { return chatHub.IsOnline(1) })
+
+	// The replayed events arriving live as well are not delivered twice.
+	duplicate := domain.Event{ID: 43, Type: domain.ReceiptUpdatedEvent, Payload: []byte(`{}`)}
+	chatHub.SendToUsers([]int32{1}, duplicate)
+	typing, _ := domain.NewEvent(domain.TypingStartedEvent, map[string]int32{"userId": 2})
+	chatHub.SendToUsers([]int32{1}, typing)
+	live := domain.Event{ID: 44, Type: domain.MessageNewEvent, Payload: []byte(`{"id":9,"body":"live"}`)}
+	chatHub.SendToUsers([]int32{1}, live)
+
+	event := readEvent(t, reader)
+	assert.Equal(t, domain.TypingStartedEvent, event.event)
+	assert.Equal(t, "", event.id)
+
+	event = readEvent(t, reader)
+	assert.Equal(t, "44", event.id)
+	assert.Contains(t, event.data, `"body":"live"`)
+}
+
+func Test_Stream_Resumes_Past_A_Message_Committed_Out_Of_Order(t *testing.T) {
+	// Message 42 took its id before 43 but committed after it, so the event
+	// log holds 43 as event 10 and 42 as event 11. A client that got event 10
+	// before going away still gets message 42 when it resumes.
+	db, mock, _ := sqlmock.New()
+	expectReplay(mock, 10, sqlmock.NewRows(eventColumns).
+		AddRow(11, domain.MessageNewEvent, `{"id":42,"roomId":5,"body":"committed late"}`))
+
+	chatHub := hub.NewHub()
+	server := newTestServer(t, chatHub, db)
+
+	_, reader := openStream(t, server, "10")
+
+	event := readEvent(t, reader)
+	assert.Equal(t, "11", event.id)
+	assert.Equal(t, domain.MessageNewEvent, event.event)
+	assert.Contains(t, event.data, `"id":42`)
+	assert.Contains(t, event.data, `"body":"committed late"`)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Stream_Tells_The_Client_When_The_Replay_Is_Truncated(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	rows := sqlmock.NewRows(eventColumns)
+	for id := 41; id <= 41+event_usecase.MaxReplayEvents; id++ {
+		rows.AddRow(id, domain.MessageNewEvent, `{}`)
+	}
+	expectReplay(mock, 40, rows)
+
+	server := newTestServer(t, hub.NewHub(), db)
+
+	_, reader := openStream(t, server, "40")
+
+	var event sseEvent
+	for i := 0; i <= event_usecase.MaxReplayEvents; i++ {
+		event = readEvent(t, reader)
+	}
+	assert.Equal(t, ReplayTruncatedEvent, event.event)
+	assert.Equal(t, "", event.id)
+	assert.Contains(t, event.data, `"lastEventId":"540"`)
+}
+
+func Test_Stream_Rejects_Invalid_Last_Event_Id(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	server := newTestServer(t, hub.NewHub(), db)
+
+	resp, _ := openStream(t, server, "abc")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_Stream_Ends_When_The_Access_Token_Expires(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	chatHub := hub.NewHub()
+	server := newTestServer(t, chatHub, db)
+
+	resp, reader := openStreamURL(t, server.URL+"/api/v1/events?ttl=100ms", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	waitForEnd(t, reader)
+	waitFor(t, func() bool { return !chatHub.IsOnline(1) })
+}
+
+func Test_Stream_Ends_When_The_Session_Is_Revoked(t *testing.T) {
+	for _, reason := range []string{domain.SessionRevokedByLogout, domain.SessionRevokedByPasswordReset} {
+		t.Run(reason, func(t *testing.T) {
+			db, _, _ := sqlmock.New()
+			chatHub := hub.NewHub()
+			server := newTestServer(t, chatHub, db)
+
+			_, reader := openStream(t, server, "")
+			waitFor(t, func() bool { return chatHub.IsOnline(1) })
+
+			revoked, _ := domain.NewEvent(domain.SessionRevokedEvent, domain.SessionRevokedPayload{Reason: reason})
+			chatHub.Deliver([]int32{1}, revoked)
+
+			event := readEvent(t, reader)
+			assert.Equal(t, domain.SessionRevokedEvent, event.event)
+			assert.Contains(t, event.data, `"reason":"`+reason+`"`)
+			waitForEnd(t, reader)
+		})
+	}
+}
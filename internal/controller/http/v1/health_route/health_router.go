@@ -0,0 +1,41 @@
+package health_route
+
+import (
+	"net/http"
+
+	"github.com/eduardolima806/my-chat-server/internal/infra/health"
+	"github.com/gin-gonic/gin"
+)
+
+type healthRouter struct {
+	checker *health.Checker
+}
+
+type livenessResponse struct {
+	Status string `json:"status"`
+}
+
+// NewHealthRoute registers the probes of orchestrators and load balancers.
+// /healthz tells the process is alive, /readyz that it can serve traffic.
+func NewHealthRoute(handler *gin.RouterGroup, checker *health.Checker) {
+	r := &healthRouter{checker: checker}
+
+	handler.GET("/healthz", r.liveness)
+	// Kept for the clients of the former health endpoint.
+	handler.GET("/health", r.liveness)
+	handler.GET("/readyz", r.readiness)
+}
+
+func (route *healthRouter) liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, livenessResponse{Status: health.StatusUp})
+}
+
+func (route *healthRouter) readiness(ctx *gin.Context) {
+	report := route.checker.Check(ctx.Request.Context())
+
+	status := http.StatusOK
+	if !report.IsReady() {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
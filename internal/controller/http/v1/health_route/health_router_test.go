@@ -0,0 +1,77 @@
+package health_route
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eduardolima806/my-chat-server/internal/infra/health"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveProbe(checker *health.Checker, path string) *httptest.ResponseRecorder {
+	engine := gin.New()
+	NewHealthRoute(&engine.RouterGroup, checker)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_Health_Routes(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("process is alive whatever its dependencies", func(t *testing.T) {
+		checker := health.NewChecker()
+		checker.Register("database", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		rec := serveProbe(checker, "/healthz")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"up"}`, rec.Body.String())
+	})
+
+	t.Run("ready with every dependency up", func(t *testing.T) {
+		checker := health.NewChecker()
+		checker.Register("database", func(ctx context.Context) error { return nil })
+
+		rec := serveProbe(checker, "/readyz")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report health.Report
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusReady, report.Status)
+		assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+	})
+
+	t.Run("not ready with a dependency down", func(t *testing.T) {
+		checker := health.NewChecker()
+		checker.Register("database", func(ctx context.Context) error { return nil })
+		checker.Register("migrations", func(ctx context.Context) error { return errors.New("migrations [13] are pending") })
+
+		rec := serveProbe(checker, "/readyz")
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		var report health.Report
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusNotReady, report.Status)
+		assert.Equal(t, health.StatusDown, report.Checks["migrations"].Status)
+		assert.Equal(t, "migrations [13] are pending", report.Checks["migrations"].Error)
+	})
+
+	t.Run("not ready while shutting down", func(t *testing.T) {
+		checker := health.NewChecker()
+		checker.ShutDown()
+
+		rec := serveProbe(checker, "/readyz")
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"status":"shutting down"}`, rec.Body.String())
+	})
+}
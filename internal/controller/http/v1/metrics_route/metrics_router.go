@@ -0,0 +1,13 @@
+package metrics_route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewMetricsRoute serves the metrics of gatherer to Prometheus compatible
+// scrapers at /metrics.
+func NewMetricsRoute(handler *gin.RouterGroup, gatherer prometheus.Gatherer) {
+	handler.GET("/metrics", gin.WrapH(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})))
+}
@@ -0,0 +1,32 @@
+package metrics_route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Metrics_Route(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	registry := prometheus.NewRegistry()
+	signups := prometheus.NewCounter(prometheus.CounterOpts{Name: "chat_signups_total", Help: "Accounts created."})
+	registry.MustRegister(signups)
+	signups.Inc()
+
+	engine := gin.New()
+	NewMetricsRoute(&engine.RouterGroup, registry)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	engine.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	assert.Equal(t, "# HELP chat_signups_total Accounts created.\n# TYPE chat_signups_total counter\nchat_signups_total 1\n", rec.Body.String())
+}
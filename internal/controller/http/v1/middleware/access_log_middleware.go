@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog logs every request once it is answered, along with the error
+// behind a server error. Only the path is logged, as query strings may carry
+// tokens.
+func AccessLog(l logger.Interface) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		args := []any{
+			"method", ctx.Request.Method,
+			"route", ctx.FullPath(),
+			"path", ctx.Request.URL.Path,
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip", ctx.ClientIP(),
+		}
+
+		// The request context carries the request id, and the user id once
+		// authenticated.
+		requestCtx := ctx.Request.Context()
+		switch {
+		case status >= http.StatusInternalServerError:
+			if len(ctx.Errors) > 0 {
+				args = append(args, "error", ctx.Errors.Last().Error())
+			}
+			l.Error(requestCtx, "http request", args...)
+		case status >= http.StatusBadRequest:
+			l.Warn(requestCtx, "http request", args...)
+		default:
+			l.Info(requestCtx, "http request", args...)
+		}
+	}
+}
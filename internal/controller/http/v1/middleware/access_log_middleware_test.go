@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveAccessLog(t *testing.T, path string) map[string]any {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+
+	engine := gin.New()
+	engine.Use(RequestID(), AccessLog(logger.New("info", &out)), RenderErrors())
+	engine.GET("/users/:id", func(ctx *gin.Context) {
+		// Stands in for the auth middleware.
+		ctx.Request = ctx.Request.WithContext(logger.WithAttrs(ctx.Request.Context(), slog.Int("user_id", 7)))
+		ctx.Status(http.StatusNoContent)
+	})
+	engine.GET("/broken", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("pq: connection refused"))
+	})
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	engine.ServeHTTP(rec, req)
+
+	var entry map[string]any
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &entry))
+	return entry
+}
+
+func Test_Access_Log_Describes_The_Request(t *testing.T) {
+	entry := serveAccessLog(t, "/users/7?token=abc")
+
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "http request", entry["msg"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/users/:id", entry["route"])
+	assert.Equal(t, "/users/7", entry["path"])
+	assert.Equal(t, float64(http.StatusNoContent), entry["status"])
+	assert.Contains(t, entry, "latency_ms")
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, float64(7), entry["user_id"])
+}
+
+func Test_Access_Log_Reports_Server_Errors(t *testing.T) {
+	entry := serveAccessLog(t, "/broken")
+
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, float64(http.StatusInternalServerError), entry["status"])
+	assert.Equal(t, "pq: connection refused", entry["error"])
+	assert.NotContains(t, entry, "user_id")
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/user_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+	accessTokenQuery    = "access_token"
+	authUserKey         = "authUser"
+	tokenExpiresAtKey   = "authTokenExpiresAt"
+)
+
+// Authenticate verifies the bearer access token of the request and stores the
+// authenticated user on the gin context, with the expiry of the token,
+// aborting with 401 otherwise.
+func Authenticate(authUseCase user_usecase.AuthenticateUserUseCaseInterface) gin.HandlerFunc {
+	return authenticate(authUseCase, false)
+}
+
+// AuthenticateWebSocket behaves like Authenticate but also accepts the token in
+// the access_token query parameter, since browsers cannot set headers on a
+// WebSocket handshake or an EventSource request.
+func AuthenticateWebSocket(authUseCase user_usecase.AuthenticateUserUseCaseInterface) gin.HandlerFunc {
+	return authenticate(authUseCase, true)
+}
+
+func authenticate(authUseCase user_usecase.AuthenticateUserUseCaseInterface, allowQueryToken bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		accessToken, ok := extractBearerToken(ctx.GetHeader(authorizationHeader))
+
+		if !ok && allowQueryToken {
+			accessToken = ctx.Query(accessTokenQuery)
+			ok = accessToken != ""
+		}
+
+		if !ok {
+			err := domain.NewError(domain.ErrUnauthorized, "missing bearer token")
+			_ = ctx.AbortWithError(domain.GetHttpStatusCode(err), err)
+			return
+		}
+
+		output, err := authUseCase.Execute(ctx.Request.Context(), accessToken)
+
+		if err != nil {
+			_ = ctx.AbortWithError(domain.GetHttpStatusCode(err), err)
+			return
+		}
+
+		SetAuthenticatedUser(ctx, output.User)
+		SetAccessTokenExpiresAt(ctx, output.AccessTokenExpiresAt)
+		ctx.Request = ctx.Request.WithContext(logger.WithAttrs(ctx.Request.Context(), slog.Int("user_id", int(output.User.ID))))
+		ctx.Next()
+	}
+}
+
+func SetAuthenticatedUser(ctx *gin.Context, user *domain.User) {
+	ctx.Set(authUserKey, user)
+}
+
+// AuthenticatedUser returns the user stored by Authenticate.
+func AuthenticatedUser(ctx *gin.Context) (*domain.User, bool) {
+	value, exists := ctx.Get(authUserKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*domain.User)
+	return user, ok
+}
+
+func SetAccessTokenExpiresAt(ctx *gin.Context, expiresAt time.Time) {
+	ctx.Set(tokenExpiresAtKey, expiresAt)
+}
+
+// AccessTokenExpiresAt returns when the token checked by Authenticate
+// expires. Connections that outlive the request must not outlive it.
+func AccessTokenExpiresAt(ctx *gin.Context) (time.Time, bool) {
+	value, exists := ctx.Get(tokenExpiresAtKey)
+	if !exists {
+		return time.Time{}, false
+	}
+	expiresAt, ok := value.(time.Time)
+	return expiresAt, ok
+}
+
+func extractBearerToken(header string) (string, bool) {
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
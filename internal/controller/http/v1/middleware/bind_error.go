@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Validation errors name fields after their JSON keys, which is what
+	// clients sent.
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// BindError describes why a request could not be bound, listing every field
+// that failed validation. message says what was being bound.
+func BindError(message string, err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return domain.NewError(domain.ErrBadRequest, fmt.Sprintf("%s: %s", message, err.Error()))
+	}
+
+	fields := make([]domain.FieldError, 0, len(validationErrs))
+	messages := make([]string, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		field := domain.FieldError{Field: fieldErr.Field(), Message: validationMessage(fieldErr)}
+		fields = append(fields, field)
+		messages = append(messages, field.Field+" "+field.Message)
+	}
+
+	return domain.NewFieldError(domain.ErrBadRequest, fmt.Sprintf("%s: %s", message, strings.Join(messages, ", ")), fields...)
+}
+
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	default:
+		return fmt.Sprintf("failed on the %s rule", fieldErr.Tag())
+	}
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
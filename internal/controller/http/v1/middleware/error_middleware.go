@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+// RenderErrors answers with the problem details of the last error a handler
+// attached with ctx.Error, unless a response was already written. Handlers
+// and middlewares report failures that way and return, so every route
+// answers errors with the same body. AccessLog logs the errors behind server
+// errors.
+func RenderErrors() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		problem := domain.NewProblem(ctx.Errors.Last().Err)
+		problem.Instance = ctx.Request.URL.Path
+
+		ctx.Header("Content-Type", problemContentType)
+		ctx.JSON(problem.Status, problem)
+	}
+}
+
+// RouteNotFound makes unknown routes answer with problem details too.
+func RouteNotFound(ctx *gin.Context) {
+	_ = ctx.Error(domain.NewError(domain.ErrNotFound, "route not found"))
+}
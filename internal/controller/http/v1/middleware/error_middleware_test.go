@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type messageBody struct {
+	Body      string `json:"body" binding:"required"`
+	MessageID int64  `json:"messageId" binding:"gt=0"`
+}
+
+func newTestEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RenderErrors())
+	engine.NoRoute(RouteNotFound)
+
+	engine.GET("/rooms/:id", func(ctx *gin.Context) {
+		_ = ctx.Error(domain.NewError(domain.ErrNotFound, `room "general" not found`))
+	})
+	engine.GET("/broken", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("pq: connection refused"))
+	})
+	engine.POST("/messages", func(ctx *gin.Context) {
+		var body messageBody
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			_ = ctx.Error(BindError("Error to bind message data", err))
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+
+	return engine
+}
+
+func serveProblem(t *testing.T, method string, url string, body string) (*httptest.ResponseRecorder, domain.Problem) {
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+	newTestEngine().ServeHTTP(rec, req)
+
+	var problem domain.Problem
+	if rec.Code >= http.StatusBadRequest {
+		assert.Equal(t, problemContentType, rec.Header().Get("Content-Type"))
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	}
+	return rec, problem
+}
+
+func Test_Domain_Errors_Are_Rendered_As_Problems(t *testing.T) {
+	rec, problem := serveProblem(t, http.MethodGet, "/rooms/5", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, domain.Problem{
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Code:     domain.ErrNotFound.Error(),
+		Detail:   `room "general" not found`,
+		Instance: "/rooms/5",
+	}, problem)
+}
+
+func Test_Unexpected_Errors_Are_Hidden(t *testing.T) {
+	rec, problem := serveProblem(t, http.MethodGet, "/broken", "")
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal server error", problem.Detail)
+}
+
+func Test_Unknown_Routes_Are_Rendered_As_Problems(t *testing.T) {
+	rec, problem := serveProblem(t, http.MethodGet, "/nowhere", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, domain.ErrNotFound.Error(), problem.Code)
+}
+
+func Test_Bind_Errors_Name_The_Invalid_Fields(t *testing.T) {
+	t.Run("validation error", func(t *testing.T) {
+		rec, problem := serveProblem(t, http.MethodPost, "/messages", `{"messageId": -1}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "Error to bind message data: body is required, messageId must be greater than 0", problem.Detail)
+		assert.Equal(t, []domain.FieldError{
+			{Field: "body", Message: "is required"},
+			{Field: "messageId", Message: "must be greater than 0"},
+		}, problem.Errors)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		rec, problem := serveProblem(t, http.MethodPost, "/messages", `{"body": `)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "Error to bind message data: unexpected EOF", problem.Detail)
+		assert.Empty(t, problem.Errors)
+	})
+
+	t.Run("valid body", func(t *testing.T) {
+		rec, _ := serveProblem(t, http.MethodPost, "/messages", `{"body": "hello", "messageId": 1}`)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
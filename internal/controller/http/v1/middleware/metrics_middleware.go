@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels the requests no route matched, so that scanning
+// arbitrary paths does not create a series per path.
+const unmatchedRoute = "unmatched"
+
+// Metrics counts the requests answered and observes their latency, per route
+// as registered rather than per path.
+func Metrics(registerer prometheus.Registerer) gin.HandlerFunc {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chat_http_requests_total",
+		Help: "HTTP requests answered, by method, route and status.",
+	}, []string{"method", "route", "status"})
+	latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chat_http_request_duration_seconds",
+		Help:    "Time taken to answer HTTP requests, by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	registerer.MustRegister(requests, latency)
+
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		requests.WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+		latency.WithLabelValues(ctx.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Requests_Are_Counted_Per_Route(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := prometheus.NewRegistry()
+
+	engine := gin.New()
+	engine.Use(Metrics(registry), RenderErrors())
+	engine.NoRoute(RouteNotFound)
+	engine.GET("/rooms/:id", func(ctx *gin.Context) {
+		if ctx.Param("id") == "0" {
+			_ = ctx.Error(domain.NewError(domain.ErrNotFound, "room not found"))
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+
+	for _, path := range []string{"/rooms/1", "/rooms/2", "/rooms/0", "/wp-admin"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	scrape := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(scrape, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	exposition := scrape.Body.String()
+
+	assert.Contains(t, exposition, `chat_http_requests_total{method="GET",route="/rooms/:id",status="204"} 2`)
+	assert.Contains(t, exposition, `chat_http_requests_total{method="GET",route="/rooms/:id",status="404"} 1`)
+	assert.Contains(t, exposition, `chat_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, exposition, `chat_http_request_duration_seconds_count{method="GET",route="/rooms/:id"} 3`)
+	assert.Contains(t, exposition, `chat_http_request_duration_seconds_bucket{method="GET",route="unmatched",le="+Inf"} 1`)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Recover turns a panicking handler into an internal server error, logging
+// the panic with its stack.
+func Recover(l logger.Interface) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				l.Error(ctx.Request.Context(), "http - v1 - panic recovered", "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+				ctx.Abort()
+				_ = ctx.Error(domain.NewError(domain.ErrInternalServerError, "internal server error"))
+			}
+		}()
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Panic_Is_Rendered_As_Internal_Server_Error(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+
+	engine := gin.New()
+	engine.Use(RenderErrors(), Recover(logger.New("info", &out)))
+	engine.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/panic", nil)
+	engine.ServeHTTP(rec, req)
+
+	var problem domain.Problem
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+
+	var entry map[string]any
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "boom", entry["panic"])
+	assert.Contains(t, entry["stack"], "recovery_middleware")
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID keeps ids sent by clients or proxies from injecting
+// anything odd into logs and response headers.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID correlates the log entries of a request. It keeps the
+// X-Request-ID the request came with, such as one set by a proxy, or assigns
+// one, echoes it in the response and adds it to the request context.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Request = ctx.Request.WithContext(logger.WithAttrs(ctx.Request.Context(), slog.String("request_id", requestID)))
+		ctx.Next()
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
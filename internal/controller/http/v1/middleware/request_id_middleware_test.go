@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithRequestID(t *testing.T, requestID string) (*httptest.ResponseRecorder, map[string]any) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+	l := logger.New("debug", &out)
+
+	engine := gin.New()
+	engine.Use(RequestID())
+	engine.GET("/ping", func(ctx *gin.Context) {
+		l.Info(ctx.Request.Context(), "pong")
+		ctx.Status(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/ping", nil)
+	if requestID != "" {
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+	engine.ServeHTTP(rec, req)
+
+	var entry map[string]any
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &entry))
+	return rec, entry
+}
+
+func Test_Request_ID_Is_Kept_From_The_Request(t *testing.T) {
+	rec, entry := serveWithRequestID(t, "edge-42.a_b")
+
+	assert.Equal(t, "edge-42.a_b", rec.Header().Get(RequestIDHeader))
+	assert.Equal(t, "edge-42.a_b", entry["request_id"])
+}
+
+func Test_Request_ID_Is_Assigned_When_Missing(t *testing.T) {
+	rec, entry := serveWithRequestID(t, "")
+
+	requestID := rec.Header().Get(RequestIDHeader)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, requestID)
+	assert.Equal(t, requestID, entry["request_id"])
+}
+
+func Test_Invalid_Request_ID_Is_Replaced(t *testing.T) {
+	rec, entry := serveWithRequestID(t, "bad id\" injected")
+
+	requestID := rec.Header().Get(RequestIDHeader)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, requestID)
+	assert.Equal(t, requestID, entry["request_id"])
+}
@@ -0,0 +1,52 @@
+package presence_route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/presence_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type presenceRouter struct {
+	useCase presence_usecase.PresenceBaseUseCase
+}
+
+type presenceResponse struct {
+	UserID   int32      `json:"userId"`
+	Status   string     `json:"status"`
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+func NewPresenceRoute(handler *gin.RouterGroup, presenceUseCase presence_usecase.PresenceBaseUseCase, authMiddleware gin.HandlerFunc) {
+	h := handler.Group("/presence", authMiddleware)
+	r := &presenceRouter{useCase: presenceUseCase}
+
+	{
+		h.GET("", r.listPresence)
+	}
+}
+
+func (route *presenceRouter) listPresence(ctx *gin.Context) {
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+		return
+	}
+
+	output, err := route.useCase.ListPresenceUseCase.Execute(ctx.Request.Context(), presence_usecase.ListPresenceInput{UserID: user.ID})
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	presences := make([]presenceResponse, 0, len(output.Presences))
+	for _, presence := range output.Presences {
+		presences = append(presences, presenceResponse{UserID: presence.UserID, Status: string(presence.Status), LastSeen: presence.LastSeen})
+	}
+	ctx.JSON(http.StatusOK, presences)
+}
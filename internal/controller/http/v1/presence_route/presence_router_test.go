@@ -0,0 +1,63 @@
+package presence_route
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/routetest"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/presence"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/presence_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_List_Presence(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("user is not authenticated", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/presence", nil)
+
+		tracker := presence.NewTracker(repository.NewPresenceRepository(db), repository.NewConversationRepository(db), hub.NewHub(), time.Minute, logger.Discard())
+		route := &presenceRouter{useCase: *presence_usecase.NewPresenceBaseUseCase(repository.NewConversationRepository(db), repository.NewPresenceRepository(db), tracker)}
+		routetest.Serve(c, route.listPresence)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("presence of contacts is listed", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/presence", nil)
+		middleware.SetAuthenticatedUser(c, &domain.User{ID: 1, UserName: "eduardolima806"})
+
+		mock.ExpectQuery("SELECT DISTINCT other.user_id FROM room_member").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+		mock.ExpectQuery("SELECT DISTINCT other.user_id FROM room_member").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+
+		tracker := presence.NewTracker(repository.NewPresenceRepository(db), repository.NewConversationRepository(db), hub.NewHub(), time.Minute, logger.Discard())
+		tracker.Connect(2)
+
+		route := &presenceRouter{useCase: *presence_usecase.NewPresenceBaseUseCase(repository.NewConversationRepository(db), repository.NewPresenceRepository(db), tracker)}
+		routetest.Serve(c, route.listPresence)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response []presenceResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, "online", response[0].Status)
+		assert.NotNil(t, response[0].LastSeen)
+	})
+}
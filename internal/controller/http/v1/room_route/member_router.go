@@ -0,0 +1,204 @@
+package room_route
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type inviteMemberBody struct {
+	Login string `json:"login" binding:"required"`
+}
+
+type changeMemberRoleBody struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type memberResponse struct {
+	UserID      int32     `json:"userId"`
+	UserName    string    `json:"userName"`
+	DisplayName string    `json:"displayName"`
+	Role        string    `json:"role"`
+	Status      string    `json:"status"`
+	InvitedBy   *int32    `json:"invitedBy,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
+func (route *roomRouter) joinRoom(ctx *gin.Context) {
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.useCase.JoinRoomUseCase.Execute(ctx.Request.Context(), input)
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toMemberResponse(output.Member))
+	}
+}
+
+func (route *roomRouter) leaveRoom(ctx *gin.Context) {
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	if err := route.useCase.LeaveRoomUseCase.Execute(ctx.Request.Context(), input); err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func (route *roomRouter) listMembers(ctx *gin.Context) {
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.useCase.ListMembersUseCase.Execute(ctx.Request.Context(), input)
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	members := make([]memberResponse, 0, len(output.Members))
+	for _, member := range output.Members {
+		members = append(members, toMemberResponse(member))
+	}
+	ctx.JSON(http.StatusOK, members)
+}
+
+func (route *roomRouter) inviteMember(ctx *gin.Context) {
+	var body inviteMemberBody
+
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - invite a room member route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind invite data", err))
+		return
+	}
+
+	output, err := route.useCase.InviteMemberUseCase.Execute(ctx.Request.Context(), room_usecase.InviteMemberInput{
+		UserID: input.UserID,
+		RoomID: input.RoomID,
+		Login:  body.Login,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusCreated, toMemberResponse(output.Member))
+	}
+}
+
+func (route *roomRouter) changeMemberRole(ctx *gin.Context) {
+	var body changeMemberRoleBody
+
+	input, ok := moderateMemberInput(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - change a room member role route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind member data", err))
+		return
+	}
+
+	output, err := route.useCase.ChangeMemberRoleUseCase.Execute(ctx.Request.Context(), room_usecase.ChangeMemberRoleInput{
+		ModerateMemberInput: input,
+		Role:                body.Role,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toMemberResponse(output.Member))
+	}
+}
+
+func (route *roomRouter) kickMember(ctx *gin.Context) {
+	input, ok := moderateMemberInput(ctx)
+	if !ok {
+		return
+	}
+
+	if err := route.useCase.KickMemberUseCase.Execute(ctx.Request.Context(), input); err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func (route *roomRouter) banMember(ctx *gin.Context) {
+	input, ok := moderateMemberInput(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.useCase.BanMemberUseCase.Execute(ctx.Request.Context(), input)
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toMemberResponse(output.Member))
+	}
+}
+
+func membershipInput(ctx *gin.Context) (room_usecase.RoomMembershipInput, bool) {
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return room_usecase.RoomMembershipInput{}, false
+	}
+
+	roomID, ok := roomIDParam(ctx)
+	if !ok {
+		return room_usecase.RoomMembershipInput{}, false
+	}
+
+	return room_usecase.RoomMembershipInput{UserID: user.ID, RoomID: roomID}, true
+}
+
+func moderateMemberInput(ctx *gin.Context) (room_usecase.ModerateMemberInput, bool) {
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return room_usecase.ModerateMemberInput{}, false
+	}
+
+	targetUserID, err := strconv.ParseInt(ctx.Param("userId"), 10, 32)
+	if err != nil || targetUserID <= 0 {
+		_ = ctx.Error(domain.NewError(domain.ErrBadRequest, "user id is not valid"))
+		return room_usecase.ModerateMemberInput{}, false
+	}
+
+	return room_usecase.ModerateMemberInput{
+		UserID:       input.UserID,
+		RoomID:       input.RoomID,
+		TargetUserID: int32(targetUserID),
+	}, true
+}
+
+func toMemberResponse(member *domain.RoomMember) memberResponse {
+	return memberResponse{
+		UserID:      member.UserID,
+		UserName:    member.UserName,
+		DisplayName: member.DisplayName,
+		Role:        string(member.Role),
+		Status:      string(member.Status),
+		InvitedBy:   member.InvitedBy,
+		Created:     member.Created,
+	}
+}
@@ -0,0 +1,109 @@
+package room_route
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/routetest"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Room_Membership(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("user joins public room", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/join", "", gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+		mock.ExpectExec("INSERT INTO room_member").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		routetest.Serve(c, newHandler(db).joinRoom)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response memberResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, int32(1), response.UserID)
+		assert.Equal(t, "member", response.Role)
+		assert.Equal(t, "active", response.Status)
+	})
+
+	t.Run("private room without invite is not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/join", "", gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+
+		routetest.Serve(c, newHandler(db).joinRoom)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("member leaves room", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, _ := newTestContext(http.MethodPost, "/rooms/5/leave", "", gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+		mock.ExpectExec("DELETE FROM room_member").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		routetest.Serve(c, newHandler(db).leaveRoom)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+	})
+
+	t.Run("invite bind error", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/invites", `{}`, gin.Params{{Key: "id", Value: "5"}})
+
+		routetest.Serve(c, newHandler(db).inviteMember)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("member id invalid", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/members/abc/kick", "", gin.Params{{Key: "id", Value: "5"}, {Key: "userId", Value: "abc"}})
+
+		routetest.Serve(c, newHandler(db).kickMember)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("member cannot kick", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/members/3/kick", "", gin.Params{{Key: "id", Value: "5"}, {Key: "userId", Value: "3"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+
+		routetest.Serve(c, newHandler(db).kickMember)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("owner bans member", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/members/3/ban", "", gin.Params{{Key: "id", Value: "5"}, {Key: "userId", Value: "3"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "owner", "active"))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(3, "member", "active"))
+		mock.ExpectExec("INSERT INTO room_member").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		routetest.Serve(c, newHandler(db).banMember)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response memberResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "banned", response.Status)
+	})
+}
@@ -0,0 +1,203 @@
+package room_route
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type sendMessageBody struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type messageResponse struct {
+	ID         int64      `json:"id"`
+	RoomID     int32      `json:"roomId"`
+	SenderID   int32      `json:"senderId"`
+	SenderName string     `json:"senderName"`
+	Body       string     `json:"body"`
+	EditedAt   *time.Time `json:"editedAt,omitempty"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty"`
+	Created    time.Time  `json:"created"`
+}
+
+type messageEditResponse struct {
+	ID       int64     `json:"id"`
+	Body     string    `json:"body"`
+	EditedBy int32     `json:"editedBy"`
+	Created  time.Time `json:"created"`
+}
+
+type messagePageResponse struct {
+	Messages []messageResponse `json:"messages"`
+	Before   string            `json:"before,omitempty"`
+	After    string            `json:"after,omitempty"`
+}
+
+func (route *roomRouter) sendMessage(ctx *gin.Context) {
+	var body sendMessageBody
+
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - send a room message route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind message data", err))
+		return
+	}
+
+	output, err := route.messageUseCase.SendMessageUseCase.Execute(ctx.Request.Context(), message_usecase.SendMessageInput{
+		UserID: input.UserID,
+		RoomID: input.RoomID,
+		Body:   body.Body,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusCreated, toMessageResponse(output.Message))
+	}
+}
+
+func (route *roomRouter) listMessages(ctx *gin.Context) {
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if rawLimit := ctx.Query("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil {
+			_ = ctx.Error(domain.NewError(domain.ErrBadRequest, "limit is not valid"))
+			return
+		}
+		limit = parsed
+	}
+
+	output, err := route.messageUseCase.ListMessagesUseCase.Execute(ctx.Request.Context(), message_usecase.ListMessagesInput{
+		UserID: input.UserID,
+		RoomID: input.RoomID,
+		Before: ctx.Query("before"),
+		After:  ctx.Query("after"),
+		Limit:  limit,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	messages := make([]messageResponse, 0, len(output.Messages))
+	for _, message := range output.Messages {
+		messages = append(messages, toMessageResponse(message))
+	}
+	ctx.JSON(http.StatusOK, messagePageResponse{Messages: messages, Before: output.Before, After: output.After})
+}
+
+func (route *roomRouter) editMessage(ctx *gin.Context) {
+	var body sendMessageBody
+
+	input, messageID, ok := messageInput(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - edit a room message route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind message data", err))
+		return
+	}
+
+	output, err := route.messageUseCase.EditMessageUseCase.Execute(ctx.Request.Context(), message_usecase.EditMessageInput{
+		UserID:    input.UserID,
+		RoomID:    input.RoomID,
+		MessageID: messageID,
+		Body:      body.Body,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toMessageResponse(output.Message))
+	}
+}
+
+func (route *roomRouter) deleteMessage(ctx *gin.Context) {
+	input, messageID, ok := messageInput(ctx)
+	if !ok {
+		return
+	}
+
+	err := route.messageUseCase.DeleteMessageUseCase.Execute(ctx.Request.Context(), message_usecase.DeleteMessageInput{
+		UserID:    input.UserID,
+		RoomID:    input.RoomID,
+		MessageID: messageID,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func (route *roomRouter) listMessageEdits(ctx *gin.Context) {
+	input, messageID, ok := messageInput(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.messageUseCase.ListMessageEditsUseCase.Execute(ctx.Request.Context(), message_usecase.ListMessageEditsInput{
+		UserID:    input.UserID,
+		RoomID:    input.RoomID,
+		MessageID: messageID,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	edits := make([]messageEditResponse, 0, len(output.Edits))
+	for _, edit := range output.Edits {
+		edits = append(edits, messageEditResponse{ID: edit.ID, Body: edit.Body, EditedBy: edit.EditedBy, Created: edit.Created})
+	}
+	ctx.JSON(http.StatusOK, edits)
+}
+
+func messageInput(ctx *gin.Context) (room_usecase.RoomMembershipInput, int64, bool) {
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return input, 0, false
+	}
+
+	messageID, err := strconv.ParseInt(ctx.Param("messageId"), 10, 64)
+	if err != nil || messageID <= 0 {
+		_ = ctx.Error(domain.NewError(domain.ErrBadRequest, "message id is not valid"))
+		return input, 0, false
+	}
+
+	return input, messageID, true
+}
+
+func toMessageResponse(message *domain.Message) messageResponse {
+	return messageResponse{
+		ID:         message.ID,
+		RoomID:     message.RoomID,
+		SenderID:   message.SenderID,
+		SenderName: message.SenderName,
+		Body:       message.Body,
+		EditedAt:   message.EditedAt,
+		DeletedAt:  message.DeletedAt,
+		Created:    message.Created,
+	}
+}
@@ -0,0 +1,135 @@
+package room_route
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/routetest"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var messageColumns = []string{"id", "room_id", "sender_id", "sender_name", "body", "edited_at", "deleted_at", "created"}
+
+func Test_Room_Messages(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("message bind error", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/messages", `{}`, gin.Params{{Key: "id", Value: "5"}})
+
+		routetest.Serve(c, newHandler(db).sendMessage)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("message is sent", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/messages", `{"body": "hello"}`, gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+		mock.ExpectQuery("INSERT INTO message").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+
+		routetest.Serve(c, newHandler(db).sendMessage)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var response messageResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, int64(42), response.ID)
+		assert.Equal(t, "hello", response.Body)
+	})
+
+	t.Run("limit invalid", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodGet, "/rooms/5/messages?limit=abc", "", gin.Params{{Key: "id", Value: "5"}})
+
+		routetest.Serve(c, newHandler(db).listMessages)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("cursor invalid", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodGet, "/rooms/5/messages?before=garbage", "", gin.Params{{Key: "id", Value: "5"}})
+
+		routetest.Serve(c, newHandler(db).listMessages)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("history is paged", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodGet, "/rooms/5/messages?limit=1", "", gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+		mock.ExpectQuery("SELECT (.+) FROM message").WithArgs(int32(5), 2).WillReturnRows(sqlmock.NewRows(messageColumns).
+			AddRow(12, 5, 1, "Eduardo Lima", "second", nil, nil, time.Now()).
+			AddRow(11, 5, 2, "joaquim2019", "first", nil, nil, time.Now()))
+
+		routetest.Serve(c, newHandler(db).listMessages)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response messagePageResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response.Messages, 1)
+		assert.Equal(t, int64(12), response.Messages[0].ID)
+		assert.NotEmpty(t, response.Before)
+		assert.NotEmpty(t, response.After)
+	})
+}
+
+func Test_Room_Message_Lifecycle(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("message id invalid", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodDelete, "/rooms/5/messages/abc", "", gin.Params{{Key: "id", Value: "5"}, {Key: "messageId", Value: "abc"}})
+
+		routetest.Serve(c, newHandler(db).deleteMessage)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("message is edited", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPatch, "/rooms/5/messages/42", `{"body": "hello, world"}`, gin.Params{{Key: "id", Value: "5"}, {Key: "messageId", Value: "42"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+		mock.ExpectQuery("SELECT (.+) FROM message m").WillReturnRows(sqlmock.NewRows(messageColumns).AddRow(42, 5, 1, "Eduardo Lima", "hello", nil, nil, time.Now()))
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO message_edit").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE message SET body").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+
+		routetest.Serve(c, newHandler(db).editMessage)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response messageResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "hello, world", response.Body)
+		assert.NotNil(t, response.EditedAt)
+	})
+
+	t.Run("someone else message cannot be deleted by member", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodDelete, "/rooms/5/messages/42", "", gin.Params{{Key: "id", Value: "5"}, {Key: "messageId", Value: "42"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+		mock.ExpectQuery("SELECT (.+) FROM message m").WillReturnRows(sqlmock.NewRows(messageColumns).AddRow(42, 5, 3, "joaquim2019", "hello", nil, nil, time.Now()))
+
+		routetest.Serve(c, newHandler(db).deleteMessage)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,81 @@
+package room_route
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type markAsReadBody struct {
+	MessageID int64 `json:"messageId" binding:"required,gt=0"`
+}
+
+type receiptResponse struct {
+	RoomID    int32     `json:"roomId"`
+	UserID    int32     `json:"userId"`
+	MessageID int64     `json:"messageId"`
+	ReadAt    time.Time `json:"readAt"`
+}
+
+func (route *roomRouter) markAsRead(ctx *gin.Context) {
+	var body markAsReadBody
+
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - mark a room as read route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind read receipt data", err))
+		return
+	}
+
+	output, err := route.messageUseCase.MarkAsReadUseCase.Execute(ctx.Request.Context(), message_usecase.MarkAsReadInput{
+		UserID:    input.UserID,
+		RoomID:    input.RoomID,
+		MessageID: body.MessageID,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toReceiptResponse(output.Receipt))
+	}
+}
+
+func (route *roomRouter) listReceipts(ctx *gin.Context) {
+	input, ok := membershipInput(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.messageUseCase.ListReceiptsUseCase.Execute(ctx.Request.Context(), message_usecase.ListReceiptsInput{
+		UserID: input.UserID,
+		RoomID: input.RoomID,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	receipts := make([]receiptResponse, 0, len(output.Receipts))
+	for _, receipt := range output.Receipts {
+		receipts = append(receipts, toReceiptResponse(receipt))
+	}
+	ctx.JSON(http.StatusOK, receipts)
+}
+
+func toReceiptResponse(receipt *domain.ReadReceipt) receiptResponse {
+	return receiptResponse{
+		RoomID:    receipt.RoomID,
+		UserID:    receipt.UserID,
+		MessageID: receipt.MessageID,
+		ReadAt:    receipt.Updated,
+	}
+}
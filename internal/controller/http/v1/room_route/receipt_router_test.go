@@ -0,0 +1,63 @@
+package room_route
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/routetest"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Room_Read_Receipts(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("read receipt bind error", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/read", `{"messageId": 0}`, gin.Params{{Key: "id", Value: "5"}})
+
+		routetest.Serve(c, newHandler(db).markAsRead)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("room is marked as read", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/read", `{"messageId": 42}`, gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+		mock.ExpectQuery("SELECT (.+) FROM message m").WillReturnRows(sqlmock.NewRows(messageColumns).AddRow(42, 5, 2, "Joaquim", "hello", nil, nil, time.Now()))
+		mock.ExpectExec("INSERT INTO read_receipt").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(2, "member", "active"))
+
+		routetest.Serve(c, newHandler(db).markAsRead)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response receiptResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, int32(1), response.UserID)
+		assert.Equal(t, int64(42), response.MessageID)
+	})
+
+	t.Run("receipts are listed", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodGet, "/rooms/5/receipts", "", gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+		mock.ExpectQuery("SELECT (.+) FROM read_receipt").WillReturnRows(sqlmock.NewRows([]string{"room_id", "user_id", "message_id", "updated"}).AddRow(5, 2, 42, time.Now()))
+
+		routetest.Serve(c, newHandler(db).listReceipts)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response []receiptResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, int32(2), response[0].UserID)
+	})
+}
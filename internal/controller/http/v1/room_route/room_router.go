@@ -0,0 +1,229 @@
+package room_route
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type roomRouter struct {
+	useCase        room_usecase.RoomBaseUseCase
+	messageUseCase message_usecase.MessageBaseUseCase
+	logger         logger.Interface
+}
+
+type createRoomBody struct {
+	Name       string `json:"name" binding:"required"`
+	Topic      string `json:"topic"`
+	Visibility string `json:"visibility"`
+}
+
+type updateRoomBody struct {
+	Name       *string `json:"name"`
+	Topic      *string `json:"topic"`
+	Visibility *string `json:"visibility"`
+}
+
+type roomResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Topic      string     `json:"topic"`
+	Visibility string     `json:"visibility"`
+	Kind       string     `json:"kind"`
+	OwnerID    int32      `json:"ownerId"`
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+	Created    time.Time  `json:"created"`
+}
+
+func NewRoomRoute(handler *gin.RouterGroup, roomUseCase room_usecase.RoomBaseUseCase, messageUseCase message_usecase.MessageBaseUseCase, authMiddleware gin.HandlerFunc, l logger.Interface) {
+	h := handler.Group("/rooms", authMiddleware)
+	r := &roomRouter{useCase: roomUseCase, messageUseCase: messageUseCase, logger: l}
+
+	{
+		h.POST("", r.createRoom)
+		h.GET("", r.listRooms)
+		h.GET("/:id", r.getRoom)
+		h.PATCH("/:id", r.updateRoom)
+		h.POST("/:id/archive", r.archiveRoom)
+
+		h.POST("/:id/join", r.joinRoom)
+		h.POST("/:id/leave", r.leaveRoom)
+		h.GET("/:id/members", r.listMembers)
+		h.POST("/:id/invites", r.inviteMember)
+		h.PATCH("/:id/members/:userId", r.changeMemberRole)
+		h.POST("/:id/members/:userId/kick", r.kickMember)
+		h.POST("/:id/members/:userId/ban", r.banMember)
+
+		h.GET("/:id/messages", r.listMessages)
+		h.POST("/:id/messages", r.sendMessage)
+		h.PATCH("/:id/messages/:messageId", r.editMessage)
+		h.DELETE("/:id/messages/:messageId", r.deleteMessage)
+		h.GET("/:id/messages/:messageId/edits", r.listMessageEdits)
+
+		h.POST("/:id/read", r.markAsRead)
+		h.GET("/:id/receipts", r.listReceipts)
+	}
+}
+
+func (route *roomRouter) createRoom(ctx *gin.Context) {
+	var body createRoomBody
+
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - create a room route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind room data", err))
+		return
+	}
+
+	output, err := route.useCase.CreateRoomUseCase.Execute(ctx.Request.Context(), room_usecase.CreateRoomInput{
+		OwnerID:    user.ID,
+		Name:       body.Name,
+		Topic:      body.Topic,
+		Visibility: body.Visibility,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusCreated, toRoomResponse(output.Room))
+	}
+}
+
+func (route *roomRouter) listRooms(ctx *gin.Context) {
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.useCase.ListRoomsUseCase.Execute(ctx.Request.Context(), room_usecase.ListRoomsInput{UserID: user.ID})
+
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	rooms := make([]roomResponse, 0, len(output.Rooms))
+	for _, room := range output.Rooms {
+		rooms = append(rooms, toRoomResponse(room))
+	}
+	ctx.JSON(http.StatusOK, rooms)
+}
+
+func (route *roomRouter) getRoom(ctx *gin.Context) {
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	roomID, ok := roomIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.useCase.GetRoomUseCase.Execute(ctx.Request.Context(), room_usecase.GetRoomInput{UserID: user.ID, RoomID: roomID})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toRoomResponse(output.Room))
+	}
+}
+
+func (route *roomRouter) updateRoom(ctx *gin.Context) {
+	var body updateRoomBody
+
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	roomID, ok := roomIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - update a room route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind room data", err))
+		return
+	}
+
+	output, err := route.useCase.UpdateRoomUseCase.Execute(ctx.Request.Context(), room_usecase.UpdateRoomInput{
+		UserID:     user.ID,
+		RoomID:     roomID,
+		Name:       body.Name,
+		Topic:      body.Topic,
+		Visibility: body.Visibility,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toRoomResponse(output.Room))
+	}
+}
+
+func (route *roomRouter) archiveRoom(ctx *gin.Context) {
+	user, ok := authenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	roomID, ok := roomIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	output, err := route.useCase.ArchiveRoomUseCase.Execute(ctx.Request.Context(), room_usecase.ArchiveRoomInput{UserID: user.ID, RoomID: roomID})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toRoomResponse(output.Room))
+	}
+}
+
+func authenticatedUser(ctx *gin.Context) (*domain.User, bool) {
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+	}
+
+	return user, ok
+}
+
+func roomIDParam(ctx *gin.Context) (int32, bool) {
+	roomID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+
+	if err != nil || roomID <= 0 {
+		_ = ctx.Error(domain.NewError(domain.ErrBadRequest, "room id is not valid"))
+		return 0, false
+	}
+
+	return int32(roomID), true
+}
+
+func toRoomResponse(room *domain.Room) roomResponse {
+	return roomResponse{
+		ID:         room.ID,
+		Name:       room.Name,
+		Topic:      room.Topic,
+		Visibility: string(room.Visibility),
+		Kind:       string(room.Kind),
+		OwnerID:    room.OwnerID,
+		ArchivedAt: room.ArchivedAt,
+		Created:    room.Created,
+	}
+}
@@ -0,0 +1,165 @@
+package room_route
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/routetest"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var roomColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created"}
+var roomMemberColumns = []string{"room_id", "user_id", "username", "displayname", "role", "status", "invited_by", "created"}
+
+func memberRow(userID int32, role string, status string) *sqlmock.Rows {
+	return sqlmock.NewRows(roomMemberColumns).AddRow(5, userID, "user", "", role, status, nil, time.Now())
+}
+
+func newTestContext(method string, url string, body string, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	req, _ := http.NewRequestWithContext(c, method, url, bytes.NewBufferString(body))
+	c.Request = req
+	c.Params = params
+	middleware.SetAuthenticatedUser(c, &domain.User{ID: 1, UserName: "eduardolima806"})
+	return c, rec
+}
+
+func newHandler(db *sql.DB) *roomRouter {
+	roomUseCase := room_usecase.NewRoomBaseUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db), repository.NewUserRepository(db), repository.NewUnitOfWork(db))
+	messageUseCase := message_usecase.NewMessageBaseUseCase(repository.NewMessageRepository(db), repository.NewReadReceiptRepository(db), repository.NewRoomMemberRepository(db), roomUseCase.AuthorizeRoomUseCase, hub.NewHub())
+
+	return &roomRouter{
+		useCase:        *roomUseCase,
+		messageUseCase: *messageUseCase,
+		logger:         logger.Discard(),
+	}
+}
+
+func Test_Create_Room(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("room bind error", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms", `{"topic": "no name"}`, nil)
+
+		routetest.Serve(c, newHandler(db).createRoom)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), "Error to bind room data: "))
+	})
+
+	t.Run("room name invalid", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms", `{"name": "General Chat"}`, nil)
+
+		routetest.Serve(c, newHandler(db).createRoom)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("room name already exists", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms", `{"name": "general"}`, nil)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectRollback()
+
+		routetest.Serve(c, newHandler(db).createRoom)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("room is created", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms", `{"name": "general", "topic": "Anything goes", "visibility": "private"}`, nil)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("INSERT INTO room").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectExec("INSERT INTO room_member").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		routetest.Serve(c, newHandler(db).createRoom)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var response roomResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, int32(5), response.ID)
+		assert.Equal(t, int32(1), response.OwnerID)
+		assert.Equal(t, "private", response.Visibility)
+	})
+}
+
+func Test_Get_Room(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("room id invalid", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodGet, "/rooms/abc", "", gin.Params{{Key: "id", Value: "abc"}})
+
+		routetest.Serve(c, newHandler(db).getRoom)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("room does not exists", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodGet, "/rooms/5", "", gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnError(sql.ErrNoRows)
+
+		routetest.Serve(c, newHandler(db).getRoom)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func Test_Update_And_Archive_Room(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("update by non owner is forbidden", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPatch, "/rooms/5", `{"topic": "New topic"}`, gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "member", "active"))
+
+		routetest.Serve(c, newHandler(db).updateRoom)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("room is archived", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		c, rec := newTestContext(http.MethodPost, "/rooms/5/archive", "", gin.Params{{Key: "id", Value: "5"}})
+
+		mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, nil, time.Now()))
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(1, "owner", "active"))
+		mock.ExpectExec("UPDATE room SET archived_at").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		routetest.Serve(c, newHandler(db).archiveRoom)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), "archivedAt"))
+	})
+}
@@ -1,21 +1,48 @@
 package v1
 
 import (
-	"net/http"
-
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/conversation_route"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/event_route"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/health_route"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/metrics_route"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/presence_route"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/room_route"
 	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/user_route"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/ws_route"
+	"github.com/eduardolima806/my-chat-server/internal/infra/health"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/presence"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/conversation_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/event_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/presence_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
 	"github.com/eduardolima806/my-chat-server/internal/usecase/user_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-func NewRouter(handler *gin.Engine, userUseCase user_usecase.UserBaseUserCase) {
+func NewRouter(handler *gin.Engine, userUseCase user_usecase.UserBaseUserCase, roomUseCase room_usecase.RoomBaseUseCase, messageUseCase message_usecase.MessageBaseUseCase, conversationUseCase conversation_usecase.ConversationBaseUseCase, presenceUseCase presence_usecase.PresenceBaseUseCase, eventUseCase event_usecase.EventBaseUseCase, chatHub *hub.Hub, tracker *presence.Tracker, checker *health.Checker, registry *prometheus.Registry, l logger.Interface) {
+
+	// Recover sits innermost, so the error it reports is rendered and logged
+	// like any other.
+	handler.Use(middleware.RequestID(), middleware.AccessLog(l), middleware.Metrics(registry), middleware.RenderErrors(), middleware.Recover(l))
+	handler.NoRoute(middleware.RouteNotFound)
+
+	health_route.NewHealthRoute(&handler.RouterGroup, checker)
+	metrics_route.NewMetricsRoute(&handler.RouterGroup, registry)
 
-	handler.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, "The server is up and running. Chat Server")
-	})
+	authMiddleware := middleware.Authenticate(userUseCase.AuthenticateUserUseCase)
 
 	unversionedGroup := handler.Group("/api/v1")
 	{
-		user_route.NewUserRoute(unversionedGroup, userUseCase)
+		user_route.NewUserRoute(unversionedGroup, userUseCase, authMiddleware, l)
+		conversation_route.NewConversationRoute(unversionedGroup, conversationUseCase, authMiddleware, l)
+		room_route.NewRoomRoute(unversionedGroup, roomUseCase, messageUseCase, authMiddleware, l)
+		presence_route.NewPresenceRoute(unversionedGroup, presenceUseCase, authMiddleware)
+		ws_route.NewWsRoute(unversionedGroup, chatHub, tracker, messageUseCase, middleware.AuthenticateWebSocket(userUseCase.AuthenticateUserUseCase))
+		event_route.NewEventRoute(unversionedGroup, chatHub, tracker, eventUseCase, middleware.AuthenticateWebSocket(userUseCase.AuthenticateUserUseCase))
 	}
 }
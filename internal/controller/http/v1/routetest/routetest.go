@@ -0,0 +1,13 @@
+// Package routetest holds helpers shared by the route tests.
+package routetest
+
+import (
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// Serve runs handler behind the error middleware, as the router does.
+func Serve(c *gin.Context, handler gin.HandlerFunc) {
+	handler(c)
+	middleware.RenderErrors()(c)
+}
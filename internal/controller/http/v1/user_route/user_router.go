@@ -1,17 +1,21 @@
 package user_route
 
 import (
-	"fmt"
+	"math"
 	"net/http"
-	"strings"
+	"strconv"
+	"time"
 
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
 	"github.com/eduardolima806/my-chat-server/internal/domain"
 	"github.com/eduardolima806/my-chat-server/internal/usecase/user_usecase"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
 type userRouter struct {
 	useCase user_usecase.UserBaseUserCase
+	logger  logger.Interface
 }
 
 type createUserBody struct {
@@ -26,13 +30,80 @@ type loginBody struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func NewUserRoute(handler *gin.RouterGroup, userUseCase user_usecase.UserBaseUserCase) {
+type refreshTokenBody struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type sessionResponse struct {
+	AccessToken           string    `json:"accessToken"`
+	TokenType             string    `json:"tokenType"`
+	ExpiresAt             time.Time `json:"expiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+type updateProfileBody struct {
+	DisplayName *string `json:"displayName"`
+	Bio         *string `json:"bio"`
+	AvatarURL   *string `json:"avatarUrl"`
+}
+
+type changePasswordBody struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+type forgotPasswordBody struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type resetPasswordBody struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+type resendVerificationBody struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type userResponse struct {
+	ID            int32     `json:"id"`
+	UserName      string    `json:"userName"`
+	DisplayName   string    `json:"displayName"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+	Bio           string    `json:"bio"`
+	AvatarURL     string    `json:"avatarUrl"`
+	Created       time.Time `json:"created"`
+}
+
+// profileResponse is what anyone can see of a user.
+type profileResponse struct {
+	ID          int32     `json:"id"`
+	UserName    string    `json:"userName"`
+	DisplayName string    `json:"displayName"`
+	Bio         string    `json:"bio"`
+	AvatarURL   string    `json:"avatarUrl"`
+	Created     time.Time `json:"created"`
+}
+
+func NewUserRoute(handler *gin.RouterGroup, userUseCase user_usecase.UserBaseUserCase, authMiddleware gin.HandlerFunc, l logger.Interface) {
 	h := handler.Group("/users")
-	r := &userRouter{useCase: userUseCase}
+	r := &userRouter{useCase: userUseCase, logger: l}
 
 	{
 		h.POST("/create-user", r.createUser)
 		h.POST("/login", r.loginUser)
+		h.POST("/refresh", r.refreshSession)
+		h.POST("/logout", authMiddleware, r.logoutUser)
+		h.GET("/me", authMiddleware, r.me)
+		h.PATCH("/me", authMiddleware, r.updateProfile)
+		h.POST("/me/password", authMiddleware, r.changePassword)
+		h.POST("/forgot-password", r.forgotPassword)
+		h.POST("/reset-password", r.resetPassword)
+		h.GET("/verify-email", r.verifyEmail)
+		h.POST("/verify-email/resend", r.resendVerification)
+		h.GET("/:username", r.profile)
 	}
 }
 
@@ -40,21 +111,15 @@ func (route *userRouter) createUser(ctx *gin.Context) {
 	var body createUserBody
 
 	if err := ctx.ShouldBindJSON(&body); err != nil {
-		// TODO: Include logger interface
-		// fmt.Errorf("http - v1 - create a user route")
-		fmt.Println("http - v1 - create a user route")
-		strErr := strings.ReplaceAll(err.Error(), "\n", "\\n")
-		bindErr := domain.CreateError(domain.ErrBadRequest.Error(), strErr)
-		bindErrStruct := domain.ErrorCodeResponse(bindErr)
-		bindErrStruct.ErrorMessage = fmt.Sprintf("Error to bind user data: %s", bindErr.Error())
-		ctx.JSON(domain.GetHttpStatusCode(bindErr), bindErrStruct)
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - create a user route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind user data", err))
 		return
 	}
 
-	userOutput, err := route.useCase.CreateUserUseCase.Execute(*body.toUserInput())
+	userOutput, err := route.useCase.CreateUserUseCase.Execute(ctx.Request.Context(), *body.toUserInput())
 
 	if err != nil {
-		ctx.JSON(domain.GetHttpStatusCode(err), domain.ErrorCodeResponse(err))
+		_ = ctx.Error(err)
 	} else {
 		ctx.JSON(http.StatusOK, userOutput)
 	}
@@ -64,31 +129,273 @@ func (route *userRouter) loginUser(ctx *gin.Context) {
 	var body loginBody
 
 	if err := ctx.ShouldBindJSON(&body); err != nil {
-		// TODO: Include logger interface
-		// fmt.Errorf("http - v1 - create a user route")
-		fmt.Println("http - v1 - login user route")
-		strErr := strings.ReplaceAll(err.Error(), "\n", "\\n")
-		bindErr := domain.CreateError(domain.ErrBadRequest.Error(), strErr)
-		bindErrStruct := domain.ErrorCodeResponse(bindErr)
-		bindErrStruct.ErrorMessage = fmt.Sprintf("Error to bind login data: %s", bindErr.Error())
-		ctx.JSON(domain.GetHttpStatusCode(bindErr), bindErrStruct)
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - login user route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind login data", err))
 		return
 	}
 
-	userOutput, err := route.useCase.LoginUserUseCase.Execute(*body.tLoginInput())
+	userOutput, err := route.useCase.LoginUserUseCase.Execute(ctx.Request.Context(), *body.tLoginInput(ctx.ClientIP()))
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorCodeResponse(err))
+		_ = ctx.Error(err)
 	} else {
 		if userOutput.IsSucceed {
-			ctx.JSON(http.StatusOK, "login succeed")
+			ctx.JSON(http.StatusOK, toSessionResponse(userOutput.Session))
 		} else {
-			err := domain.CreateError(domain.ErrBadRequest.Error(), userOutput.ErrorType.Description)
-			ctx.JSON(http.StatusBadRequest, domain.ErrorCodeResponse(err))
+			code := domain.ErrBadRequest
+			switch userOutput.ErrorType {
+			case user_usecase.EmailNotVerified:
+				code = domain.ErrForbidden
+			case user_usecase.TooManyAttempts:
+				code = domain.ErrTooManyRequests
+				ctx.Header("Retry-After", retryAfterSeconds(userOutput.RetryAfter))
+			}
+			_ = ctx.Error(domain.NewError(code, userOutput.ErrorType.Description))
 		}
 	}
 }
 
+func (route *userRouter) refreshSession(ctx *gin.Context) {
+	var body refreshTokenBody
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - refresh session route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind refresh token data", err))
+		return
+	}
+
+	sessionOutput, err := route.useCase.RefreshSessionUseCase.Execute(ctx.Request.Context(), user_usecase.RefreshSessionInput{
+		RefreshToken: body.RefreshToken,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toSessionResponse(sessionOutput))
+	}
+}
+
+func (route *userRouter) logoutUser(ctx *gin.Context) {
+	var body refreshTokenBody
+
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - logout user route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind logout data", err))
+		return
+	}
+
+	err := route.useCase.LogoutUserUseCase.Execute(ctx.Request.Context(), user_usecase.LogoutInput{
+		UserID:       user.ID,
+		RefreshToken: body.RefreshToken,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func (route *userRouter) me(ctx *gin.Context) {
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toUserResponse(user))
+}
+
+func (route *userRouter) updateProfile(ctx *gin.Context) {
+	var body updateProfileBody
+
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - update profile route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind profile data", err))
+		return
+	}
+
+	updatedUser, err := route.useCase.UpdateUserProfileUseCase.Execute(ctx.Request.Context(), user_usecase.UpdateProfileInput{
+		UserID:      user.ID,
+		DisplayName: body.DisplayName,
+		Bio:         body.Bio,
+		AvatarURL:   body.AvatarURL,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, toUserResponse(updatedUser))
+	}
+}
+
+func (route *userRouter) changePassword(ctx *gin.Context) {
+	var body changePasswordBody
+
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - change password route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind password data", err))
+		return
+	}
+
+	err := route.useCase.ChangePasswordUseCase.Execute(ctx.Request.Context(), user_usecase.ChangePasswordInput{
+		UserID:          user.ID,
+		CurrentPassword: body.CurrentPassword,
+		NewPassword:     body.NewPassword,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// forgotPassword answers 202 whether or not the email has an account.
+func (route *userRouter) forgotPassword(ctx *gin.Context) {
+	var body forgotPasswordBody
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - forgot password route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind forgot password data", err))
+		return
+	}
+
+	err := route.useCase.RequestPasswordResetUseCase.Execute(ctx.Request.Context(), body.Email)
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusAccepted)
+	}
+}
+
+func (route *userRouter) resetPassword(ctx *gin.Context) {
+	var body resetPasswordBody
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - reset password route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind reset password data", err))
+		return
+	}
+
+	err := route.useCase.ResetPasswordUseCase.Execute(ctx.Request.Context(), user_usecase.ResetPasswordInput{
+		Token:       body.Token,
+		NewPassword: body.NewPassword,
+	})
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+func (route *userRouter) verifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+
+	if token == "" {
+		_ = ctx.Error(domain.NewFieldError(domain.ErrBadRequest, "token is required",
+			domain.FieldError{Field: "token", Message: "is required"}))
+		return
+	}
+
+	err := route.useCase.VerifyEmailUseCase.Execute(ctx.Request.Context(), token)
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// resendVerification answers 202 whether or not a mail was sent.
+func (route *userRouter) resendVerification(ctx *gin.Context) {
+	var body resendVerificationBody
+
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		route.logger.Debug(ctx.Request.Context(), "http - v1 - resend verification route", "error", err)
+		_ = ctx.Error(middleware.BindError("Error to bind verification data", err))
+		return
+	}
+
+	err := route.useCase.ResendEmailVerificationUseCase.Execute(ctx.Request.Context(), body.Email)
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.Status(http.StatusAccepted)
+	}
+}
+
+func (route *userRouter) profile(ctx *gin.Context) {
+	user, err := route.useCase.GetUserProfileUseCase.Execute(ctx.Request.Context(), ctx.Param("username"))
+
+	if err != nil {
+		_ = ctx.Error(err)
+	} else {
+		ctx.JSON(http.StatusOK, profileResponse{
+			ID:          user.ID,
+			UserName:    user.UserName,
+			DisplayName: user.DisplayName,
+			Bio:         user.Bio,
+			AvatarURL:   user.AvatarURL,
+			Created:     user.Created,
+		})
+	}
+}
+
+func toUserResponse(user *domain.User) userResponse {
+	return userResponse{
+		ID:            user.ID,
+		UserName:      user.UserName,
+		DisplayName:   user.DisplayName,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		Bio:           user.Bio,
+		AvatarURL:     user.AvatarURL,
+		Created:       user.Created,
+	}
+}
+
+// retryAfterSeconds formats d for the Retry-After header, which counts whole
+// seconds, rounding up so clients never retry too early.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+func toSessionResponse(session *user_usecase.SessionOutput) sessionResponse {
+	return sessionResponse{
+		AccessToken:           session.AccessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             session.AccessTokenExpiresAt,
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiresAt: session.RefreshTokenExpiresAt,
+	}
+}
+
 func (body *createUserBody) toUserInput() *user_usecase.UserInput {
 	return &user_usecase.UserInput{
 		UserName:    body.UserName,
@@ -98,9 +405,10 @@ func (body *createUserBody) toUserInput() *user_usecase.UserInput {
 	}
 }
 
-func (body *loginBody) tLoginInput() *user_usecase.LoginInput {
+func (body *loginBody) tLoginInput(ip string) *user_usecase.LoginInput {
 	return &user_usecase.LoginInput{
 		Login:    body.Login,
 		Password: body.Password,
+		IP:       ip,
 	}
 }
@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,15 +12,37 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/routetest"
 	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
 	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/infra/throttle"
 	"github.com/eduardolima806/my-chat-server/internal/usecase/user_usecase"
 	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var testLockout = domain.LockoutPolicy{MaxFailures: 3, FailureWindow: time.Minute, BaseLockout: time.Minute, MaxLockout: time.Hour}
+
+// newUserUseCase builds the user use cases on top of db, counting failed
+// logins in memory.
+func newUserUseCase(db *sql.DB, passwordHasher util.PasswordHasher, mailer util.Mailer) user_usecase.UserBaseUserCase {
+	return *user_usecase.NewUserBaseUserCase(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), repository.NewPasswordResetTokenRepository(db), repository.NewEmailVerificationTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), passwordHasher, util.NewJWTTokenManager("secret", time.Minute), mailer, repository.NewUnitOfWork(db), hub.NewHub(), user_usecase.UserSettings{
+		RefreshTokenTTL:            time.Hour,
+		PasswordResetTTL:           time.Hour,
+		EmailVerificationTTL:       time.Hour,
+		VerificationResendInterval: time.Minute,
+		VerifyEmailURL:             "http://localhost:8080/api/v1/users/verify-email",
+		AccountLockout:             testLockout,
+		IPLockout:                  testLockout,
+	}, user_usecase.NewMetrics(prometheus.NewRegistry()), logger.Discard())
+}
+
 func Test_Create_New_User(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
@@ -32,8 +54,6 @@ func Test_Create_New_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		user := map[string]string{
 			"displayName": "Eduardo Lima",
 		}
@@ -46,10 +66,11 @@ func Test_Create_New_User(t *testing.T) {
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.createUser(c)
+		routetest.Serve(c, handler.createUser)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.True(t, strings.Contains(rec.Body.String(), "Error to bind user data: "))
 	})
@@ -61,8 +82,6 @@ func Test_Create_New_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		user := map[string]string{
 			"userName":    "ed",
 			"displayName": "Eduardo Lima",
@@ -78,14 +97,16 @@ func Test_Create_New_User(t *testing.T) {
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.createUser(c)
+		routetest.Serve(c, handler.createUser)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "username must has at least 5 alphanumerics characters")
-		actualError := errors.New(rec.Body.String())
-		assert.Equal(t, domain.ErrorCodeResponse(expectedError), domain.ErrorCodeResponse(actualError))
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, domain.ErrBadRequest.Error(), problem.Code)
+		assert.Equal(t, "username must has at least 5 alphanumerics characters", problem.Detail)
 	})
 
 	t.Run("email invalid", func(t *testing.T) {
@@ -95,8 +116,6 @@ func Test_Create_New_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		user := map[string]string{
 			"userName":    "eduardolima806",
 			"displayName": "Eduardo Lima",
@@ -112,14 +131,16 @@ func Test_Create_New_User(t *testing.T) {
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.createUser(c)
+		routetest.Serve(c, handler.createUser)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "email is not valid")
-		actualError := errors.New(rec.Body.String())
-		assert.Equal(t, domain.ErrorCodeResponse(expectedError), domain.ErrorCodeResponse(actualError))
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, domain.ErrBadRequest.Error(), problem.Code)
+		assert.Equal(t, "email is not valid", problem.Detail)
 	})
 
 	t.Run("password is not secure", func(t *testing.T) {
@@ -129,8 +150,6 @@ func Test_Create_New_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		user := map[string]string{
 			"userName":    "eduardolima806",
 			"displayName": "Eduardo Lima",
@@ -146,14 +165,16 @@ func Test_Create_New_User(t *testing.T) {
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.createUser(c)
+		routetest.Serve(c, handler.createUser)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "password is not secure")
-		actualError := errors.New(rec.Body.String())
-		assert.Equal(t, domain.ErrorCodeResponse(expectedError), domain.ErrorCodeResponse(actualError))
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, domain.ErrBadRequest.Error(), problem.Code)
+		assert.Equal(t, "password is not secure", problem.Detail)
 	})
 
 	t.Run("user is created", func(t *testing.T) {
@@ -163,8 +184,6 @@ func Test_Create_New_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		user := map[string]string{
 			"userName":    "joaquim2019",
 			"displayName": "Joaquim Lima",
@@ -172,14 +191,17 @@ func Test_Create_New_User(t *testing.T) {
 			"password":    "P4$$word",
 		}
 
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
-
 		passHasherMock.On("HashPassword", user["password"]).Return("hashedPassword", nil)
 
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 		rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
 		mock.ExpectQuery("INSERT INTO app_user").WillReturnRows(rows)
+		mock.ExpectQuery("INSERT INTO email_verification_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
 
+		var mails bytes.Buffer
 		userJson, _ := json.Marshal(user)
 
 		req, err := http.NewRequestWithContext(c, http.MethodPost, "/users/create-user", bytes.NewBuffer(userJson))
@@ -188,13 +210,16 @@ func Test_Create_New_User(t *testing.T) {
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.LogMailer{Out: &mails}),
+			logger:  logger.Discard(),
 		}
 
-		handler.createUser(c)
+		routetest.Serve(c, handler.createUser)
 		assert.Equal(t, http.StatusOK, rec.Code)
 		expectedBody := "{\"CreatedUserId\":1}"
 		assert.Equal(t, expectedBody, rec.Body.String())
+		assert.Contains(t, mails.String(), "To: joaquim@gmail.com")
+		assert.Contains(t, mails.String(), "/api/v1/users/verify-email?token=")
 	})
 }
 
@@ -207,8 +232,6 @@ func Test_Login_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		login := map[string]string{
 			"loginError":   "eduardo01",
 			"passwordTypo": "P4$$w0rd001",
@@ -216,17 +239,18 @@ func Test_Login_User(t *testing.T) {
 
 		loginJson, _ := json.Marshal(login)
 
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 
 		req, err := http.NewRequestWithContext(c, http.MethodPost, "/users/login", bytes.NewBuffer(loginJson))
 		assert.NoError(t, err)
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.loginUser(c)
+		routetest.Serve(c, handler.loginUser)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.True(t, strings.Contains(rec.Body.String(), "Error to bind login data: "))
@@ -239,8 +263,6 @@ func Test_Login_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		login := map[string]string{
 			"login":    "eduardo01",
 			"password": "P4$$w0rd001",
@@ -248,21 +270,23 @@ func Test_Login_User(t *testing.T) {
 
 		loginJson, _ := json.Marshal(login)
 
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 
 		req, err := http.NewRequestWithContext(c, http.MethodPost, "/users/login", bytes.NewBuffer(loginJson))
 		assert.NoError(t, err)
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.loginUser(c)
+		routetest.Serve(c, handler.loginUser)
 
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "user login does not exists")
-		actualError := errors.New(rec.Body.String())
-		assert.Equal(t, domain.ErrorCodeResponse(expectedError), domain.ErrorCodeResponse(actualError))
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, domain.ErrBadRequest.Error(), problem.Code)
+		assert.Equal(t, "user login does not exists", problem.Detail)
 	})
 
 	t.Run("email does not exists", func(t *testing.T) {
@@ -272,8 +296,6 @@ func Test_Login_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		login := map[string]string{
 			"login":    "eduardo01@test.com",
 			"password": "P4$$w0rd001",
@@ -281,21 +303,23 @@ func Test_Login_User(t *testing.T) {
 
 		loginJson, _ := json.Marshal(login)
 
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 
 		req, err := http.NewRequestWithContext(c, http.MethodPost, "/users/login", bytes.NewBuffer(loginJson))
 		assert.NoError(t, err)
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.loginUser(c)
+		routetest.Serve(c, handler.loginUser)
 
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "email does not exists")
-		actualError := errors.New(rec.Body.String())
-		assert.Equal(t, domain.ErrorCodeResponse(expectedError), domain.ErrorCodeResponse(actualError))
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, domain.ErrBadRequest.Error(), problem.Code)
+		assert.Equal(t, "email does not exists", problem.Detail)
 	})
 
 	t.Run("password does not match", func(t *testing.T) {
@@ -305,8 +329,6 @@ func Test_Login_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		login := map[string]string{
 			"login":    "eduardolima806",
 			"password": "P4$$w0rd00122",
@@ -314,10 +336,10 @@ func Test_Login_User(t *testing.T) {
 
 		loginJson, _ := json.Marshal(login)
 
-		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-		rows2 := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows)
-		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows2)
+		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		rows2 := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows)
+		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows2)
 
 		passHasherMock.On("VerifyPassword", login["password"], mock.Anything).Return(false)
 
@@ -326,14 +348,16 @@ func Test_Login_User(t *testing.T) {
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.loginUser(c)
+		routetest.Serve(c, handler.loginUser)
 
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "password does not match")
-		actualError := errors.New(rec.Body.String())
-		assert.Equal(t, domain.ErrorCodeResponse(expectedError), domain.ErrorCodeResponse(actualError))
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, domain.ErrBadRequest.Error(), problem.Code)
+		assert.Equal(t, "password does not match", problem.Detail)
 	})
 
 	t.Run("login succeed", func(t *testing.T) {
@@ -343,8 +367,6 @@ func Test_Login_User(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(rec)
 
-		userRepo := repository.NewUserRepository(db)
-
 		login := map[string]string{
 			"login":    "eduardolima806",
 			"password": "P4$$w0rd001",
@@ -352,10 +374,13 @@ func Test_Login_User(t *testing.T) {
 
 		loginJson, _ := json.Marshal(login)
 
-		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-		rows2 := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows)
-		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows2)
+		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		rows2 := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows)
+		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows2)
+
+		mockDb.ExpectQuery("INSERT INTO refresh_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mockDb.MatchExpectationsInOrder(false)
 
 		passHasherMock.On("VerifyPassword", login["password"], mock.Anything).Return(true)
 
@@ -364,12 +389,484 @@ func Test_Login_User(t *testing.T) {
 		c.Request = req
 
 		handler := &userRouter{
-			useCase: *user_usecase.NewUserBaseUserCase(userRepo, passHasherMock),
+			useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}),
+			logger:  logger.Discard(),
+		}
+
+		routetest.Serve(c, handler.loginUser)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response sessionResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.NotEmpty(t, response.AccessToken)
+		assert.NotEmpty(t, response.RefreshToken)
+		assert.Equal(t, "Bearer", response.TokenType)
+	})
+
+	t.Run("login locked out", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		passHasherMock := &util.MockPasswordHasher{}
+		passHasherMock.On("VerifyPassword", "wrong", "hash").Return(false)
+		handler := &userRouter{useCase: newUserUseCase(db, passHasherMock, &util.MockMailer{}), logger: logger.Discard()}
+
+		var rec *httptest.ResponseRecorder
+		for i := 0; i < testLockout.MaxFailures; i++ {
+			rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+			mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(rows)
+
+			rec = httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			req, _ := http.NewRequestWithContext(c, http.MethodPost, "/users/login", bytes.NewBufferString(`{"login": "eduardolima806", "password": "wrong"}`))
+			req.RemoteAddr = "10.0.0.1:52000"
+			c.Request = req
+
+			if i == testLockout.MaxFailures-1 {
+				mockDb.ExpectQuery("INSERT INTO audit_log").WithArgs(domain.AuditLoginLocked, int32(1), "10.0.0.1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mockDb.ExpectQuery("INSERT INTO audit_log").WithArgs(domain.AuditLoginLocked, nil, "10.0.0.1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			}
+
+			routetest.Serve(c, handler.loginUser)
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, domain.ErrTooManyRequests.Error(), problem.Code)
+		assert.Equal(t, "too many failed login attempts", problem.Detail)
+		assert.Nil(t, mockDb.ExpectationsWereMet())
+	})
+
+	t.Run("login lockout ignores X-Forwarded-For from untrusted addresses", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		// No proxy is trusted, as configured by default.
+		engine := gin.New()
+		assert.Nil(t, engine.SetTrustedProxies(nil))
+		engine.Use(middleware.RenderErrors())
+		useCase := newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{})
+		NewUserRoute(engine.Group(""), useCase, middleware.Authenticate(useCase.AuthenticateUserUseCase), logger.Discard())
+
+		var rec *httptest.ResponseRecorder
+		for i := 0; i < testLockout.MaxFailures; i++ {
+			mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+			if i == testLockout.MaxFailures-1 {
+				mockDb.ExpectQuery("INSERT INTO audit_log").WithArgs(domain.AuditLoginLocked, nil, "10.0.0.1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			rec = httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/users/login", bytes.NewBufferString(fmt.Sprintf(`{"login": "nobody%d", "password": "wrong"}`, i)))
+			req.RemoteAddr = "10.0.0.1:52000"
+			req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i+1))
+			engine.ServeHTTP(rec, req)
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Nil(t, mockDb.ExpectationsWereMet())
+	})
+}
+
+func Test_Me_Route(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(db *sql.DB) *gin.Engine {
+		engine := gin.New()
+		useCase := newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{})
+		NewUserRoute(engine.Group(""), useCase, middleware.Authenticate(useCase.AuthenticateUserUseCase), logger.Discard())
+		return engine
+	}
+
+	t.Run("missing bearer token", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/me", nil)
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("invalid bearer token", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/me", nil)
+		req.Header.Set("Authorization", "Bearer not.a.token")
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("authenticated user", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		mockDb.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(rows)
+
+		accessToken, _, _ := util.NewJWTTokenManager("secret", time.Minute).GenerateToken(1)
+		req, _ := http.NewRequest(http.MethodGet, "/users/me", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), "\"userName\":\"eduardolima806\""))
+		assert.False(t, strings.Contains(rec.Body.String(), "P4$$w0rd"))
+	})
+}
+
+func Test_Update_Profile_Route(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(db *sql.DB) *gin.Engine {
+		engine := gin.New()
+		engine.Use(middleware.RenderErrors())
+		useCase := newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{})
+		NewUserRoute(engine.Group(""), useCase, middleware.Authenticate(useCase.AuthenticateUserUseCase), logger.Discard())
+		return engine
+	}
+
+	userRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+	}
+
+	patch := func(db *sql.DB, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		accessToken, _, _ := util.NewJWTTokenManager("secret", time.Minute).GenerateToken(1)
+		req, _ := http.NewRequest(http.MethodPatch, "/users/me", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		newEngine(db).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("profile updated", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+		mockDb.ExpectExec("UPDATE app_user SET displayname").WithArgs(int32(1), "Eduardo", "Go developer", "https://cdn.example.com/eduardo.png").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		rec := patch(db, `{"displayName": "Eduardo", "bio": "Go developer", "avatarUrl": "https://cdn.example.com/eduardo.png"}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response userResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "Eduardo", response.DisplayName)
+		assert.Equal(t, "Go developer", response.Bio)
+		assert.Equal(t, "https://cdn.example.com/eduardo.png", response.AvatarURL)
+		assert.Nil(t, mockDb.ExpectationsWereMet())
+	})
+
+	t.Run("invalid avatar url", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+
+		rec := patch(db, `{"avatarUrl": "not a url"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "avatar url must be an http or https url", problem.Detail)
+	})
+
+	t.Run("missing bearer token", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodPatch, "/users/me", bytes.NewBufferString(`{"bio": "Go developer"}`))
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func Test_Profile_Route(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(db *sql.DB) *gin.Engine {
+		engine := gin.New()
+		engine.Use(middleware.RenderErrors())
+		useCase := newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{})
+		NewUserRoute(engine.Group(""), useCase, middleware.Authenticate(useCase.AuthenticateUserUseCase), logger.Discard())
+		return engine
+	}
+
+	t.Run("public profile", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "Go developer", "", nil, time.Now(), nil)
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE lower\\(username\\)").WithArgs("eduardolima806").WillReturnRows(rows)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/eduardolima806", nil)
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), "\"bio\":\"Go developer\""))
+		assert.False(t, strings.Contains(rec.Body.String(), "eduardolima.dev.io@gmail.com"))
+		assert.False(t, strings.Contains(rec.Body.String(), "P4$$w0rd"))
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/nobody", nil)
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func Test_Password_Routes(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(db *sql.DB, passwordHasher util.PasswordHasher, mailer util.Mailer) *gin.Engine {
+		engine := gin.New()
+		engine.Use(middleware.RenderErrors())
+		useCase := newUserUseCase(db, passwordHasher, mailer)
+		NewUserRoute(engine.Group(""), useCase, middleware.Authenticate(useCase.AuthenticateUserUseCase), logger.Discard())
+		return engine
+	}
+
+	userRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	}
+
+	t.Run("password changed", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		passHasherMock := &util.MockPasswordHasher{}
+		rec := httptest.NewRecorder()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+		mockDb.ExpectExec("UPDATE app_user SET password").WithArgs(int32(1), "newHash").WillReturnResult(sqlmock.NewResult(0, 1))
+		passHasherMock.On("VerifyPassword", "P4$$w0rd", "hash").Return(true)
+		passHasherMock.On("HashPassword", "N3w$$w0rd").Return("newHash", nil)
+
+		accessToken, _, _ := util.NewJWTTokenManager("secret", time.Minute).GenerateToken(1)
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/password", bytes.NewBufferString(`{"currentPassword": "P4$$w0rd", "newPassword": "N3w$$w0rd"}`))
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		newEngine(db, passHasherMock, &util.MockMailer{}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Nil(t, mockDb.ExpectationsWereMet())
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		passHasherMock := &util.MockPasswordHasher{}
+		rec := httptest.NewRecorder()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(userRow())
+		passHasherMock.On("VerifyPassword", "wrong", "hash").Return(false)
+
+		accessToken, _, _ := util.NewJWTTokenManager("secret", time.Minute).GenerateToken(1)
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/password", bytes.NewBufferString(`{"currentPassword": "wrong", "newPassword": "N3w$$w0rd"}`))
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		newEngine(db, passHasherMock, &util.MockMailer{}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, []domain.FieldError{{Field: "currentPassword", Message: "does not match"}}, problem.Errors)
+	})
+
+	t.Run("forgot password with unknown email", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/forgot-password", bytes.NewBufferString(`{"email": "nobody@gmail.com"}`))
+		newEngine(db, &util.MockPasswordHasher{}, &util.MockMailer{}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+
+	t.Run("reset password with invalid token", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM password_reset_token").WillReturnError(sql.ErrNoRows)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/reset-password", bytes.NewBufferString(`{"token": "reset", "newPassword": "N3w$$w0rd"}`))
+		newEngine(db, &util.MockPasswordHasher{}, &util.MockMailer{}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "reset token is not valid", problem.Detail)
+	})
+}
+
+func Test_Email_Verification_Routes(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(db *sql.DB) *gin.Engine {
+		engine := gin.New()
+		engine.Use(middleware.RenderErrors())
+		useCase := newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{})
+		NewUserRoute(engine.Group(""), useCase, middleware.Authenticate(useCase.AuthenticateUserUseCase), logger.Discard())
+		return engine
+	}
+
+	t.Run("email verified", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "used_at", "created"}).AddRow(4, 1, util.HashToken("verify"), time.Now().Add(time.Hour), nil, time.Now())
+		mockDb.ExpectQuery("SELECT (.+) FROM email_verification_token").WithArgs(util.HashToken("verify")).WillReturnRows(rows)
+		mockDb.ExpectBegin()
+		mockDb.ExpectExec("UPDATE email_verification_token SET used_at").WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDb.ExpectExec("UPDATE app_user SET email_verified_at").WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDb.ExpectExec("UPDATE email_verification_token SET used_at").WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDb.ExpectCommit()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/verify-email?token=verify", nil)
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Nil(t, mockDb.ExpectationsWereMet())
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/users/verify-email", nil)
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, []domain.FieldError{{Field: "token", Message: "is required"}}, problem.Errors)
+	})
+
+	t.Run("resend to unknown email", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+		req, _ := http.NewRequest(http.MethodPost, "/users/verify-email/resend", bytes.NewBufferString(`{"email": "nobody@gmail.com"}`))
+		newEngine(db).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+
+	t.Run("login with unverified email", func(t *testing.T) {
+		db, mockDb, _ := sqlmock.New()
+		passHasherMock := &util.MockPasswordHasher{}
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+
+		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+		mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(rows)
+		passHasherMock.On("VerifyPassword", "P4$$w0rd", "hash").Return(true)
+
+		req, _ := http.NewRequestWithContext(c, http.MethodPost, "/users/login", bytes.NewBufferString(`{"login": "eduardolima806", "password": "P4$$w0rd"}`))
+		c.Request = req
+
+		useCase := newUserUseCase(db, passHasherMock, &util.MockMailer{})
+		useCase.LoginUserUseCase = user_usecase.NewLoginUserUseCase(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), passHasherMock, util.NewJWTTokenManager("secret", time.Minute), time.Hour, true, testLockout, testLockout, user_usecase.NewMetrics(prometheus.NewRegistry()))
+		handler := &userRouter{useCase: useCase, logger: logger.Discard()}
+
+		routetest.Serve(c, handler.loginUser)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		var problem domain.Problem
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "email is not verified", problem.Detail)
+	})
+}
+
+func Test_Refresh_Session(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("refresh bind error", func(t *testing.T) {
+
+		db, _, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+
+		req, err := http.NewRequestWithContext(c, http.MethodPost, "/users/refresh", bytes.NewBufferString("{}"))
+		assert.NoError(t, err)
+		c.Request = req
+
+		handler := &userRouter{
+			useCase: newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{}),
+			logger:  logger.Discard(),
+		}
+
+		routetest.Serve(c, handler.refreshSession)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), "Error to bind refresh token data: "))
+	})
+
+	t.Run("refresh token does not exists", func(t *testing.T) {
+
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+
+		mockDb.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnError(sql.ErrNoRows)
+
+		req, err := http.NewRequestWithContext(c, http.MethodPost, "/users/refresh", bytes.NewBufferString(`{"refreshToken": "refresh"}`))
+		assert.NoError(t, err)
+		c.Request = req
+
+		handler := &userRouter{
+			useCase: newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{}),
+			logger:  logger.Discard(),
+		}
+
+		routetest.Serve(c, handler.refreshSession)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("refresh succeed", func(t *testing.T) {
+
+		db, mockDb, _ := sqlmock.New()
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "rotated_at", "revoked_at", "created"}).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), nil, nil, time.Now())
+		mockDb.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnRows(rows)
+		mockDb.ExpectBegin()
+		mockDb.ExpectExec("UPDATE refresh_token SET rotated_at").WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDb.ExpectQuery("INSERT INTO refresh_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mockDb.ExpectCommit()
+
+		req, err := http.NewRequestWithContext(c, http.MethodPost, "/users/refresh", bytes.NewBufferString(`{"refreshToken": "refresh"}`))
+		assert.NoError(t, err)
+		c.Request = req
+
+		handler := &userRouter{
+			useCase: newUserUseCase(db, &util.MockPasswordHasher{}, &util.MockMailer{}),
+			logger:  logger.Discard(),
 		}
 
-		handler.loginUser(c)
+		routetest.Serve(c, handler.refreshSession)
 
 		assert.Equal(t, http.StatusOK, rec.Code)
-		assert.Equal(t, "\"login succeed\"", rec.Body.String())
+		var response sessionResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.NotEmpty(t, response.RefreshToken)
 	})
 }
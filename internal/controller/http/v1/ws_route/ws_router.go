@@ -0,0 +1,195 @@
+package ws_route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/presence"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	MessageSendEvent   = "message.send"
+	MessageEditEvent   = "message.edit"
+	MessageDeleteEvent = "message.delete"
+	MessageReadEvent   = "message.read"
+	TypingStartEvent   = "typing.start"
+	TypingStopEvent    = "typing.stop"
+	PresenceSetEvent   = "presence.set"
+)
+
+type wsRouter struct {
+	hub            *hub.Hub
+	tracker        *presence.Tracker
+	messageUseCase message_usecase.MessageBaseUseCase
+	upgrader       websocket.Upgrader
+}
+
+type messageSendPayload struct {
+	RoomID int32  `json:"roomId"`
+	Body   string `json:"body"`
+}
+
+type typingPayload struct {
+	RoomID int32 `json:"roomId"`
+}
+
+type presenceSetPayload struct {
+	Status string `json:"status"`
+}
+
+type messageChangePayload struct {
+	RoomID    int32  `json:"roomId"`
+	MessageID int64  `json:"messageId"`
+	Body      string `json:"body"`
+}
+
+func NewWsRoute(handler *gin.RouterGroup, chatHub *hub.Hub, tracker *presence.Tracker, messageUseCase message_usecase.MessageBaseUseCase, authMiddleware gin.HandlerFunc) {
+	r := &wsRouter{
+		hub:            chatHub,
+		tracker:        tracker,
+		messageUseCase: messageUseCase,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Connections are authenticated by access token rather than cookies,
+			// so cross-origin handshakes cannot ride on a browser session.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+
+	handler.GET("/ws", authMiddleware, r.connect)
+}
+
+func (route *wsRouter) connect(ctx *gin.Context) {
+	user, ok := middleware.AuthenticatedUser(ctx)
+
+	if !ok {
+		_ = ctx.Error(domain.NewError(domain.ErrUnauthorized, "user is not authenticated"))
+		return
+	}
+
+	conn, err := route.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// The upgrader already replied with an HTTP error.
+		return
+	}
+
+	// The token is only checked at the handshake, so the connection does not
+	// outlive it.
+	expiresAt, _ := middleware.AccessTokenExpiresAt(ctx)
+
+	route.tracker.Connect(user.ID)
+	defer route.tracker.Disconnect(user.ID)
+
+	route.hub.Serve(conn, user.ID, expiresAt, func(client *hub.Client, event domain.Event) {
+		route.handleEvent(ctx.Request.Context(), user, client, event)
+	})
+}
+
+func (route *wsRouter) handleEvent(ctx context.Context, user *domain.User, client *hub.Client, event domain.Event) {
+	route.tracker.Touch(user.ID)
+
+	switch event.Type {
+	case MessageSendEvent:
+		var payload messageSendPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			client.SendError(domain.NewError(domain.ErrBadRequest, "message payload is not valid"))
+			return
+		}
+
+		// Delivery to the room, sender included, happens through the use case
+		// publisher once the message is stored.
+		_, err := route.messageUseCase.SendMessageUseCase.Execute(ctx, message_usecase.SendMessageInput{
+			UserID: user.ID,
+			RoomID: payload.RoomID,
+			Body:   payload.Body,
+		})
+		if err != nil {
+			client.SendError(err)
+		}
+	case MessageEditEvent:
+		var payload messageChangePayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			client.SendError(domain.NewError(domain.ErrBadRequest, "message payload is not valid"))
+			return
+		}
+
+		_, err := route.messageUseCase.EditMessageUseCase.Execute(ctx, message_usecase.EditMessageInput{
+			UserID:    user.ID,
+			RoomID:    payload.RoomID,
+			MessageID: payload.MessageID,
+			Body:      payload.Body,
+		})
+		if err != nil {
+			client.SendError(err)
+		}
+	case MessageDeleteEvent:
+		var payload messageChangePayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			client.SendError(domain.NewError(domain.ErrBadRequest, "message payload is not valid"))
+			return
+		}
+
+		err := route.messageUseCase.DeleteMessageUseCase.Execute(ctx, message_usecase.DeleteMessageInput{
+			UserID:    user.ID,
+			RoomID:    payload.RoomID,
+			MessageID: payload.MessageID,
+		})
+		if err != nil {
+			client.SendError(err)
+		}
+	case MessageReadEvent:
+		var payload messageChangePayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			client.SendError(domain.NewError(domain.ErrBadRequest, "message payload is not valid"))
+			return
+		}
+
+		_, err := route.messageUseCase.MarkAsReadUseCase.Execute(ctx, message_usecase.MarkAsReadInput{
+			UserID:    user.ID,
+			RoomID:    payload.RoomID,
+			MessageID: payload.MessageID,
+		})
+		if err != nil {
+			client.SendError(err)
+		}
+	case TypingStartEvent, TypingStopEvent:
+		var payload typingPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			client.SendError(domain.NewError(domain.ErrBadRequest, "typing payload is not valid"))
+			return
+		}
+
+		err := route.messageUseCase.SendTypingUseCase.Execute(ctx, message_usecase.SendTypingInput{
+			UserID: user.ID,
+			RoomID: payload.RoomID,
+			Typing: event.Type == TypingStartEvent,
+		})
+		if err != nil {
+			client.SendError(err)
+		}
+	case PresenceSetEvent:
+		var payload presenceSetPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			client.SendError(domain.NewError(domain.ErrBadRequest, "presence payload is not valid"))
+			return
+		}
+
+		status, err := domain.ParseReportedStatus(payload.Status)
+		if err != nil {
+			client.SendError(domain.NewError(domain.ErrBadRequest, err.Error()))
+			return
+		}
+
+		route.tracker.SetStatus(user.ID, status)
+	default:
+		client.SendError(domain.NewError(domain.ErrBadRequest, "unknown event type"))
+	}
+}
@@ -0,0 +1,206 @@
+package ws_route
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/controller/http/v1/middleware"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/internal/infra/presence"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/message_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/user_usecase"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}
+
+var roomColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created"}
+var roomMemberColumns = []string{"room_id", "user_id", "username", "displayname", "role", "status", "invited_by", "created"}
+
+// The tracker gets a database of its own without expectations, so presence
+// events fail quietly instead of competing with the queries under test.
+func newTestServer(t *testing.T, chatHub *hub.Hub, db *sql.DB) (*httptest.Server, *presence.Tracker) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.RenderErrors())
+	authUseCase := user_usecase.NewAuthenticateUserUseCase(repository.NewUserRepository(db), util.NewJWTTokenManager("secret", time.Minute))
+	roomMemberRepo := repository.NewRoomMemberRepository(db)
+	authorizeRoomUseCase := room_usecase.NewAuthorizeRoomUseCase(repository.NewRoomRepository(db), roomMemberRepo)
+	messageUseCase := message_usecase.NewMessageBaseUseCase(repository.NewMessageRepository(db), repository.NewReadReceiptRepository(db), roomMemberRepo, authorizeRoomUseCase, chatHub)
+	presenceDb, _, _ := sqlmock.New()
+	tracker := presence.NewTracker(repository.NewPresenceRepository(presenceDb), repository.NewConversationRepository(presenceDb), chatHub, time.Minute, logger.Discard())
+	NewWsRoute(engine.Group("/api/v1"), chatHub, tracker, *messageUseCase, middleware.AuthenticateWebSocket(authUseCase))
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server, tracker
+}
+
+func dial(t *testing.T, server *httptest.Server, userID int32) *websocket.Conn {
+	accessToken, _, _ := util.NewJWTTokenManager("secret", time.Minute).GenerateToken(userID)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws?access_token=" + accessToken
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func Test_WebSocket_Requires_Authentication(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	server, _ := newTestServer(t, hub.NewHub(), db)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func Test_WebSocket_Message_Is_Fanned_Out(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mockDb.MatchExpectationsInOrder(false)
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(2)).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(2, "joaquim2019", "", "joaquim@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mockDb.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mockDb.ExpectQuery("SELECT (.+) FROM room_member (.+) m.user_id = \\$2").
+		WillReturnRows(sqlmock.NewRows(roomMemberColumns).AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()))
+	mockDb.ExpectQuery("INSERT INTO message").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	mockDb.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(sqlmock.NewRows(roomMemberColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()).
+		AddRow(5, 2, "joaquim2019", "", "owner", "active", nil, time.Now()))
+
+	chatHub := hub.NewHub()
+	server, _ := newTestServer(t, chatHub, db)
+
+	sender := dial(t, server, 1)
+	receiver := dial(t, server, 2)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !(chatHub.IsOnline(1) && chatHub.IsOnline(2)) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	sendEvent, _ := domain.NewEvent(MessageSendEvent, messageSendPayload{RoomID: 5, Body: "hello"})
+	assert.Nil(t, sender.WriteJSON(sendEvent))
+
+	_ = receiver.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event domain.Event
+	assert.Nil(t, receiver.ReadJSON(&event))
+	assert.Equal(t, domain.MessageNewEvent, event.Type)
+
+	var payload message_usecase.MessagePayload
+	assert.Nil(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, int64(42), payload.ID)
+	assert.Equal(t, int32(5), payload.RoomID)
+	assert.Equal(t, int32(1), payload.SenderID)
+	assert.Equal(t, "Eduardo Lima", payload.SenderName)
+	assert.Equal(t, "hello", payload.Body)
+}
+
+func Test_WebSocket_Rejects_Empty_Message(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mockDb.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mockDb.ExpectQuery("SELECT (.+) FROM room_member").
+		WillReturnRows(sqlmock.NewRows(roomMemberColumns).AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()))
+
+	server, _ := newTestServer(t, hub.NewHub(), db)
+	conn := dial(t, server, 1)
+
+	sendEvent, _ := domain.NewEvent(MessageSendEvent, messageSendPayload{RoomID: 5, Body: "  "})
+	assert.Nil(t, conn.WriteJSON(sendEvent))
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event domain.Event
+	assert.Nil(t, conn.ReadJSON(&event))
+	assert.Equal(t, hub.ErrorEvent, event.Type)
+}
+
+func Test_WebSocket_Typing_Is_Relayed(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mockDb.MatchExpectationsInOrder(false)
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(2)).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(2, "joaquim2019", "", "joaquim@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mockDb.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mockDb.ExpectQuery("SELECT (.+) FROM room_member (.+) m.user_id = \\$2").
+		WillReturnRows(sqlmock.NewRows(roomMemberColumns).AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()))
+	mockDb.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(sqlmock.NewRows(roomMemberColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()).
+		AddRow(5, 2, "joaquim2019", "", "owner", "active", nil, time.Now()))
+
+	chatHub := hub.NewHub()
+	server, _ := newTestServer(t, chatHub, db)
+
+	typist := dial(t, server, 1)
+	receiver := dial(t, server, 2)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !(chatHub.IsOnline(1) && chatHub.IsOnline(2)) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	typingEvent, _ := domain.NewEvent(TypingStartEvent, typingPayload{RoomID: 5})
+	assert.Nil(t, typist.WriteJSON(typingEvent))
+
+	_ = receiver.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event domain.Event
+	assert.Nil(t, receiver.ReadJSON(&event))
+	assert.Equal(t, domain.TypingStartedEvent, event.Type)
+
+	var payload message_usecase.TypingPayload
+	assert.Nil(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, int32(1), payload.UserID)
+	assert.Equal(t, int32(5), payload.RoomID)
+}
+
+func Test_WebSocket_Presence_Follows_The_Connection(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user WHERE id").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil))
+
+	chatHub := hub.NewHub()
+	server, tracker := newTestServer(t, chatHub, db)
+	conn := dial(t, server, 1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !chatHub.IsOnline(1) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, domain.PresenceOnline, tracker.Presence([]int32{1})[0].Status)
+
+	awayEvent, _ := domain.NewEvent(PresenceSetEvent, presenceSetPayload{Status: "away"})
+	assert.Nil(t, conn.WriteJSON(awayEvent))
+
+	deadline = time.Now().Add(2 * time.Second)
+	for tracker.Presence([]int32{1})[0].Status != domain.PresenceAway && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, domain.PresenceAway, tracker.Presence([]int32{1})[0].Status)
+
+	offlineEvent, _ := domain.NewEvent(PresenceSetEvent, presenceSetPayload{Status: "offline"})
+	assert.Nil(t, conn.WriteJSON(offlineEvent))
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event domain.Event
+	assert.Nil(t, conn.ReadJSON(&event))
+	assert.Equal(t, hub.ErrorEvent, event.Type)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+const (
+	AuditLoginLocked = "login.locked"
+)
+
+// AuditLog records a security relevant event. UserID is nil when the event
+// is not tied to an account, such as the lockout of a client IP.
+type AuditLog struct {
+	ID      int32
+	Action  string
+	UserID  *int32
+	IP      string
+	Detail  string
+	Created time.Time
+}
+
+func NewAuditLog(action string, userID *int32, ip string, detail string) *AuditLog {
+	return &AuditLog{
+		Action:  action,
+		UserID:  userID,
+		IP:      ip,
+		Detail:  detail,
+		Created: time.Now(),
+	}
+}
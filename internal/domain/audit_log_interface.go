@@ -0,0 +1,7 @@
+package domain
+
+import "context"
+
+type AuditLogRepositoryInterface interface {
+	Save(ctx context.Context, log *AuditLog) (int32, error)
+}
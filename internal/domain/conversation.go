@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+const ConversationNewEvent = "conversation.new"
+
+// Conversation is a room as seen by one participant. Peer is only set for
+// direct conversations and holds the other participant. UnreadCount covers
+// messages from others after LastReadMessageID.
+type Conversation struct {
+	Room              *Room
+	Peer              *User
+	LastActivityAt    time.Time
+	LastReadMessageID int64
+	UnreadCount       int
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+type ConversationRepositoryInterface interface {
+	ListConversations(ctx context.Context, userID int32) ([]*Conversation, error)
+	// ListContactIDs returns the users sharing at least one conversation
+	// with the given user, who are the audience of their presence.
+	ListContactIDs(ctx context.Context, userID int32) ([]int32, error)
+}
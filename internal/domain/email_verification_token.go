@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// EmailVerificationToken proves that a user can read the mails sent to the
+// email of the account. Only the hash of the token is stored, and it can be
+// used once.
+type EmailVerificationToken struct {
+	ID        int32
+	UserID    int32
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	Created   time.Time
+}
+
+func NewEmailVerificationToken(userID int32, tokenHash string, ttl time.Duration) *EmailVerificationToken {
+	now := time.Now()
+	return &EmailVerificationToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		Created:   now,
+	}
+}
+
+func (t *EmailVerificationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *EmailVerificationToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+type EmailVerificationTokenRepositoryInterface interface {
+	Save(ctx context.Context, token *EmailVerificationToken) (int32, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)
+	GetLatestByUser(ctx context.Context, userID int32) (*EmailVerificationToken, error)
+	MarkAsUsed(ctx context.Context, id int32) (bool, error)
+	InvalidateForUser(ctx context.Context, userID int32) error
+}
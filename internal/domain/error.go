@@ -1,11 +1,8 @@
 package domain
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
-	"strings"
 )
 
 var (
@@ -15,56 +12,117 @@ var (
 	ErrConflict            = errors.New("CONFLICT")
 	ErrInsufficientFund    = errors.New("INSUFFICIENT_FUND")
 	ErrUnauthorized        = errors.New("UNAUTHORIZED")
+	ErrForbidden           = errors.New("FORBIDDEN")
+	ErrTooManyRequests     = errors.New("TOO_MANY_REQUESTS")
 )
 
-type ErrorCodesStruct struct {
-	ErrorCode    string `json:"error_code"`
-	ErrorMessage string `json:"error_message"`
+var httpStatusCodes = map[error]int{
+	ErrInternalServerError: http.StatusInternalServerError,
+	ErrNotFound:            http.StatusNotFound,
+	ErrBadRequest:          http.StatusBadRequest,
+	ErrConflict:            http.StatusConflict,
+	ErrInsufficientFund:    http.StatusBadRequest,
+	ErrUnauthorized:        http.StatusUnauthorized,
+	ErrForbidden:           http.StatusForbidden,
+	ErrTooManyRequests:     http.StatusTooManyRequests,
 }
 
-func CreateError(code string, message string) error {
-	err := fmt.Sprintf(`{"error_code": "%s", "error_message": "%s"}`, code, message)
-	return errors.New(err)
+// Error is the error use cases return. Code is one of the Err* values above,
+// so errors.Is(err, ErrNotFound) holds for it, and Fields names the inputs at
+// fault. Cause keeps the underlying error for logs; it is never shown to
+// clients.
+type Error struct {
+	Code    error
+	Message string
+	Fields  []FieldError
+	Cause   error
 }
 
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func NewError(code error, message string) error {
+	return &Error{Code: code, Message: message}
+}
+
+// WrapError is NewError keeping the error that caused it.
+func WrapError(code error, message string, cause error) error {
+	return &Error{Code: code, Message: message, Cause: cause}
+}
+
+func NewFieldError(code error, message string, fields ...FieldError) error {
+	return &Error{Code: code, Message: message, Fields: fields}
+}
+
+// NewConflictError reports that the value of field is already taken, such as
+// the username of a new user.
+func NewConflictError(field string, message string) error {
+	return NewFieldError(ErrConflict, message, FieldError{Field: field, Message: message})
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// GetHttpStatusCode maps the code of err to an HTTP status. Errors that are
+// not an *Error are internal errors.
 func GetHttpStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
 	}
-	errCode := extractErroCode(err)
 
-	switch errCode {
-	case ErrInternalServerError.Error():
-		return http.StatusInternalServerError
-	case ErrNotFound.Error():
-		return http.StatusNotFound
-	case ErrConflict.Error():
-		return http.StatusConflict
-	case ErrInsufficientFund.Error():
-		return http.StatusBadRequest
-	case ErrUnauthorized.Error():
-		return http.StatusUnauthorized
-	case ErrBadRequest.Error():
-		return http.StatusBadRequest
-	default:
+	var domainErr *Error
+	if !errors.As(err, &domainErr) {
 		return http.StatusInternalServerError
 	}
-}
-
-func extractErroCode(err error) string {
-	var errStruct ErrorCodesStruct
-	s := err.Error()
 
-	_ = json.Unmarshal([]byte(s), &errStruct)
+	if status, ok := httpStatusCodes[domainErr.Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
 
-	return strings.ToUpper(errStruct.ErrorCode)
+// Problem is the body of every error response, in the spirit of RFC 9457
+// problem details. Code is the machine readable counterpart of Title.
+type Problem struct {
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Code     string       `json:"code"`
+	Detail   string       `json:"detail"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
 }
 
-func ErrorCodeResponse(err error) ErrorCodesStruct {
-	var errStruct ErrorCodesStruct
-	s := err.Error()
+// NewProblem describes err for clients. Details of errors that are not an
+// *Error are hidden, since they may leak internals.
+func NewProblem(err error) Problem {
+	status := GetHttpStatusCode(err)
+	problem := Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   ErrInternalServerError.Error(),
+		Detail: "internal server error",
+	}
 
-	_ = json.Unmarshal([]byte(s), &errStruct)
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		problem.Code = domainErr.Code.Error()
+		problem.Detail = domainErr.Message
+		problem.Errors = domainErr.Fields
+	}
 
-	return errStruct
+	return problem
 }
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Http_Status_Code_Of_Errors(t *testing.T) {
+	assert.Equal(t, http.StatusOK, GetHttpStatusCode(nil))
+	assert.Equal(t, http.StatusNotFound, GetHttpStatusCode(NewError(ErrNotFound, "room not found")))
+	assert.Equal(t, http.StatusNotFound, GetHttpStatusCode(fmt.Errorf("get room: %w", NewError(ErrNotFound, "room not found"))))
+	assert.Equal(t, http.StatusTooManyRequests, GetHttpStatusCode(NewError(ErrTooManyRequests, "too many failed login attempts")))
+	assert.Equal(t, http.StatusInternalServerError, GetHttpStatusCode(errors.New("unexpected")))
+}
+
+func Test_Error_Keeps_Its_Code_And_Cause(t *testing.T) {
+	err := WrapError(ErrInternalServerError, "could not possible to save user", sql.ErrConnDone)
+
+	assert.ErrorIs(t, err, ErrInternalServerError)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NotErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, "could not possible to save user: sql: connection is already closed")
+
+	var domainErr *Error
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "could not possible to save user", domainErr.Message)
+}
+
+func Test_Problem_Describes_The_Error(t *testing.T) {
+	problem := NewProblem(NewConflictError("username", `username "eduardo" already exists`))
+
+	assert.Equal(t, Problem{
+		Title:  "Conflict",
+		Status: http.StatusConflict,
+		Code:   ErrConflict.Error(),
+		Detail: `username "eduardo" already exists`,
+		Errors: []FieldError{{Field: "username", Message: `username "eduardo" already exists`}},
+	}, problem)
+}
+
+func Test_Problem_Hides_Unexpected_Errors(t *testing.T) {
+	problem := NewProblem(errors.New("pq: password authentication failed"))
+
+	assert.Equal(t, http.StatusInternalServerError, problem.Status)
+	assert.Equal(t, ErrInternalServerError.Error(), problem.Code)
+	assert.Equal(t, "internal server error", problem.Detail)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "encoding/json"
+
+type Event struct {
+	// ID is the position of the event in the event log, zero for events that
+	// are not kept there.
+	ID      int64           `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// ephemeralEvents only matter to the connections open when they happen, so
+// they are not kept for clients that resume: a replayed typing indicator
+// would be stale, and a replayed revocation would close the new connection.
+var ephemeralEvents = map[string]bool{
+	TypingStartedEvent:  true,
+	TypingStoppedEvent:  true,
+	SessionRevokedEvent: true,
+}
+
+func NewEvent(eventType string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{Type: eventType, Payload: data}, nil
+}
+
+// IsEphemeral tells whether the event is left out of the event log.
+func (e Event) IsEphemeral() bool {
+	return ephemeralEvents[e.Type]
+}
+
+// EventPublisherInterface delivers events to every live connection of the
+// given users.
+type EventPublisherInterface interface {
+	SendToUsers(userIDs []int32, event Event)
+}
@@ -0,0 +1,15 @@
+package domain
+
+// EventBusHandler receives every event published on the bus, by any server
+// instance, together with the users it is addressed to.
+type EventBusHandler func(userIDs []int32, event Event)
+
+// EventBusInterface carries events between server instances, so a client
+// receives an event whichever instance its connection is held by.
+type EventBusInterface interface {
+	Publish(userIDs []int32, event Event) error
+	Subscribe(handler EventBusHandler)
+	// Ping reports whether the bus can currently carry events.
+	Ping() error
+	Close() error
+}
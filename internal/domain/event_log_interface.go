@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// EventLogRepositoryInterface keeps the events published to users, so a
+// client that reconnects gets the ones it missed. Events are committed in the
+// order of their ids, so the id of the last event a client got is a cursor
+// that cannot skip one committed later.
+type EventLogRepositoryInterface interface {
+	// Append stores the event for the users and hands it, carrying its id, to
+	// deliver before committing. Appends wait for each other meanwhile, so
+	// events are also delivered in the order of their ids.
+	Append(ctx context.Context, userIDs []int32, event Event, deliver func(Event)) error
+	// ListAfter returns, oldest first, up to limit events addressed to the
+	// user with an id above afterID. It waits for the appends in progress.
+	ListAfter(ctx context.Context, userID int32, afterID int64, limit int) ([]Event, error)
+	// OldestID is the id of the oldest event kept, zero when there is none.
+	OldestID(ctx context.Context) (int64, error)
+	// DeleteBefore drops the events created before the given time, except
+	// the newest one, which tells how far the log went.
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// LoginAttempt counts the failed logins of a key, such as an account or a
+// client IP.
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// RetryAfter is how long the key stays locked, zero when it is not.
+func (a *LoginAttempt) RetryAfter(now time.Time) time.Duration {
+	if !a.IsLocked(now) {
+		return 0
+	}
+	return a.LockedUntil.Sub(now)
+}
+
+// LockoutPolicy locks a key once it reaches MaxFailures failures. The first
+// lockout lasts BaseLockout and every further failure doubles it, up to
+// MaxLockout. Failures are forgotten after FailureWindow without any.
+type LockoutPolicy struct {
+	MaxFailures   int
+	FailureWindow time.Duration
+	BaseLockout   time.Duration
+	MaxLockout    time.Duration
+}
+
+// LockoutFor is how long a key with the given failures is locked, zero when
+// it is not.
+func (p LockoutPolicy) LockoutFor(failures int) time.Duration {
+	if p.MaxFailures <= 0 || failures < p.MaxFailures {
+		return 0
+	}
+
+	lockout := p.BaseLockout
+	for i := p.MaxFailures; i < failures && lockout < p.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > p.MaxLockout {
+		return p.MaxLockout
+	}
+	return lockout
+}
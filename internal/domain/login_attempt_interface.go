@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttemptStoreInterface keeps the failed login counters. Every method
+// must be safe to call from several instances at once.
+type LoginAttemptStoreInterface interface {
+	// Get returns the attempt of key, with no failures if it has none.
+	Get(ctx context.Context, key string) (*LoginAttempt, error)
+	// RecordFailure counts one more failure for key at now. Failures older
+	// than window, counting from the last failure or the end of the last
+	// lockout, are dropped first.
+	RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Lockout_Doubles_With_Each_Failure_Up_To_Max(t *testing.T) {
+	policy := LockoutPolicy{MaxFailures: 3, BaseLockout: time.Minute, MaxLockout: 10 * time.Minute}
+
+	assert.Equal(t, time.Duration(0), policy.LockoutFor(2))
+	assert.Equal(t, time.Minute, policy.LockoutFor(3))
+	assert.Equal(t, 2*time.Minute, policy.LockoutFor(4))
+	assert.Equal(t, 8*time.Minute, policy.LockoutFor(6))
+	assert.Equal(t, 10*time.Minute, policy.LockoutFor(7))
+	assert.Equal(t, 10*time.Minute, policy.LockoutFor(1000))
+}
+
+func Test_If_Lockout_Is_Disabled_Without_Max_Failures(t *testing.T) {
+	policy := LockoutPolicy{BaseLockout: time.Minute, MaxLockout: time.Hour}
+
+	assert.Equal(t, time.Duration(0), policy.LockoutFor(100))
+}
+
+func Test_If_Login_Attempt_Is_Locked_Until_Lockout_Ends(t *testing.T) {
+	now := time.Now()
+	lockedUntil := now.Add(time.Minute)
+	attempt := LoginAttempt{Key: "ip:127.0.0.1", Failures: 5, LockedUntil: &lockedUntil}
+
+	assert.True(t, attempt.IsLocked(now))
+	assert.Equal(t, time.Minute, attempt.RetryAfter(now))
+	assert.False(t, attempt.IsLocked(lockedUntil))
+	assert.Equal(t, time.Duration(0), attempt.RetryAfter(lockedUntil))
+	assert.False(t, (&LoginAttempt{Key: "ip:127.0.0.1"}).IsLocked(now))
+}
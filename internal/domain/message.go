@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MessageBodyMaxLength = 4000
+
+	MessageNewEvent     = "message.new"
+	MessageUpdatedEvent = "message.updated"
+	MessageDeletedEvent = "message.deleted"
+)
+
+type Message struct {
+	ID         int64
+	RoomID     int32
+	SenderID   int32
+	SenderName string
+	Body       string
+	EditedAt   *time.Time
+	DeletedAt  *time.Time
+	Created    time.Time
+}
+
+// MessageEdit keeps a body a message had before it was edited.
+type MessageEdit struct {
+	ID        int64
+	MessageID int64
+	Body      string
+	EditedBy  int32
+	Created   time.Time
+}
+
+// MessagePage selects a window of a room history. Messages are ordered by ID,
+// so BeforeID and AfterID are exclusive keyset bounds and at most one of them
+// is set.
+type MessagePage struct {
+	RoomID   int32
+	BeforeID int64
+	AfterID  int64
+	Limit    int
+}
+
+func NewMessage(roomID int32, senderID int32, body string) (*Message, error) {
+	message := &Message{
+		RoomID:   roomID,
+		SenderID: senderID,
+		Body:     strings.TrimSpace(body),
+		Created:  time.Now(),
+	}
+	err := message.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// Edit replaces the body and returns the previous version so it can be kept
+// in the edit history.
+func (m *Message) Edit(body string, editedBy int32) (*MessageEdit, error) {
+	previous := &MessageEdit{
+		MessageID: m.ID,
+		Body:      m.Body,
+		EditedBy:  editedBy,
+		Created:   time.Now(),
+	}
+
+	m.Body = strings.TrimSpace(body)
+	if err := m.Validate(); err != nil {
+		m.Body = previous.Body
+		return nil, err
+	}
+
+	m.EditedAt = &previous.Created
+	return previous, nil
+}
+
+func (m *Message) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+func (m *Message) IsSentBy(userID int32) bool {
+	return m.SenderID == userID
+}
+
+func (m *Message) Validate() error {
+
+	if m.Body == "" {
+		return errors.New("message body is required")
+	}
+
+	if utf8.RuneCountInString(m.Body) > MessageBodyMaxLength {
+		return errors.New("message body must have at most 4000 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+type MessageRepositoryInterface interface {
+	Save(ctx context.Context, message *Message) (int64, error)
+	GetMessageById(ctx context.Context, id int64) (*Message, error)
+	ListMessages(ctx context.Context, page MessagePage) ([]*Message, error)
+	Edit(ctx context.Context, message *Message, previous *MessageEdit) error
+	SoftDelete(ctx context.Context, id int64) error
+	ListEdits(ctx context.Context, messageID int64) ([]*MessageEdit, error)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Message_Body_Is_Trimmed(t *testing.T) {
+	message, err := NewMessage(5, 1, "  hello  ")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", message.Body)
+}
+
+func Test_Message_Body_Validation(t *testing.T) {
+	_, err := NewMessage(5, 1, "   ")
+	assert.EqualError(t, err, "message body is required")
+
+	_, err = NewMessage(5, 1, strings.Repeat("a", MessageBodyMaxLength+1))
+	assert.EqualError(t, err, "message body must have at most 4000 characters")
+
+	_, err = NewMessage(5, 1, strings.Repeat("á", MessageBodyMaxLength))
+	assert.Nil(t, err)
+}
+
+func Test_Message_Edit_Keeps_Previous_Body(t *testing.T) {
+	message, _ := NewMessage(5, 1, "hello")
+	message.ID = 42
+
+	previous, err := message.Edit(" hello, world ", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", previous.Body)
+	assert.Equal(t, int64(42), previous.MessageID)
+	assert.Equal(t, "hello, world", message.Body)
+	assert.NotNil(t, message.EditedAt)
+}
+
+func Test_Invalid_Message_Edit_Is_Rejected(t *testing.T) {
+	message, _ := NewMessage(5, 1, "hello")
+
+	_, err := message.Edit("  ", 1)
+	assert.EqualError(t, err, "message body is required")
+	assert.Equal(t, "hello", message.Body)
+	assert.Nil(t, message.EditedAt)
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// PasswordResetToken lets a user who forgot the password choose a new one.
+// Only the hash of the token is stored, and it can be used once.
+type PasswordResetToken struct {
+	ID        int32
+	UserID    int32
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	Created   time.Time
+}
+
+func NewPasswordResetToken(userID int32, tokenHash string, ttl time.Duration) *PasswordResetToken {
+	now := time.Now()
+	return &PasswordResetToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		Created:   now,
+	}
+}
+
+func (t *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *PasswordResetToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
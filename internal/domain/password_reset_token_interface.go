@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+type PasswordResetTokenRepositoryInterface interface {
+	Save(ctx context.Context, token *PasswordResetToken) (int32, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	MarkAsUsed(ctx context.Context, id int32) (bool, error)
+	InvalidateForUser(ctx context.Context, userID int32) error
+}
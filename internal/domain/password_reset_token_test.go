@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Password_Reset_Token_Expires_After_TTL(t *testing.T) {
+	token := NewPasswordResetToken(idUser, "hash", time.Hour)
+
+	assert.False(t, token.IsExpired(time.Now()))
+	assert.True(t, token.IsExpired(time.Now().Add(2*time.Hour)))
+}
+
+func Test_If_New_Password_Reset_Token_Is_Not_Used(t *testing.T) {
+	token := NewPasswordResetToken(idUser, "hash", time.Hour)
+
+	assert.False(t, token.IsUsed())
+
+	now := time.Now()
+	token.UsedAt = &now
+
+	assert.True(t, token.IsUsed())
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	PresenceUpdatedEvent = "presence.updated"
+	TypingStartedEvent   = "typing.started"
+	TypingStoppedEvent   = "typing.stopped"
+)
+
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+// Presence is never stored as such: it is derived from the live connections
+// of the user. Only LastSeen survives a restart.
+type Presence struct {
+	UserID   int32
+	Status   PresenceStatus
+	LastSeen *time.Time
+}
+
+// ParseReportedStatus validates a status sent by a client. Offline is not
+// something a connected client can claim, it follows from disconnecting.
+func ParseReportedStatus(status string) (PresenceStatus, error) {
+	switch PresenceStatus(status) {
+	case PresenceOnline, PresenceAway:
+		return PresenceStatus(status), nil
+	default:
+		return "", errors.New("presence status must be online or away")
+	}
+}
+
+// PresenceTrackerInterface reports the presence of users as seen by the live
+// connections of this server.
+type PresenceTrackerInterface interface {
+	Presence(userIDs []int32) []*Presence
+}
+
+type PresenceRepositoryInterface interface {
+	SaveLastSeen(ctx context.Context, userID int32, lastSeen time.Time) error
+	ListLastSeen(ctx context.Context, userIDs []int32) (map[int32]time.Time, error)
+}
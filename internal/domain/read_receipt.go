@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const ReceiptUpdatedEvent = "receipt.updated"
+
+// ReadReceipt is the newest message a participant has read in a room.
+// Everything up to and including MessageID counts as read.
+type ReadReceipt struct {
+	RoomID    int32
+	UserID    int32
+	MessageID int64
+	Updated   time.Time
+}
+
+func NewReadReceipt(roomID int32, userID int32, messageID int64) *ReadReceipt {
+	return &ReadReceipt{
+		RoomID:    roomID,
+		UserID:    userID,
+		MessageID: messageID,
+		Updated:   time.Now(),
+	}
+}
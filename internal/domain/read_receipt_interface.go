@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+type ReadReceiptRepositoryInterface interface {
+	// Advance stores the receipt unless the participant already read past
+	// it, and reports whether it moved.
+	Advance(ctx context.Context, receipt *ReadReceipt) (bool, error)
+	ListReceipts(ctx context.Context, roomID int32) ([]*ReadReceipt, error)
+}
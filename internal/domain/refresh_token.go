@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// SessionRevokedEvent is published to a user whose sessions were ended before
+// their access tokens expired. The realtime connections of the user are
+// closed right after it.
+const SessionRevokedEvent = "session.revoked"
+
+const (
+	SessionRevokedByLogout        = "logout"
+	SessionRevokedByPasswordReset = "password_reset"
+)
+
+type SessionRevokedPayload struct {
+	Reason string `json:"reason"`
+}
+
+type RefreshToken struct {
+	ID        int32
+	UserID    int32
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	Created   time.Time
+}
+
+func NewRefreshToken(userID int32, familyID string, tokenHash string, ttl time.Duration) *RefreshToken {
+	now := time.Now()
+	return &RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		Created:   now,
+	}
+}
+
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *RefreshToken) IsRotated() bool {
+	return t.RotatedAt != nil
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
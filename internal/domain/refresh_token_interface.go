@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+type RefreshTokenRepositoryInterface interface {
+	Save(ctx context.Context, token *RefreshToken) (int32, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	MarkAsRotated(ctx context.Context, id int32) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int32) error
+}
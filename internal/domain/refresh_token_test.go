@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Refresh_Token_Expires_After_TTL(t *testing.T) {
+	token := NewRefreshToken(idUser, "family", "hash", time.Hour)
+
+	assert.False(t, token.IsExpired(time.Now()))
+	assert.True(t, token.IsExpired(time.Now().Add(2*time.Hour)))
+}
+
+func Test_If_New_Refresh_Token_Is_Not_Rotated_Or_Revoked(t *testing.T) {
+	token := NewRefreshToken(idUser, "family", "hash", time.Hour)
+
+	assert.False(t, token.IsRotated())
+	assert.False(t, token.IsRevoked())
+
+	now := time.Now()
+	token.RotatedAt = &now
+	token.RevokedAt = &now
+
+	assert.True(t, token.IsRotated())
+	assert.True(t, token.IsRevoked())
+}
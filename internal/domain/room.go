@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+type RoomVisibility string
+
+const (
+	RoomPublic  RoomVisibility = "public"
+	RoomPrivate RoomVisibility = "private"
+)
+
+type RoomKind string
+
+const (
+	RoomKindChannel RoomKind = "channel"
+	RoomKindDirect  RoomKind = "direct"
+)
+
+type Room struct {
+	ID         int32
+	Name       string
+	Topic      string
+	Visibility RoomVisibility
+	Kind       RoomKind
+	OwnerID    int32
+	ArchivedAt *time.Time
+	Created    time.Time
+}
+
+const (
+	RoomNameRegex      = `^[a-z0-9][a-z0-9_\-]{1,49}$`
+	RoomTopicMaxLength = 250
+)
+
+func NewRoom(id int32, name string, topic string, visibility RoomVisibility, ownerID int32) (*Room, error) {
+	room := &Room{
+		ID:         id,
+		Name:       name,
+		Topic:      topic,
+		Visibility: visibility,
+		Kind:       RoomKindChannel,
+		OwnerID:    ownerID,
+		Created:    time.Now(),
+	}
+	err := room.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// NewDirectRoom builds the private conversation between two users. Its name
+// is derived from the pair, so it is the same whoever opens it, and it never
+// matches RoomNameRegex, so it cannot clash with a channel.
+func NewDirectRoom(openedBy int32, peerID int32) *Room {
+	return &Room{
+		Name:       DirectRoomName(openedBy, peerID),
+		Visibility: RoomPrivate,
+		Kind:       RoomKindDirect,
+		OwnerID:    openedBy,
+		Created:    time.Now(),
+	}
+}
+
+func DirectRoomName(userID int32, otherUserID int32) string {
+	if userID > otherUserID {
+		userID, otherUserID = otherUserID, userID
+	}
+	return fmt.Sprintf("dm:%d:%d", userID, otherUserID)
+}
+
+func (r *Room) Validate() error {
+
+	roomNameRegex := regexp.MustCompile(RoomNameRegex)
+
+	if !r.IsDirect() && !roomNameRegex.MatchString(r.Name) {
+		return errors.New("room name must have 2 to 50 lowercase letters, numbers, hyphens or underscores")
+	}
+
+	if utf8.RuneCountInString(r.Topic) > RoomTopicMaxLength {
+		return errors.New("room topic must have at most 250 characters")
+	}
+
+	if r.Visibility != RoomPublic && r.Visibility != RoomPrivate {
+		return errors.New("room visibility must be public or private")
+	}
+
+	return nil
+}
+
+func (r *Room) IsDirect() bool {
+	return r.Kind == RoomKindDirect
+}
+
+func (r *Room) IsArchived() bool {
+	return r.ArchivedAt != nil
+}
+
+func (r *Room) IsOwnedBy(userID int32) bool {
+	return r.OwnerID == userID
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+type RoomRepositoryInterface interface {
+	Save(ctx context.Context, room *Room) (int32, error)
+	GetRoomById(ctx context.Context, id int32) (*Room, error)
+	GetRoomByName(ctx context.Context, name string) (*Room, error)
+	ListRoomsVisibleTo(ctx context.Context, userID int32) ([]*Room, error)
+	Update(ctx context.Context, room *Room) error
+	Archive(ctx context.Context, id int32) error
+}
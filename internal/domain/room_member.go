@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type RoomRole string
+
+const (
+	RoomRoleOwner  RoomRole = "owner"
+	RoomRoleAdmin  RoomRole = "admin"
+	RoomRoleMember RoomRole = "member"
+)
+
+type MembershipStatus string
+
+const (
+	MembershipInvited MembershipStatus = "invited"
+	MembershipActive  MembershipStatus = "active"
+	MembershipBanned  MembershipStatus = "banned"
+)
+
+type RoomMember struct {
+	RoomID      int32
+	UserID      int32
+	UserName    string
+	DisplayName string
+	Role        RoomRole
+	Status      MembershipStatus
+	InvitedBy   *int32
+	Created     time.Time
+}
+
+func NewRoomMember(roomID int32, userID int32, role RoomRole, status MembershipStatus) (*RoomMember, error) {
+	member := &RoomMember{
+		RoomID:  roomID,
+		UserID:  userID,
+		Role:    role,
+		Status:  status,
+		Created: time.Now(),
+	}
+	err := member.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (m *RoomMember) Validate() error {
+	switch m.Role {
+	case RoomRoleOwner, RoomRoleAdmin, RoomRoleMember:
+	default:
+		return errors.New("room role must be owner, admin or member")
+	}
+
+	switch m.Status {
+	case MembershipInvited, MembershipActive, MembershipBanned:
+	default:
+		return errors.New("membership status must be invited, active or banned")
+	}
+
+	return nil
+}
+
+func (m *RoomMember) IsActive() bool {
+	return m.Status == MembershipActive
+}
+
+func (m *RoomMember) IsBanned() bool {
+	return m.Status == MembershipBanned
+}
+
+func (m *RoomMember) CanModerate() bool {
+	return m.IsActive() && (m.Role == RoomRoleOwner || m.Role == RoomRoleAdmin)
+}
+
+// Outranks reports whether m may moderate other: owners outrank everyone else
+// and admins outrank plain members.
+func (m *RoomMember) Outranks(other *RoomMember) bool {
+	return roleRank(m.Role) > roleRank(other.Role)
+}
+
+func roleRank(role RoomRole) int {
+	switch role {
+	case RoomRoleOwner:
+		return 2
+	case RoomRoleAdmin:
+		return 1
+	default:
+		return 0
+	}
+}
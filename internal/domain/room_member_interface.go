@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+type RoomMemberRepositoryInterface interface {
+	Save(ctx context.Context, member *RoomMember) error
+	GetMember(ctx context.Context, roomID int32, userID int32) (*RoomMember, error)
+	ListMembers(ctx context.Context, roomID int32) ([]*RoomMember, error)
+	Delete(ctx context.Context, roomID int32, userID int32) error
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Room_Member_Role_And_Status_Are_Validated(t *testing.T) {
+	t.Run("room role invalid", func(t *testing.T) {
+		_, err := NewRoomMember(idRoom, idUser, RoomRole("guest"), MembershipActive)
+		assert.EqualError(t, err, "room role must be owner, admin or member")
+	})
+
+	t.Run("membership status invalid", func(t *testing.T) {
+		_, err := NewRoomMember(idRoom, idUser, RoomRoleMember, MembershipStatus("left"))
+		assert.EqualError(t, err, "membership status must be invited, active or banned")
+	})
+
+	t.Run("room member valid", func(t *testing.T) {
+		member, err := NewRoomMember(idRoom, idUser, RoomRoleMember, MembershipActive)
+		assert.Nil(t, err)
+		assert.True(t, member.IsActive())
+		assert.False(t, member.IsBanned())
+	})
+}
+
+func Test_Room_Member_Moderation_Rules(t *testing.T) {
+	owner, _ := NewRoomMember(idRoom, 1, RoomRoleOwner, MembershipActive)
+	admin, _ := NewRoomMember(idRoom, 2, RoomRoleAdmin, MembershipActive)
+	member, _ := NewRoomMember(idRoom, 3, RoomRoleMember, MembershipActive)
+	invitedAdmin, _ := NewRoomMember(idRoom, 4, RoomRoleAdmin, MembershipInvited)
+
+	assert.True(t, owner.CanModerate())
+	assert.True(t, admin.CanModerate())
+	assert.False(t, member.CanModerate())
+	assert.False(t, invitedAdmin.CanModerate())
+
+	assert.True(t, owner.Outranks(admin))
+	assert.True(t, admin.Outranks(member))
+	assert.False(t, admin.Outranks(owner))
+	assert.False(t, admin.Outranks(invitedAdmin))
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	idRoom    = 1
+	roomName  = "general"
+	roomTopic = "Anything goes"
+)
+
+func Test_Room_Name_Is_Or_Not_Is_Valid(t *testing.T) {
+	testsCases := []string{
+		"g",
+		"General",
+		"general chat",
+		"-general",
+		"general-chat_01",
+	}
+
+	t.Run("room name with less then 2 characters", func(t *testing.T) {
+		_, err := NewRoom(idRoom, testsCases[0], roomTopic, RoomPublic, idUser)
+		assert.Error(t, err)
+	})
+
+	t.Run("room name with uppercase characters", func(t *testing.T) {
+		_, err := NewRoom(idRoom, testsCases[1], roomTopic, RoomPublic, idUser)
+		assert.Error(t, err)
+	})
+
+	t.Run("room name with spaces", func(t *testing.T) {
+		_, err := NewRoom(idRoom, testsCases[2], roomTopic, RoomPublic, idUser)
+		assert.Error(t, err)
+	})
+
+	t.Run("room name starting with hyphen", func(t *testing.T) {
+		_, err := NewRoom(idRoom, testsCases[3], roomTopic, RoomPublic, idUser)
+		assert.Error(t, err)
+	})
+
+	t.Run("room name valid", func(t *testing.T) {
+		_, err := NewRoom(idRoom, testsCases[4], roomTopic, RoomPublic, idUser)
+		assert.Nil(t, err)
+	})
+}
+
+func Test_Room_Topic_Length(t *testing.T) {
+	t.Run("room topic too long", func(t *testing.T) {
+		_, err := NewRoom(idRoom, roomName, strings.Repeat("a", RoomTopicMaxLength+1), RoomPublic, idUser)
+		assert.EqualError(t, err, "room topic must have at most 250 characters")
+	})
+
+	t.Run("room topic at the limit", func(t *testing.T) {
+		_, err := NewRoom(idRoom, roomName, strings.Repeat("á", RoomTopicMaxLength), RoomPublic, idUser)
+		assert.Nil(t, err)
+	})
+}
+
+func Test_Room_Visibility(t *testing.T) {
+	t.Run("room visibility invalid", func(t *testing.T) {
+		_, err := NewRoom(idRoom, roomName, roomTopic, RoomVisibility("secret"), idUser)
+		assert.EqualError(t, err, "room visibility must be public or private")
+	})
+
+	t.Run("room visibility private", func(t *testing.T) {
+		room, err := NewRoom(idRoom, roomName, roomTopic, RoomPrivate, idUser)
+		assert.Nil(t, err)
+		assert.True(t, room.IsOwnedBy(idUser))
+		assert.False(t, room.IsArchived())
+	})
+}
+
+func Test_Direct_Room_Is_The_Same_For_Both_Participants(t *testing.T) {
+	room := NewDirectRoom(7, 3)
+
+	assert.Equal(t, "dm:3:7", room.Name)
+	assert.Equal(t, DirectRoomName(3, 7), DirectRoomName(7, 3))
+	assert.True(t, room.IsDirect())
+	assert.Equal(t, RoomPrivate, room.Visibility)
+	assert.Nil(t, room.Validate())
+	assert.False(t, regexp.MustCompile(RoomNameRegex).MatchString(room.Name))
+}
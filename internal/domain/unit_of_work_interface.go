@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// UnitOfWorkInterface runs fn so that every repository call made with the
+// context it receives commits or rolls back together.
+type UnitOfWorkInterface interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,22 +2,40 @@ package domain
 
 import (
 	"errors"
+	"net/url"
 	"regexp"
 	"time"
+	"unicode/utf8"
 )
 
 type User struct {
-	ID          int32
-	UserName    string
-	DisplayName string
-	Email       string
-	Password    string
-	Created     time.Time
+	ID              int32
+	UserName        string
+	DisplayName     string
+	Email           string
+	Password        string
+	Bio             string
+	AvatarURL       string
+	EmailVerifiedAt *time.Time
+	Created         time.Time
+	// PasswordChangedAt is when the password was last reset, nil if never.
+	PasswordChangedAt *time.Time
+}
+
+// ProfileUpdate holds the profile fields a user changes. Nil fields are kept.
+type ProfileUpdate struct {
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
 }
 
 const (
 	UserNameRegex = `^[a-zA-Z0-9]{5,}$`
 	EmailRegex    = `^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`
+
+	DisplayNameMaxLength = 255
+	BioMaxLength         = 500
+	AvatarURLMaxLength   = 2048
 )
 
 func NewUser(id int32, userName string, displayName string, email string, password string) (*User, error) {
@@ -50,13 +68,85 @@ func (u *User) Validate() error {
 		return errors.New("email is not valid")
 	}
 
+	if err := ValidatePassword(u.Password); err != nil {
+		return err
+	}
+
+	return u.ValidateProfile()
+}
+
+// IsTokenRevoked tells whether an access token issued at issuedAt predates
+// the last password reset. Token times only keep whole seconds, so a token
+// issued in the second of the reset counts as older.
+func (u *User) IsTokenRevoked(issuedAt time.Time) bool {
+	if u.PasswordChangedAt == nil {
+		return false
+	}
+	return !issuedAt.After(u.PasswordChangedAt.Truncate(time.Second))
+}
+
+// ValidatePassword checks a plain password before it is hashed.
+func ValidatePassword(password string) error {
 	passRules := []string{".{7,}", "[a-z]", "[A-Z]", "[0-9]", "[^\\d\\w]"}
 	for _, v := range passRules {
 		passRegex := regexp.MustCompile(v)
-		if !passRegex.MatchString(u.Password) {
+		if !passRegex.MatchString(password) {
 			return errors.New("password is not secure")
 		}
 	}
 
 	return nil
 }
+
+// ValidateProfile checks the fields a user may change after signup.
+func (u *User) ValidateProfile() error {
+
+	if utf8.RuneCountInString(u.DisplayName) > DisplayNameMaxLength {
+		return errors.New("display name must have at most 255 characters")
+	}
+
+	if utf8.RuneCountInString(u.Bio) > BioMaxLength {
+		return errors.New("bio must have at most 500 characters")
+	}
+
+	if u.AvatarURL != "" {
+		avatarURL, err := url.Parse(u.AvatarURL)
+		if err != nil || (avatarURL.Scheme != "http" && avatarURL.Scheme != "https") || avatarURL.Host == "" {
+			return errors.New("avatar url must be an http or https url")
+		}
+		if len(u.AvatarURL) > AvatarURLMaxLength {
+			return errors.New("avatar url must have at most 2048 characters")
+		}
+	}
+
+	return nil
+}
+
+// IsEmailVerified reports whether the user followed the verification link
+// mailed on signup.
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// UpdateProfile applies update when the resulting profile is valid, leaving
+// the user untouched otherwise.
+func (u *User) UpdateProfile(update ProfileUpdate) error {
+	profile := *u
+
+	if update.DisplayName != nil {
+		profile.DisplayName = *update.DisplayName
+	}
+	if update.Bio != nil {
+		profile.Bio = *update.Bio
+	}
+	if update.AvatarURL != nil {
+		profile.AvatarURL = *update.AvatarURL
+	}
+
+	if err := profile.ValidateProfile(); err != nil {
+		return err
+	}
+
+	u.DisplayName, u.Bio, u.AvatarURL = profile.DisplayName, profile.Bio, profile.AvatarURL
+	return nil
+}
@@ -1,6 +1,19 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 type UserRepositoryInterface interface {
-	Save(user *User) (int32, error)
-	GetUserByUserNameOrEmail(userNameOrEmail string) (*User, error)
+	Save(ctx context.Context, user *User) (int32, error)
+	GetUserByUserNameOrEmail(ctx context.Context, userNameOrEmail string) (*User, error)
+	GetUserByUserName(ctx context.Context, userName string) (*User, error)
+	GetUserById(ctx context.Context, id int32) (*User, error)
+	UpdateProfile(ctx context.Context, user *User) error
+	UpdatePassword(ctx context.Context, userID int32, passwordHash string) error
+	MarkEmailAsVerified(ctx context.Context, userID int32) error
+	// MarkPasswordAsChanged stamps when the password was reset, which revokes
+	// the access tokens issued before.
+	MarkPasswordAsChanged(ctx context.Context, userID int32, changedAt time.Time) error
 }
@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -89,3 +91,65 @@ func Test_If_Get_No_Error_For_Valid_User(t *testing.T) {
 	assert.Equal(t, expectedUser.Email, user.Email)
 	assert.Equal(t, expectedUser.Password, user.Password)
 }
+
+func Test_If_Profile_Is_Updated(t *testing.T) {
+	newDisplayName := "Eduardo"
+	newBio := "Go developer"
+	newAvatarURL := "https://cdn.example.com/eduardo.png"
+
+	t.Run("only the given fields change", func(t *testing.T) {
+		user, _ := NewUser(idUser, userName, displayName, email, password)
+
+		err := user.UpdateProfile(ProfileUpdate{Bio: &newBio, AvatarURL: &newAvatarURL})
+		assert.Nil(t, err)
+		assert.Equal(t, displayName, user.DisplayName)
+		assert.Equal(t, newBio, user.Bio)
+		assert.Equal(t, newAvatarURL, user.AvatarURL)
+	})
+
+	t.Run("invalid avatar url", func(t *testing.T) {
+		user, _ := NewUser(idUser, userName, displayName, email, password)
+		avatarURL := "javascript:alert(1)"
+
+		err := user.UpdateProfile(ProfileUpdate{DisplayName: &newDisplayName, AvatarURL: &avatarURL})
+		assert.EqualError(t, err, "avatar url must be an http or https url")
+		assert.Equal(t, displayName, user.DisplayName)
+		assert.Empty(t, user.AvatarURL)
+	})
+
+	t.Run("bio too long", func(t *testing.T) {
+		user, _ := NewUser(idUser, userName, displayName, email, password)
+		bio := strings.Repeat("a", BioMaxLength+1)
+
+		err := user.UpdateProfile(ProfileUpdate{Bio: &bio})
+		assert.EqualError(t, err, "bio must have at most 500 characters")
+	})
+
+	t.Run("display name too long", func(t *testing.T) {
+		user, _ := NewUser(idUser, userName, displayName, email, password)
+		name := strings.Repeat("a", DisplayNameMaxLength+1)
+
+		err := user.UpdateProfile(ProfileUpdate{DisplayName: &name})
+		assert.EqualError(t, err, "display name must have at most 255 characters")
+	})
+
+	t.Run("empty values clear the profile", func(t *testing.T) {
+		user, _ := NewUser(idUser, userName, displayName, email, password)
+		empty := ""
+
+		err := user.UpdateProfile(ProfileUpdate{DisplayName: &empty, AvatarURL: &empty})
+		assert.Nil(t, err)
+		assert.Empty(t, user.DisplayName)
+	})
+}
+
+func Test_If_New_User_Email_Is_Not_Verified(t *testing.T) {
+	user, _ := NewUser(idUser, userName, displayName, email, password)
+
+	assert.False(t, user.IsEmailVerified())
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+
+	assert.True(t, user.IsEmailVerified())
+}
@@ -0,0 +1,60 @@
+package bus
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+var ErrBusClosed = errors.New("event bus is closed")
+
+// InProcessBus delivers events to the subscribers of this process only. It
+// suits a single instance, and tests that run several hubs side by side.
+type InProcessBus struct {
+	mu       sync.RWMutex
+	handlers []domain.EventBusHandler
+	closed   bool
+}
+
+func NewInProcessBus() *InProcessBus {
+	return &InProcessBus{}
+}
+
+func (b *InProcessBus) Publish(userIDs []int32, event domain.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	for _, handler := range b.handlers {
+		handler(userIDs, event)
+	}
+	return nil
+}
+
+func (b *InProcessBus) Subscribe(handler domain.EventBusHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *InProcessBus) Ping() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+	return nil
+}
+
+func (b *InProcessBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.handlers = nil
+	return nil
+}
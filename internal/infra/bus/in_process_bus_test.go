@@ -0,0 +1,78 @@
+package bus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(t *testing.T, client *hub.Client) string {
+	select {
+	case message := <-client.Messages():
+		return string(message)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not received in time")
+		return ""
+	}
+}
+
+func assertNothingReceived(t *testing.T, client *hub.Client) {
+	select {
+	case message := <-client.Messages():
+		t.Fatalf("unexpected event %s", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_Event_Reaches_Users_Connected_To_Another_Hub(t *testing.T) {
+	eventBus := NewInProcessBus()
+
+	// Two hubs stand for two server instances sharing the bus.
+	firstHub := hub.NewHub()
+	secondHub := hub.NewHub()
+	eventBus.Subscribe(firstHub.SendToUsers)
+	eventBus.Subscribe(secondHub.SendToUsers)
+
+	sender := firstHub.Subscribe(1)
+	receiver := secondHub.Subscribe(2)
+	bystander := secondHub.Subscribe(3)
+
+	event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": "hello"})
+	NewPublisher(eventBus, nil, 0, logger.Discard()).SendToUsers([]int32{1, 2}, event)
+
+	assert.Contains(t, receive(t, sender), domain.MessageNewEvent)
+	assert.Contains(t, receive(t, receiver), domain.MessageNewEvent)
+	assertNothingReceived(t, bystander)
+}
+
+func Test_Every_Connection_Of_A_User_Across_Hubs_Receives_The_Event(t *testing.T) {
+	eventBus := NewInProcessBus()
+
+	firstHub := hub.NewHub()
+	secondHub := hub.NewHub()
+	eventBus.Subscribe(firstHub.SendToUsers)
+	eventBus.Subscribe(secondHub.SendToUsers)
+
+	desktop := firstHub.Subscribe(1)
+	phone := secondHub.Subscribe(1)
+
+	event, _ := domain.NewEvent(domain.TypingStartedEvent, map[string]int32{"roomId": 5})
+	assert.Nil(t, eventBus.Publish([]int32{1}, event))
+
+	assert.Contains(t, receive(t, desktop), domain.TypingStartedEvent)
+	assert.Contains(t, receive(t, phone), domain.TypingStartedEvent)
+}
+
+func Test_Closed_Bus_Rejects_Events(t *testing.T) {
+	eventBus := NewInProcessBus()
+	assert.Nil(t, eventBus.Ping())
+	assert.Nil(t, eventBus.Close())
+	assert.ErrorIs(t, eventBus.Ping(), ErrBusClosed)
+
+	event, _ := domain.NewEvent(domain.MessageNewEvent, nil)
+	assert.ErrorIs(t, eventBus.Publish([]int32{1}, event), ErrBusClosed)
+}
@@ -0,0 +1,183 @@
+package bus
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/lib/pq"
+)
+
+const (
+	notifyChannel = "chat_events"
+	// Postgres rejects NOTIFY payloads of 8000 bytes or more. Bigger events
+	// are parked in the bus_event table and only their id is notified.
+	maxNotifyPayload = 7900
+	// Parked events only need to outlive the delivery to every listener.
+	parkedEventRetention = 5 * time.Minute
+
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+)
+
+type busMessage struct {
+	UserIDs []int32       `json:"userIds,omitempty"`
+	Event   *domain.Event `json:"event,omitempty"`
+	Ref     int64         `json:"ref,omitempty"`
+}
+
+// PostgresBus shares events between instances through LISTEN/NOTIFY. Every
+// instance, the publishing one included, gets each event back from Postgres
+// and hands it to its subscribers.
+type PostgresBus struct {
+	Db *sql.DB
+
+	listener *pq.Listener
+	mu       sync.RWMutex
+	handlers []domain.EventBusHandler
+	done     chan struct{}
+	logger   logger.Interface
+
+	// ctx bounds every query of the bus, which is not tied to a request, and
+	// is cancelled on Close so shutdown does not wait on a slow publish.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewPostgresBus opens a dedicated listening connection next to the pool, as
+// LISTEN holds on to its session.
+func NewPostgresBus(db *sql.DB, connectionString string, l logger.Interface) (*PostgresBus, error) {
+	listener := pq.NewListener(connectionString, minReconnectInterval, maxReconnectInterval, func(eventType pq.ListenerEventType, err error) {
+		if err != nil {
+			l.Warn(context.Background(), "bus - postgres listener", "event", int(eventType), "error", err)
+		}
+	})
+
+	if err := listener.Listen(notifyChannel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	b := newPostgresBus(db, listener.Notify, l)
+	b.listener = listener
+	return b, nil
+}
+
+func newPostgresBus(db *sql.DB, notifications <-chan *pq.Notification, l logger.Interface) *PostgresBus {
+	b := &PostgresBus{
+		Db:     db,
+		done:   make(chan struct{}),
+		logger: l,
+	}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	go b.receive(notifications)
+	return b
+}
+
+func (b *PostgresBus) Publish(userIDs []int32, event domain.Event) error {
+	payload, err := json.Marshal(busMessage{UserIDs: userIDs, Event: &event})
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > maxNotifyPayload {
+		ref, err := b.park(payload)
+		if err != nil {
+			return err
+		}
+		payload, _ = json.Marshal(busMessage{Ref: ref})
+	}
+
+	_, err = b.Db.ExecContext(b.ctx, "SELECT pg_notify($1, $2)", notifyChannel, string(payload))
+	return err
+}
+
+func (b *PostgresBus) Subscribe(handler domain.EventBusHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Ping checks the listening connection, which reconnects on its own when it
+// is lost: events notified in between are missed.
+func (b *PostgresBus) Ping() error {
+	if b.listener == nil {
+		return nil
+	}
+	return b.listener.Ping()
+}
+
+// Close stops listening and aborts the queries still running. Events
+// published by other instances from then on are not delivered here.
+func (b *PostgresBus) Close() error {
+	b.cancel()
+	if b.listener == nil {
+		return nil
+	}
+
+	err := b.listener.Close()
+	<-b.done
+	return err
+}
+
+func (b *PostgresBus) park(payload []byte) (int64, error) {
+	now := time.Now()
+
+	if _, err := b.Db.ExecContext(b.ctx, "DELETE FROM bus_event WHERE created < $1", now.Add(-parkedEventRetention)); err != nil {
+		return 0, err
+	}
+
+	var ref int64
+	err := b.Db.QueryRowContext(b.ctx, "INSERT INTO bus_event (payload, created) VALUES ($1,$2) RETURNING id", string(payload), now).Scan(&ref)
+	return ref, err
+}
+
+func (b *PostgresBus) receive(notifications <-chan *pq.Notification) {
+	defer close(b.done)
+
+	for notification := range notifications {
+		// A nil notification means the connection was re-established:
+		// whatever was notified in between is lost, as with any realtime
+		// delivery.
+		if notification == nil {
+			continue
+		}
+
+		if err := b.dispatch(notification.Extra); err != nil {
+			b.logger.Error(context.Background(), "bus - could not possible to dispatch event", "error", err)
+		}
+	}
+}
+
+func (b *PostgresBus) dispatch(payload string) error {
+	var message busMessage
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		return err
+	}
+
+	if message.Ref > 0 {
+		var parked string
+		if err := b.Db.QueryRowContext(b.ctx, "SELECT payload FROM bus_event WHERE id = $1", message.Ref).Scan(&parked); err != nil {
+			return err
+		}
+		message = busMessage{}
+		if err := json.Unmarshal([]byte(parked), &message); err != nil {
+			return err
+		}
+	}
+
+	if message.Event == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(message.UserIDs, *message.Event)
+	}
+	return nil
+}
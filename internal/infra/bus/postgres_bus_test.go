@@ -0,0 +1,163 @@
+package bus
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// capturedArg matches any value and keeps it, standing in for Postgres
+// handing the notified payload back to the listeners.
+type capturedArg struct {
+	value string
+}
+
+func (c *capturedArg) Match(v driver.Value) bool {
+	c.value, _ = v.(string)
+	return true
+}
+
+type delivery struct {
+	userIDs []int32
+	event   domain.Event
+}
+
+func Test_Notified_Event_Is_Dispatched_To_Subscribers(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	notifications := make(chan *pq.Notification)
+	eventBus := newPostgresBus(db, notifications, logger.Discard())
+	defer close(notifications)
+
+	deliveries := make(chan delivery, 1)
+	eventBus.Subscribe(func(userIDs []int32, event domain.Event) {
+		deliveries <- delivery{userIDs: userIDs, event: event}
+	})
+
+	payload := &capturedArg{}
+	mock.ExpectExec("SELECT pg_notify\\(\\$1, \\$2\\)").WithArgs(notifyChannel, payload).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": "hello"})
+	assert.Nil(t, eventBus.Publish([]int32{1, 2}, event))
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	notifications <- &pq.Notification{Channel: notifyChannel, Extra: payload.value}
+
+	select {
+	case received := <-deliveries:
+		assert.Equal(t, []int32{1, 2}, received.userIDs)
+		assert.Equal(t, domain.MessageNewEvent, received.event.Type)
+		assert.JSONEq(t, `{"body":"hello"}`, string(received.event.Payload))
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not dispatched in time")
+	}
+}
+
+func Test_Oversized_Event_Is_Parked_And_Fetched_Back(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	notifications := make(chan *pq.Notification)
+	eventBus := newPostgresBus(db, notifications, logger.Discard())
+	defer close(notifications)
+
+	deliveries := make(chan delivery, 1)
+	eventBus.Subscribe(func(userIDs []int32, event domain.Event) {
+		deliveries <- delivery{userIDs: userIDs, event: event}
+	})
+
+	parked := &capturedArg{}
+	notified := &capturedArg{}
+	mock.ExpectExec("DELETE FROM bus_event WHERE created < \\$1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO bus_event").WithArgs(parked, sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("SELECT pg_notify").WithArgs(notifyChannel, notified).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body := strings.Repeat("é", 4000)
+	event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": body})
+	assert.Nil(t, eventBus.Publish([]int32{2}, event))
+	assert.Equal(t, `{"ref":7}`, notified.value)
+
+	mock.ExpectQuery("SELECT payload FROM bus_event WHERE id = \\$1").WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"payload"}).AddRow(parked.value))
+
+	notifications <- &pq.Notification{Channel: notifyChannel, Extra: notified.value}
+
+	select {
+	case received := <-deliveries:
+		assert.Equal(t, []int32{2}, received.userIDs)
+		assert.Contains(t, string(received.event.Payload), body)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not dispatched in time")
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Oversized_Event_Is_Not_Parked_Once_The_Bus_Is_Closed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	notifications := make(chan *pq.Notification)
+	eventBus := newPostgresBus(db, notifications, logger.Discard())
+	defer close(notifications)
+
+	assert.Nil(t, eventBus.Close())
+
+	event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": strings.Repeat("é", 4000)})
+	assert.ErrorIs(t, eventBus.Publish([]int32{2}, event), context.Canceled)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Close_Returns_While_A_Publish_Is_Blocked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	notifications := make(chan *pq.Notification)
+	eventBus := newPostgresBus(db, notifications, logger.Discard())
+	defer close(notifications)
+
+	mock.ExpectExec("SELECT pg_notify").WillDelayFor(time.Minute).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	published := make(chan error, 1)
+	go func() {
+		event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": "hello"})
+		published <- eventBus.Publish([]int32{1}, event)
+	}()
+
+	select {
+	case <-published:
+		t.Fatal("publish should still be waiting on the database")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- eventBus.Close() }()
+
+	select {
+	case err := <-closed:
+		assert.Nil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("close did not return in time")
+	}
+
+	select {
+	case err := <-published:
+		assert.NotNil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("publish was not aborted by close")
+	}
+}
+
+func Test_Reconnection_Notice_Is_Ignored(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	notifications := make(chan *pq.Notification)
+	eventBus := newPostgresBus(db, notifications, logger.Discard())
+
+	eventBus.Subscribe(func([]int32, domain.Event) {
+		t.Error("no event should be dispatched")
+	})
+
+	notifications <- nil
+	close(notifications)
+	<-eventBus.done
+}
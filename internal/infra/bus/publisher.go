@@ -0,0 +1,80 @@
+package bus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+)
+
+// pruneInterval is how often, at most, events past the retention are
+// deleted from the event log.
+const pruneInterval = time.Minute
+
+// Publisher lets use cases publish through the bus instead of a local hub,
+// so their events reach users connected to any instance. Events other than
+// ephemeral ones are kept in the event log first, so clients that reconnect
+// can resume from the id of the last one they got.
+type Publisher struct {
+	Bus      domain.EventBusInterface
+	EventLog domain.EventLogRepositoryInterface
+
+	retention  time.Duration
+	mu         sync.Mutex
+	lastPruned time.Time
+	logger     logger.Interface
+}
+
+func NewPublisher(eventBus domain.EventBusInterface, eventLog domain.EventLogRepositoryInterface, retention time.Duration, l logger.Interface) *Publisher {
+	return &Publisher{
+		Bus:       eventBus,
+		EventLog:  eventLog,
+		retention: retention,
+		logger:    l,
+	}
+}
+
+// SendToUsers is best effort like every realtime delivery: the change is
+// already stored and can be fetched through the REST API. An event that
+// could not be kept is still delivered, without an id.
+func (p *Publisher) SendToUsers(userIDs []int32, event domain.Event) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	if p.EventLog == nil || event.IsEphemeral() {
+		p.publish(userIDs, event)
+		return
+	}
+
+	ctx := context.Background()
+	if err := p.EventLog.Append(ctx, userIDs, event, func(event domain.Event) { p.publish(userIDs, event) }); err != nil {
+		p.logger.Error(ctx, "bus - could not possible to keep event", "event_type", event.Type, "error", err)
+		p.publish(userIDs, event)
+	}
+
+	p.prune(ctx)
+}
+
+func (p *Publisher) publish(userIDs []int32, event domain.Event) {
+	if err := p.Bus.Publish(userIDs, event); err != nil {
+		p.logger.Error(context.Background(), "bus - could not possible to publish event", "event_type", event.Type, "error", err)
+	}
+}
+
+func (p *Publisher) prune(ctx context.Context) {
+	p.mu.Lock()
+	now := time.Now()
+	if now.Sub(p.lastPruned) < pruneInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.lastPruned = now
+	p.mu.Unlock()
+
+	if err := p.EventLog.DeleteBefore(ctx, now.Add(-p.retention)); err != nil {
+		p.logger.Warn(ctx, "bus - could not possible to prune event log", "error", err)
+	}
+}
@@ -0,0 +1,105 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/hub"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEventLog struct {
+	appended  []domain.Event
+	appendErr error
+	pruned    []time.Time
+}
+
+func (f *fakeEventLog) Append(_ context.Context, _ []int32, event domain.Event, deliver func(domain.Event)) error {
+	if f.appendErr != nil {
+		return f.appendErr
+	}
+	event.ID = int64(len(f.appended) + 1)
+	f.appended = append(f.appended, event)
+	deliver(event)
+	return nil
+}
+
+func (f *fakeEventLog) ListAfter(context.Context, int32, int64, int) ([]domain.Event, error) {
+	return nil, nil
+}
+
+func (f *fakeEventLog) OldestID(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeEventLog) DeleteBefore(_ context.Context, before time.Time) error {
+	f.pruned = append(f.pruned, before)
+	return nil
+}
+
+func Test_If_The_Event_Is_Kept_And_Delivered_With_Its_ID(t *testing.T) {
+	eventBus := NewInProcessBus()
+	chatHub := hub.NewHub()
+	eventBus.Subscribe(chatHub.SendToUsers)
+	client := chatHub.Subscribe(1)
+
+	eventLog := &fakeEventLog{}
+	publisher := NewPublisher(eventBus, eventLog, 24*time.Hour, logger.Discard())
+
+	event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": "hello"})
+	publisher.SendToUsers([]int32{1}, event)
+
+	assert.Len(t, eventLog.appended, 1)
+	assert.Contains(t, receive(t, client), `"id":1`)
+}
+
+func Test_If_Ephemeral_Events_Are_Not_Kept(t *testing.T) {
+	eventBus := NewInProcessBus()
+	chatHub := hub.NewHub()
+	eventBus.Subscribe(chatHub.SendToUsers)
+	client := chatHub.Subscribe(1)
+
+	eventLog := &fakeEventLog{}
+	publisher := NewPublisher(eventBus, eventLog, 24*time.Hour, logger.Discard())
+
+	event, _ := domain.NewEvent(domain.TypingStartedEvent, map[string]int32{"userId": 2})
+	publisher.SendToUsers([]int32{1}, event)
+
+	assert.Empty(t, eventLog.appended)
+	message := receive(t, client)
+	assert.Contains(t, message, domain.TypingStartedEvent)
+	assert.NotContains(t, message, `"id"`)
+}
+
+func Test_If_The_Event_Is_Delivered_When_It_Cannot_Be_Kept(t *testing.T) {
+	eventBus := NewInProcessBus()
+	chatHub := hub.NewHub()
+	eventBus.Subscribe(chatHub.SendToUsers)
+	client := chatHub.Subscribe(1)
+
+	eventLog := &fakeEventLog{appendErr: errors.New("error to append event")}
+	publisher := NewPublisher(eventBus, eventLog, 24*time.Hour, logger.Discard())
+
+	event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": "hello"})
+	publisher.SendToUsers([]int32{1}, event)
+
+	message := receive(t, client)
+	assert.Contains(t, message, domain.MessageNewEvent)
+	assert.NotContains(t, message, `"id"`)
+}
+
+func Test_If_The_Event_Log_Is_Pruned_At_Most_Once_A_Minute(t *testing.T) {
+	eventLog := &fakeEventLog{}
+	publisher := NewPublisher(NewInProcessBus(), eventLog, time.Hour, logger.Discard())
+
+	event, _ := domain.NewEvent(domain.MessageNewEvent, map[string]string{"body": "hello"})
+	publisher.SendToUsers([]int32{1}, event)
+	publisher.SendToUsers([]int32{1}, event)
+
+	assert.Len(t, eventLog.pruned, 1)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), eventLog.pruned[0], time.Second)
+}
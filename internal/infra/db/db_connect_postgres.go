@@ -1,28 +1,98 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"sync"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/eduardolima806/my-chat-server/config"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 	_ "github.com/lib/pq"
 )
 
-var lock = &sync.Mutex{}
+// maxConnectRetryInterval caps the backoff between connection attempts.
+const maxConnectRetryInterval = 30 * time.Second
 
-var connection *sql.DB
+// ConnectToPostgresDb opens a pool tuned by dbConfig and pings it until the
+// database answers, retrying with backoff, so the server fails fast on start
+// rather than on its first request. Every call opens a pool of its own.
+func ConnectToPostgresDb(ctx context.Context, dbConfig config.PG, l logger.Interface) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", ConnectionString(dbConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	configurePool(conn, dbConfig)
+
+	if err := pingWithRetry(ctx, conn, dbConfig, l); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func ConnectionString(dbConfig config.PG) string {
+	query := url.Values{}
+	query.Set("sslmode", dbConfig.SSLMode)
+	if dbConfig.ConnectTimeout > 0 {
+		query.Set("connect_timeout", strconv.Itoa(int(dbConfig.ConnectTimeout.Seconds())))
+	}
+
+	connectionURL := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(dbConfig.User, dbConfig.Password),
+		Host:     net.JoinHostPort(dbConfig.Host, dbConfig.Port),
+		Path:     "/" + dbConfig.Name,
+		RawQuery: query.Encode(),
+	}
+	return connectionURL.String()
+}
+
+func configurePool(conn *sql.DB, dbConfig config.PG) {
+	conn.SetMaxOpenConns(dbConfig.MaxOpenConns)
+	conn.SetMaxIdleConns(dbConfig.MaxIdleConns)
+	conn.SetConnMaxLifetime(dbConfig.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(dbConfig.ConnMaxIdleTime)
+}
+
+// pingWithRetry pings conn once, then ConnectRetries more times while it
+// fails, doubling the wait from ConnectRetryInterval in between.
+func pingWithRetry(ctx context.Context, conn *sql.DB, dbConfig config.PG, l logger.Interface) error {
+	interval := dbConfig.ConnectRetryInterval
+
+	for attempt := 0; ; attempt++ {
+		err := ping(ctx, conn, dbConfig.ConnectTimeout)
+		if err == nil {
+			return nil
+		}
+		if attempt >= dbConfig.ConnectRetries {
+			return fmt.Errorf("database is not reachable after %d attempts: %w", attempt+1, err)
+		}
+
+		l.Warn(ctx, "db - database is not reachable, retrying", "retry_in", interval.String(), "error", err)
 
-func ConnectToPostgresDb(dbConfig config.PG) (*sql.DB, error) {
-	var err error
-	if connection == nil {
-		lock.Lock()
-		defer lock.Unlock()
-		connectionString := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", dbConfig.User, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Name)
-		connection, err = sql.Open("postgres", connectionString)
-		if err != nil {
-			return nil, err
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
 		}
+
+		interval *= 2
+		if interval > maxConnectRetryInterval {
+			interval = maxConnectRetryInterval
+		}
+	}
+}
+
+func ping(ctx context.Context, conn *sql.DB, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
-	return connection, nil
+	return conn.PingContext(ctx)
 }
@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	StatusReady        = "ready"
+	StatusNotReady     = "not ready"
+	StatusShuttingDown = "shutting down"
+
+	// defaultCheckTimeout bounds every check, so a hanging dependency
+	// reports down instead of hanging the probe.
+	defaultCheckTimeout = 2 * time.Second
+)
+
+// Check reports whether a dependency works, a nil error meaning it does.
+type Check func(ctx context.Context) error
+
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+func (r Report) IsReady() bool {
+	return r.Status == StatusReady
+}
+
+// Checker tells whether the server is ready for traffic: every registered
+// check passes and the server is not shutting down.
+type Checker struct {
+	mu           sync.RWMutex
+	checks       map[string]Check
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{
+		checks:  make(map[string]Check),
+		timeout: defaultCheckTimeout,
+	}
+}
+
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// ShutDown makes the server report not ready from now on, so load balancers
+// stop routing to it before it stops accepting connections.
+func (c *Checker) ShutDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Check runs every check at once.
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	c.mu.RLock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = run(ctx, check, c.timeout)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Checks: make(map[string]CheckResult, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusNotReady
+		}
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check, timeout time.Duration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
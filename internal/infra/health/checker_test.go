@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Server_Is_Ready_When_Every_Check_Passes(t *testing.T) {
+	checker := NewChecker()
+	checker.Register("database", func(ctx context.Context) error { return nil })
+	checker.Register("event_bus", func(ctx context.Context) error { return nil })
+
+	report := checker.Check(context.Background())
+
+	assert.True(t, report.IsReady())
+	assert.Equal(t, StatusReady, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["database"].Status)
+	assert.Equal(t, StatusUp, report.Checks["event_bus"].Status)
+	assert.Empty(t, report.Checks["database"].Error)
+}
+
+func Test_If_Server_Is_Not_Ready_When_A_Check_Fails(t *testing.T) {
+	checker := NewChecker()
+	checker.Register("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.Register("event_bus", func(ctx context.Context) error { return nil })
+
+	report := checker.Check(context.Background())
+
+	assert.False(t, report.IsReady())
+	assert.Equal(t, StatusNotReady, report.Status)
+	assert.Equal(t, CheckResult{Status: StatusDown, LatencyMs: report.Checks["database"].LatencyMs, Error: "connection refused"}, report.Checks["database"])
+	assert.Equal(t, StatusUp, report.Checks["event_bus"].Status)
+}
+
+func Test_If_Hanging_Check_Times_Out(t *testing.T) {
+	checker := NewChecker()
+	checker.timeout = 50 * time.Millisecond
+	checker.Register("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	report := checker.Check(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, report.IsReady())
+	assert.Equal(t, StatusDown, report.Checks["database"].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+	assert.GreaterOrEqual(t, report.Checks["database"].LatencyMs, float64(50))
+}
+
+func Test_If_Server_Is_Not_Ready_While_Shutting_Down(t *testing.T) {
+	checker := NewChecker()
+	checker.Register("database", func(ctx context.Context) error { return nil })
+
+	checker.ShutDown()
+	report := checker.Check(context.Background())
+
+	assert.False(t, report.IsReady())
+	assert.Equal(t, StatusShuttingDown, report.Status)
+	assert.Empty(t, report.Checks)
+}
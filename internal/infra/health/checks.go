@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Database checks that the pool reaches the database.
+func Database(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+type pendingMigrations interface {
+	Pending(ctx context.Context) ([]int64, error)
+}
+
+// Migrations checks that the database schema is the one this binary
+// expects.
+func Migrations(migrator pendingMigrations) Check {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("migrations %v are pending", pending)
+		}
+		return nil
+	}
+}
+
+// Running checks that a background worker did not stop: done is closed when
+// it returns.
+func Running(done <-chan struct{}) Check {
+	return func(ctx context.Context) error {
+		select {
+		case <-done:
+			return errors.New("worker stopped")
+		default:
+			return nil
+		}
+	}
+}
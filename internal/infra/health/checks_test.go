@@ -0,0 +1,46 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type migratorStub struct {
+	pending []int64
+	err     error
+}
+
+func (m migratorStub) Pending(ctx context.Context) ([]int64, error) {
+	return m.pending, m.err
+}
+
+func Test_Database_Check_Pings_The_Pool(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	defer db.Close()
+
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	check := Database(db)
+	assert.Nil(t, check(context.Background()))
+	assert.EqualError(t, check(context.Background()), "connection refused")
+}
+
+func Test_Migrations_Check_Fails_With_Pending_Migrations(t *testing.T) {
+	assert.Nil(t, Migrations(migratorStub{pending: []int64{}})(context.Background()))
+	assert.EqualError(t, Migrations(migratorStub{pending: []int64{12, 13}})(context.Background()), "migrations [12 13] are pending")
+	assert.EqualError(t, Migrations(migratorStub{err: errors.New("connection refused")})(context.Background()), "connection refused")
+}
+
+func Test_Running_Check_Fails_Once_The_Worker_Stops(t *testing.T) {
+	done := make(chan struct{})
+	check := Running(done)
+
+	assert.Nil(t, check(context.Background()))
+	close(done)
+	assert.EqualError(t, check(context.Background()), "worker stopped")
+}
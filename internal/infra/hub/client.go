@@ -0,0 +1,151 @@
+package hub
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 8192
+	sendBufferSize = 256
+
+	ErrorEvent = "error"
+
+	tokenExpiredReason = "access token expired"
+)
+
+type Client struct {
+	UserID int32
+
+	hub       *Hub
+	conn      *websocket.Conn
+	send      chan []byte
+	closeOnce sync.Once
+}
+
+func newClient(h *Hub, conn *websocket.Conn, userID int32) *Client {
+	return &Client{
+		UserID: userID,
+		hub:    h,
+		conn:   conn,
+		send:   make(chan []byte, sendBufferSize),
+	}
+}
+
+// Send delivers an event to this connection only.
+func (c *Client) Send(event domain.Event) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	c.hub.mu.RLock()
+	defer c.hub.mu.RUnlock()
+	c.enqueue(message)
+}
+
+// Messages yields the encoded events queued for a subscribed client. It is
+// closed when the client is unsubscribed or could not keep up.
+func (c *Client) Messages() <-chan []byte {
+	return c.send
+}
+
+func (c *Client) SendError(err error) {
+	event, _ := domain.NewEvent(ErrorEvent, domain.NewProblem(err))
+	c.Send(event)
+}
+
+// enqueue must be called with the hub lock held so the send channel cannot be
+// closed concurrently. A client that cannot keep up is disconnected.
+func (c *Client) enqueue(message []byte) {
+	if _, ok := c.hub.clients[c.UserID][c]; !ok {
+		return
+	}
+
+	select {
+	case c.send <- message:
+	default:
+		go c.hub.unregister(c)
+	}
+}
+
+func (c *Client) closeSend() {
+	c.closeOnce.Do(func() {
+		close(c.send)
+	})
+}
+
+func (c *Client) readPump(handler EventHandler) {
+	defer func() {
+		c.hub.unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var event domain.Event
+		if err := json.Unmarshal(data, &event); err != nil || event.Type == "" {
+			c.SendError(domain.NewError(domain.ErrBadRequest, "invalid event"))
+			continue
+		}
+
+		handler(c, event)
+	}
+}
+
+func (c *Client) writePump(expiresAt time.Time) {
+	ticker := time.NewTicker(pingPeriod)
+
+	var expired <-chan time.Time
+	if !expiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(expiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-expired:
+			// The client reconnects with a fresh access token.
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, tokenExpiredReason))
+			return
+		case message, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/gorilla/websocket"
+)
+
+type EventHandler func(client *Client, event domain.Event)
+
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[int32]map[*Client]struct{}
+	closed  bool
+	// serving counts the WebSocket connections Serve is holding.
+	serving sync.WaitGroup
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[int32]map[*Client]struct{}),
+	}
+}
+
+// Serve registers the connection for the user and blocks until it is closed.
+// Every event read from the socket is passed to handler. The connection is
+// closed once expiresAt, the expiry of the access token it was opened with,
+// passes; a zero expiresAt keeps it open.
+func (h *Hub) Serve(conn *websocket.Conn, userID int32, expiresAt time.Time, handler EventHandler) {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+		conn.Close()
+		return
+	}
+	h.serving.Add(1)
+	h.mu.Unlock()
+	defer h.serving.Done()
+
+	client := newClient(h, conn, userID)
+	h.register(client)
+
+	go client.writePump(expiresAt)
+	client.readPump(handler)
+}
+
+// Subscribe registers a connection that is not a WebSocket, such as a
+// server-sent events stream. The caller drains Messages until it is closed
+// and must Unsubscribe once the connection ends.
+func (h *Hub) Subscribe(userID int32) *Client {
+	client := newClient(h, nil, userID)
+	h.register(client)
+	return client
+}
+
+func (h *Hub) Unsubscribe(client *Client) {
+	h.unregister(client)
+}
+
+func (h *Hub) SendToUsers(userIDs []int32, event domain.Event) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, userID := range userIDs {
+		for client := range h.clients[userID] {
+			client.enqueue(message)
+		}
+	}
+}
+
+func (h *Hub) Broadcast(event domain.Event) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, userClients := range h.clients {
+		for client := range userClients {
+			client.enqueue(message)
+		}
+	}
+}
+
+// Deliver hands an event received from the bus to the connections of the
+// users. A session.revoked event is the last they get: the connections are
+// closed right after it, Messages of subscribed clients included.
+func (h *Hub) Deliver(userIDs []int32, event domain.Event) {
+	h.SendToUsers(userIDs, event)
+
+	if event.Type == domain.SessionRevokedEvent {
+		h.Disconnect(userIDs)
+	}
+}
+
+// Disconnect closes every connection of the users. Events already queued are
+// still written before the close.
+func (h *Hub) Disconnect(userIDs []int32) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userID := range userIDs {
+		for client := range h.clients[userID] {
+			client.closeSend()
+		}
+		delete(h.clients, userID)
+	}
+}
+
+func (h *Hub) IsOnline(userID int32) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID]) > 0
+}
+
+func (h *Hub) ConnectionCount(userID int32) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID])
+}
+
+// Shutdown disconnects every client and refuses new ones, then waits for the
+// WebSocket connections to close or ctx to be done. Subscribed clients see
+// their Messages closed.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for _, userClients := range h.clients {
+		for client := range userClients {
+			client.closeSend()
+		}
+	}
+	h.clients = make(map[int32]map[*Client]struct{})
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.serving.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) register(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		client.closeSend()
+		return
+	}
+
+	userClients, ok := h.clients[client.UserID]
+	if !ok {
+		userClients = make(map[*Client]struct{})
+		h.clients[client.UserID] = userClients
+	}
+	userClients[client] = struct{}{}
+}
+
+func (h *Hub) unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userClients, ok := h.clients[client.UserID]
+	if !ok {
+		return
+	}
+	if _, ok := userClients[client]; !ok {
+		return
+	}
+
+	delete(userClients, client)
+	client.closeSend()
+
+	if len(userClients) == 0 {
+		delete(h.clients, client.UserID)
+	}
+}
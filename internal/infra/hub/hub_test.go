@@ -0,0 +1,233 @@
+package hub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T, h *Hub, handler EventHandler) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := strconv.Atoi(r.URL.Query().Get("user"))
+		var expiresAt time.Time
+		if ttl, err := time.ParseDuration(r.URL.Query().Get("ttl")); err == nil {
+			expiresAt = time.Now().Add(ttl)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade error: %s", err)
+			return
+		}
+		h.Serve(conn, int32(userID), expiresAt, handler)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server, userID int) *websocket.Conn {
+	return dialURL(t, "ws"+strings.TrimPrefix(server.URL, "http")+"?user="+strconv.Itoa(userID))
+}
+
+// dialWithTTL connects as if with an access token expiring after ttl.
+func dialWithTTL(t *testing.T, server *httptest.Server, userID int, ttl time.Duration) *websocket.Conn {
+	return dialURL(t, "ws"+strings.TrimPrefix(server.URL, "http")+"?user="+strconv.Itoa(userID)+"&ttl="+ttl.String())
+}
+
+func dialURL(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition was not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) domain.Event {
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var event domain.Event
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+	return event
+}
+
+func Test_If_Event_Is_Sent_To_Every_Connection_Of_The_User(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(*Client, domain.Event) {})
+
+	first := dial(t, server, 1)
+	second := dial(t, server, 1)
+	other := dial(t, server, 2)
+	waitFor(t, func() bool { return h.ConnectionCount(1) == 2 && h.IsOnline(2) })
+
+	event, _ := domain.NewEvent("message.new", map[string]string{"body": "hello"})
+	h.SendToUsers([]int32{1}, event)
+
+	assert.Equal(t, "message.new", readEvent(t, first).Type)
+	assert.Equal(t, "message.new", readEvent(t, second).Type)
+
+	_ = other.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err := other.ReadMessage()
+	assert.Error(t, err)
+}
+
+func Test_If_Inbound_Event_Is_Handled_And_Broadcast(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(client *Client, event domain.Event) {
+		h.Broadcast(event)
+	})
+
+	sender := dial(t, server, 1)
+	receiver := dial(t, server, 2)
+	waitFor(t, func() bool { return h.IsOnline(1) && h.IsOnline(2) })
+
+	assert.Nil(t, sender.WriteJSON(domain.Event{Type: "message.send"}))
+
+	assert.Equal(t, "message.send", readEvent(t, receiver).Type)
+	assert.Equal(t, "message.send", readEvent(t, sender).Type)
+}
+
+func Test_If_Invalid_Event_Gets_An_Error_Event(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(*Client, domain.Event) {})
+
+	conn := dial(t, server, 1)
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+
+	assert.Equal(t, ErrorEvent, readEvent(t, conn).Type)
+}
+
+func Test_If_Client_Is_Removed_On_Disconnect(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(*Client, domain.Event) {})
+
+	conn := dial(t, server, 1)
+	waitFor(t, func() bool { return h.IsOnline(1) })
+
+	conn.Close()
+	waitFor(t, func() bool { return !h.IsOnline(1) })
+}
+
+func Test_If_Subscriber_Receives_Events_Until_Unsubscribed(t *testing.T) {
+	h := NewHub()
+
+	subscriber := h.Subscribe(1)
+	assert.True(t, h.IsOnline(1))
+
+	event, _ := domain.NewEvent("message.new", map[string]string{"body": "hello"})
+	h.SendToUsers([]int32{1}, event)
+
+	select {
+	case message := <-subscriber.Messages():
+		assert.Contains(t, string(message), "message.new")
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber did not receive the event")
+	}
+
+	h.Unsubscribe(subscriber)
+	assert.False(t, h.IsOnline(1))
+
+	_, ok := <-subscriber.Messages()
+	assert.False(t, ok)
+}
+
+func Test_If_Shutdown_Closes_Every_Connection(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(*Client, domain.Event) {})
+
+	conn := dial(t, server, 1)
+	subscriber := h.Subscribe(2)
+	waitFor(t, func() bool { return h.IsOnline(1) && h.IsOnline(2) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	assert.Nil(t, h.Shutdown(ctx))
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNoStatusReceived), err)
+
+	_, ok := <-subscriber.Messages()
+	assert.False(t, ok)
+	assert.False(t, h.IsOnline(1))
+	assert.False(t, h.IsOnline(2))
+}
+
+func Test_If_Closed_Hub_Refuses_New_Clients(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(*Client, domain.Event) {})
+	assert.Nil(t, h.Shutdown(context.Background()))
+
+	subscriber := h.Subscribe(1)
+	_, ok := <-subscriber.Messages()
+	assert.False(t, ok)
+
+	conn := dial(t, server, 1)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+	assert.False(t, h.IsOnline(1))
+}
+
+func Test_If_Connection_Is_Closed_When_The_Access_Token_Expires(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(*Client, domain.Event) {})
+
+	conn := dialWithTTL(t, server, 1, 100*time.Millisecond)
+	waitFor(t, func() bool { return h.IsOnline(1) })
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err)
+	waitFor(t, func() bool { return !h.IsOnline(1) })
+}
+
+func Test_If_Session_Revoked_Event_Disconnects_The_User(t *testing.T) {
+	h := NewHub()
+	server := newTestServer(t, h, func(*Client, domain.Event) {})
+
+	conn := dial(t, server, 1)
+	subscriber := h.Subscribe(1)
+	other := dial(t, server, 2)
+	waitFor(t, func() bool { return h.ConnectionCount(1) == 2 && h.IsOnline(2) })
+
+	event, _ := domain.NewEvent(domain.SessionRevokedEvent, domain.SessionRevokedPayload{Reason: domain.SessionRevokedByLogout})
+	h.Deliver([]int32{1}, event)
+
+	assert.Equal(t, domain.SessionRevokedEvent, readEvent(t, conn).Type)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNoStatusReceived), err)
+
+	message, ok := <-subscriber.Messages()
+	assert.True(t, ok)
+	assert.Contains(t, string(message), domain.SessionRevokedEvent)
+	_, ok = <-subscriber.Messages()
+	assert.False(t, ok)
+
+	assert.False(t, h.IsOnline(1))
+	assert.True(t, h.IsOnline(2))
+
+	newMessage, _ := domain.NewEvent("message.new", map[string]string{"body": "hello"})
+	h.SendToUsers([]int32{2}, newMessage)
+	assert.Equal(t, "message.new", readEvent(t, other).Type)
+}
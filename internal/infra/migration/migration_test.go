@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Embedded_Migrations_Are_Loaded_In_Order(t *testing.T) {
+	migrations, err := Embedded()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, int64(i+1), migration.Version, "versions must follow each other")
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+	}
+	assert.Equal(t, "create_app_user", migrations[0].Name)
+}
+
+// baselineAppUser is the table init-db.sql created before migrations existed.
+// 0001 is skipped on such databases, so it must not add anything to it.
+const baselineAppUser = `CREATE TABLE IF NOT EXISTS app_user (
+  id serial,
+  username varchar(50) NOT NULL,
+  displayname varchar(255),
+  email varchar(150) NOT NULL,
+  password varchar(150) NOT NULL,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+`
+
+func Test_First_Migration_Matches_The_Baseline_Schema(t *testing.T) {
+	migrations, err := Embedded()
+	assert.Nil(t, err)
+
+	assert.Equal(t, baselineAppUser, migrations[0].Up)
+}
+
+func Test_Migrations_Are_Sorted_By_Version(t *testing.T) {
+	files := fstest.MapFS{
+		"0010_add_index.up.sql":      {Data: []byte("CREATE INDEX i ON t (c);")},
+		"0010_add_index.down.sql":    {Data: []byte("DROP INDEX i;")},
+		"0002_create_table.up.sql":   {Data: []byte("CREATE TABLE t (c int);")},
+		"0002_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+	}
+
+	migrations, err := Load(files)
+	assert.Nil(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, int64(2), migrations[0].Version)
+	assert.Equal(t, "create_table", migrations[0].Name)
+	assert.Equal(t, "DROP INDEX i;", migrations[1].Down)
+}
+
+func Test_Migration_Without_Down_File_Is_Rejected(t *testing.T) {
+	files := fstest.MapFS{
+		"0001_create_table.up.sql": {Data: []byte("CREATE TABLE t (c int);")},
+	}
+
+	_, err := Load(files)
+	assert.ErrorContains(t, err, "needs both an up and a down file")
+}
+
+func Test_Migration_Version_Used_Twice_Is_Rejected(t *testing.T) {
+	files := fstest.MapFS{
+		"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (c int);")},
+		"0001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+		"0001_create_other.up.sql":   {Data: []byte("CREATE TABLE o (c int);")},
+		"0001_create_other.down.sql": {Data: []byte("DROP TABLE o;")},
+	}
+
+	_, err := Load(files)
+	assert.ErrorContains(t, err, "migration version 1 is used by")
+}
+
+func Test_Migration_With_Invalid_Name_Is_Rejected(t *testing.T) {
+	files := fstest.MapFS{
+		"create_table.sql": {Data: []byte("CREATE TABLE t (c int);")},
+	}
+
+	_, err := Load(files)
+	assert.ErrorContains(t, err, "is not valid")
+}
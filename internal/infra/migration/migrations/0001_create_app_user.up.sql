@@ -1,8 +1,3 @@
-SELECT 'CREATE DATABASE chat_server'
-WHERE NOT EXISTS (SELECT FROM pg_database WHERE datname = 'chat_server')\gexec
-
-\connect chat_server
-
 CREATE TABLE IF NOT EXISTS app_user (
   id serial,
   username varchar(50) NOT NULL,
@@ -11,4 +6,4 @@ CREATE TABLE IF NOT EXISTS app_user (
   password varchar(150) NOT NULL,
   created timestamp NOT NULL,
   PRIMARY KEY (id)
-)\gexec
\ No newline at end of file
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS refresh_token (
+  id serial,
+  user_id integer NOT NULL REFERENCES app_user (id) ON DELETE CASCADE,
+  family_id varchar(64) NOT NULL,
+  token_hash varchar(64) NOT NULL UNIQUE,
+  expires_at timestamp NOT NULL,
+  rotated_at timestamp,
+  revoked_at timestamp,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS refresh_token_family_id_idx ON refresh_token (family_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS room (
+  id serial,
+  name varchar(50) NOT NULL UNIQUE,
+  topic varchar(250) NOT NULL DEFAULT '',
+  visibility varchar(10) NOT NULL,
+  kind varchar(10) NOT NULL DEFAULT 'channel',
+  owner_id integer NOT NULL REFERENCES app_user (id),
+  archived_at timestamp,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id),
+  CHECK (visibility IN ('public', 'private')),
+  CHECK (kind IN ('channel', 'direct'))
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS room_member (
+  room_id integer NOT NULL REFERENCES room (id) ON DELETE CASCADE,
+  user_id integer NOT NULL REFERENCES app_user (id) ON DELETE CASCADE,
+  role varchar(10) NOT NULL,
+  status varchar(10) NOT NULL,
+  invited_by integer REFERENCES app_user (id) ON DELETE SET NULL,
+  created timestamp NOT NULL,
+  PRIMARY KEY (room_id, user_id),
+  CHECK (role IN ('owner', 'admin', 'member')),
+  CHECK (status IN ('invited', 'active', 'banned'))
+);
+
+CREATE INDEX IF NOT EXISTS room_member_user_id_idx ON room_member (user_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS message (
+  id bigserial,
+  room_id integer NOT NULL REFERENCES room (id) ON DELETE CASCADE,
+  sender_id integer NOT NULL REFERENCES app_user (id),
+  body varchar(4000) NOT NULL,
+  edited_at timestamp,
+  deleted_at timestamp,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS message_room_id_id_idx ON message (room_id, id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS message_edit (
+  id bigserial,
+  message_id bigint NOT NULL REFERENCES message (id) ON DELETE CASCADE,
+  body varchar(4000) NOT NULL,
+  edited_by integer NOT NULL REFERENCES app_user (id),
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS message_edit_message_id_idx ON message_edit (message_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS read_receipt (
+  room_id integer NOT NULL,
+  user_id integer NOT NULL,
+  message_id bigint NOT NULL REFERENCES message (id) ON DELETE CASCADE,
+  updated timestamp NOT NULL,
+  PRIMARY KEY (room_id, user_id),
+  FOREIGN KEY (room_id, user_id) REFERENCES room_member (room_id, user_id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS bus_event;
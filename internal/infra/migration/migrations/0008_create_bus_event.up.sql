@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS bus_event (
+  id bigserial,
+  payload text NOT NULL,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
@@ -0,0 +1,3 @@
+-- 0001 matches the table databases created before migrations already have,
+-- so the columns added since then come in migrations of their own.
+ALTER TABLE app_user ADD COLUMN IF NOT EXISTS last_seen timestamp;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS app_user_email_key;
+
+DROP INDEX IF EXISTS app_user_username_key;
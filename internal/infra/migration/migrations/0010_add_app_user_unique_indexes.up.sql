@@ -0,0 +1,3 @@
+CREATE UNIQUE INDEX IF NOT EXISTS app_user_username_key ON app_user (lower(username));
+
+CREATE UNIQUE INDEX IF NOT EXISTS app_user_email_key ON app_user (lower(email));
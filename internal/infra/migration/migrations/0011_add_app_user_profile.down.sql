@@ -0,0 +1,3 @@
+ALTER TABLE app_user DROP COLUMN IF EXISTS avatar_url;
+
+ALTER TABLE app_user DROP COLUMN IF EXISTS bio;
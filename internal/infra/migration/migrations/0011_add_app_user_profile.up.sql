@@ -0,0 +1,3 @@
+ALTER TABLE app_user ADD COLUMN IF NOT EXISTS bio varchar(500) NOT NULL DEFAULT '';
+
+ALTER TABLE app_user ADD COLUMN IF NOT EXISTS avatar_url varchar(2048) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS refresh_token_user_id_idx;
+
+DROP TABLE IF EXISTS password_reset_token;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS password_reset_token (
+  id serial,
+  user_id integer NOT NULL REFERENCES app_user (id) ON DELETE CASCADE,
+  token_hash varchar(64) NOT NULL UNIQUE,
+  expires_at timestamp NOT NULL,
+  used_at timestamp,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS password_reset_token_user_id_idx ON password_reset_token (user_id);
+
+CREATE INDEX IF NOT EXISTS refresh_token_user_id_idx ON refresh_token (user_id);
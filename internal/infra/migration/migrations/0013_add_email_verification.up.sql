@@ -0,0 +1,16 @@
+ALTER TABLE app_user ADD COLUMN IF NOT EXISTS email_verified_at timestamp;
+
+-- Accounts created before verification existed are trusted as they are.
+UPDATE app_user SET email_verified_at = created WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verification_token (
+  id serial,
+  user_id integer NOT NULL REFERENCES app_user (id) ON DELETE CASCADE,
+  token_hash varchar(64) NOT NULL UNIQUE,
+  expires_at timestamp NOT NULL,
+  used_at timestamp,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS email_verification_token_user_id_idx ON email_verification_token (user_id, created);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS login_attempt (
+  key varchar(320) NOT NULL,
+  failures integer NOT NULL,
+  last_failure_at timestamp NOT NULL,
+  locked_until timestamp,
+  PRIMARY KEY (key)
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+  id serial,
+  action varchar(64) NOT NULL,
+  user_id integer REFERENCES app_user (id) ON DELETE SET NULL,
+  ip varchar(64) NOT NULL DEFAULT '',
+  detail text NOT NULL DEFAULT '',
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_user_id_idx ON audit_log (user_id, created);
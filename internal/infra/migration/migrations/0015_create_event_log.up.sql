@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS event_log (
+  id bigserial,
+  user_ids integer[] NOT NULL,
+  type varchar(50) NOT NULL,
+  payload text NOT NULL,
+  created timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS event_log_user_ids_idx ON event_log USING GIN (user_ids);
+CREATE INDEX IF NOT EXISTS event_log_created_idx ON event_log (created);
@@ -0,0 +1 @@
+ALTER TABLE app_user DROP COLUMN IF EXISTS password_changed_at;
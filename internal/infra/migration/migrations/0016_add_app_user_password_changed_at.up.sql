@@ -0,0 +1 @@
+ALTER TABLE app_user ADD COLUMN IF NOT EXISTS password_changed_at timestamp;
@@ -0,0 +1,189 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// advisoryLockKey serializes migrations across every instance sharing the
+// database. The value is arbitrary but must never change.
+const advisoryLockKey int64 = 7261029384756102
+
+type Migrator struct {
+	Db         *sql.DB
+	Migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		Db:         db,
+		Migrations: migrations,
+	}
+}
+
+// Up applies every pending migration in version order and returns the
+// versions it applied. Each migration runs in its own transaction together
+// with its schema_migrations row, so a failure leaves no half applied step.
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	applied := make([]int64, 0)
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.Migrations {
+			if done[migration.Version] {
+				continue
+			}
+
+			err := inTransaction(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1,$2,$3)",
+					migration.Version, migration.Name, time.Now())
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+
+			applied = append(applied, migration.Version)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the latest applied migrations, at most steps of them, and
+// returns the versions it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	reverted := make([]int64, 0)
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.Migrations[i]
+			if !done[migration.Version] {
+				continue
+			}
+
+			err := inTransaction(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s revert failed: %w", migration.Version, migration.Name, err)
+			}
+
+			reverted = append(reverted, migration.Version)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Pending returns the versions known to this binary that the database has
+// not applied yet.
+func (m *Migrator) Pending(ctx context.Context) ([]int64, error) {
+	conn, err := m.Db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// A read-only check: a database never migrated has everything pending.
+	var exists bool
+	if err := conn.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	done := make(map[int64]bool)
+	if exists {
+		if done, err = listVersions(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	pending := make([]int64, 0)
+	for _, migration := range m.Migrations {
+		if !done[migration.Version] {
+			pending = append(pending, migration.Version)
+		}
+	}
+	return pending, nil
+}
+
+// withLock holds a session advisory lock for the whole run. The lock belongs
+// to one connection, so every statement goes through that same connection.
+func (m *Migrator) withLock(ctx context.Context, run func(conn *sql.Conn) error) error {
+	conn, err := m.Db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+
+	return run(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]bool, error) {
+	_, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations ("+
+		"version bigint NOT NULL, name varchar(255) NOT NULL, applied_at timestamp NOT NULL, PRIMARY KEY (version))")
+	if err != nil {
+		return nil, err
+	}
+
+	return listVersions(ctx, conn)
+}
+
+func listVersions(ctx context.Context, conn *sql.Conn) (map[int64]bool, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions[version] = true
+	}
+
+	return versions, rows.Err()
+}
+
+func inTransaction(ctx context.Context, conn *sql.Conn, run func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := run(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
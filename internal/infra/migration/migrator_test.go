@@ -0,0 +1,99 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_user", Up: "CREATE TABLE app_user (id serial)", Down: "DROP TABLE app_user"},
+	{Version: 2, Name: "create_room", Up: "CREATE TABLE room (id serial)", Down: "DROP TABLE room"},
+	{Version: 3, Name: "create_message", Up: "CREATE TABLE message (id serial)", Down: "DROP TABLE message"},
+}
+
+func expectLockAndVersions(mock sqlmock.Sqlmock, versions ...int64) {
+	mock.ExpectExec("SELECT pg_advisory_lock\\(\\$1\\)").WithArgs(advisoryLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, version := range versions {
+		rows.AddRow(version)
+	}
+	mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(rows)
+}
+
+func Test_Pending_Migrations_Are_Applied_Under_The_Lock(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectLockAndVersions(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE room").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), "create_room", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE message").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(3), "create_message", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(\\$1\\)").WithArgs(advisoryLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := NewMigrator(db, testMigrations).Up(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2, 3}, applied)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Failed_Migration_Is_Rolled_Back_And_Stops_The_Run(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectLockAndVersions(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE room").WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := NewMigrator(db, testMigrations).Up(context.Background())
+	assert.ErrorContains(t, err, "migration 2_create_room failed")
+	assert.Empty(t, applied)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Latest_Migrations_Are_Reverted(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectLockAndVersions(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE room").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations WHERE version = \\$1").WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := NewMigrator(db, testMigrations).Down(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, reverted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Pending_Lists_Unapplied_Versions_Without_Writing(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT to_regclass\\('schema_migrations'\\) IS NOT NULL").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+
+	pending, err := NewMigrator(db, testMigrations).Pending(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{3}, pending)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Everything_Is_Pending_On_A_Fresh_Database(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT to_regclass").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	pending, err := NewMigrator(db, testMigrations).Pending(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, pending)
+}
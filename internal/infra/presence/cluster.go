@@ -0,0 +1,227 @@
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+// presenceSyncEvent carries the presence of the users connected to one
+// instance to the trackers of the others. It travels on the bus addressed to
+// no user, so hubs never relay it to clients.
+const presenceSyncEvent = "presence.sync"
+
+const (
+	// syncUpdate reports users whose status changed on the instance.
+	syncUpdate = "update"
+	// syncSnapshot reports every user connected to the instance, replacing
+	// what it reported before. It is the heartbeat of the instance.
+	syncSnapshot = "snapshot"
+	// syncHello is the snapshot of an instance that just started, which the
+	// others answer with theirs.
+	syncHello = "hello"
+	// syncGoodbye drops everything the instance reported.
+	syncGoodbye = "goodbye"
+)
+
+// missedHeartbeats is how many heartbeats of an instance may go missing
+// before what it reported is dropped, as it most likely stopped.
+const missedHeartbeats = 3
+
+type syncMessage struct {
+	Instance  string            `json:"instance"`
+	Kind      string            `json:"kind"`
+	Presences []PresencePayload `json:"presences,omitempty"`
+}
+
+type remotePresence struct {
+	status    domain.PresenceStatus
+	lastSeen  time.Time
+	expiresAt time.Time
+}
+
+// Share makes the tracker exchange presence with the trackers of the other
+// instances over eventBus, so a user connected to any instance is online on
+// all of them. Each instance announces the changes of its own users, and all
+// of them every heartbeatInterval. It must be called before Run.
+//
+// The instance whose change moves the merged presence of a user publishes
+// presence.updated. When an instance stops, every other one does, so
+// contacts may get the same offline presence more than once.
+func (t *Tracker) Share(eventBus domain.EventBusInterface, instanceID string, heartbeatInterval time.Duration) {
+	t.bus = eventBus
+	t.instanceID = instanceID
+	t.heartbeatInterval = heartbeatInterval
+
+	eventBus.Subscribe(t.receive)
+}
+
+// share announces a change of the presence of a user on this instance.
+func (t *Tracker) share(local PresencePayload) {
+	if t.bus == nil {
+		return
+	}
+	t.send(syncMessage{Kind: syncUpdate, Presences: []PresencePayload{local}})
+}
+
+// send is best effort: a missed message is made up for by the next
+// heartbeat.
+func (t *Tracker) send(message syncMessage) {
+	message.Instance = t.instanceID
+
+	event, err := domain.NewEvent(presenceSyncEvent, message)
+	if err != nil {
+		return
+	}
+
+	if err := t.bus.Publish(nil, event); err != nil {
+		t.logger.Warn(context.Background(), "presence - could not possible to share presence", "kind", message.Kind, "error", err)
+	}
+}
+
+func (t *Tracker) receive(_ []int32, event domain.Event) {
+	if event.Type != presenceSyncEvent {
+		return
+	}
+
+	var message syncMessage
+	if err := json.Unmarshal(event.Payload, &message); err != nil || message.Instance == t.instanceID {
+		return
+	}
+
+	switch message.Kind {
+	case syncUpdate:
+		t.update(message.Instance, message.Presences)
+	case syncSnapshot:
+		t.replace(message.Instance, message.Presences)
+	case syncHello:
+		t.replace(message.Instance, message.Presences)
+		t.send(syncMessage{Kind: syncSnapshot, Presences: t.snapshot()})
+	case syncGoodbye:
+		t.replace(message.Instance, nil)
+	}
+}
+
+// update records the changes an instance announced. It publishes nothing:
+// the instance that saw the change did.
+func (t *Tracker) update(instanceID string, presences []PresencePayload) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	expiresAt := time.Now().Add(missedHeartbeats * t.heartbeatInterval)
+	for _, presence := range presences {
+		t.setRemote(instanceID, presence, expiresAt)
+	}
+}
+
+// replace swaps what an instance reported for presences. Users it no longer
+// reports and who are offline everywhere now are published, as the instance
+// may have stopped without announcing it. Everything else was already
+// published by the instance that saw it change.
+func (t *Tracker) replace(instanceID string, presences []PresencePayload) {
+	t.mu.Lock()
+
+	listed := make(map[int32]PresencePayload, len(presences))
+	for _, presence := range presences {
+		listed[presence.UserID] = presence
+	}
+	for userID, instances := range t.remote {
+		if _, ok := instances[instanceID]; ok {
+			if _, ok := listed[userID]; !ok {
+				listed[userID] = PresencePayload{UserID: userID, Status: domain.PresenceOffline}
+			}
+		}
+	}
+
+	expiresAt := time.Now().Add(missedHeartbeats * t.heartbeatInterval)
+	changed := make([]PresencePayload, 0)
+	for userID, presence := range listed {
+		before := t.current(userID)
+		t.setRemote(instanceID, presence, expiresAt)
+		if after := t.current(userID); after.Status != before.Status && after.Status == domain.PresenceOffline {
+			changed = append(changed, keepLastSeen(after, before))
+		}
+	}
+	t.mu.Unlock()
+
+	for _, presence := range changed {
+		t.publish(presence)
+	}
+}
+
+// sweep drops what instances that stopped sending heartbeats reported.
+func (t *Tracker) sweep(now time.Time) {
+	t.mu.Lock()
+
+	changed := make([]PresencePayload, 0)
+	for userID, instances := range t.remote {
+		before := t.current(userID)
+		for instanceID, instance := range instances {
+			if now.After(instance.expiresAt) {
+				delete(instances, instanceID)
+			}
+		}
+		if len(instances) == 0 {
+			delete(t.remote, userID)
+		}
+		if after := t.current(userID); after.Status != before.Status {
+			changed = append(changed, keepLastSeen(after, before))
+		}
+	}
+	t.mu.Unlock()
+
+	for _, presence := range changed {
+		t.publish(presence)
+	}
+}
+
+// snapshot lists the users connected to this instance, or still within
+// their grace period.
+func (t *Tracker) snapshot() []PresencePayload {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	presences := make([]PresencePayload, 0, len(t.users))
+	for userID, user := range t.users {
+		if user.status != domain.PresenceOffline {
+			presences = append(presences, toPresencePayload(userID, user))
+		}
+	}
+	return presences
+}
+
+// setRemote must be called with the lock held. An offline presence removes
+// the instance from the user.
+func (t *Tracker) setRemote(instanceID string, presence PresencePayload, expiresAt time.Time) {
+	instances := t.remote[presence.UserID]
+
+	if presence.Status == domain.PresenceOffline {
+		delete(instances, instanceID)
+		if len(instances) == 0 {
+			delete(t.remote, presence.UserID)
+		}
+		return
+	}
+
+	if instances == nil {
+		instances = make(map[string]*remotePresence)
+		t.remote[presence.UserID] = instances
+	}
+
+	instance := &remotePresence{status: presence.Status, expiresAt: expiresAt}
+	if presence.LastSeen != nil {
+		instance.lastSeen = *presence.LastSeen
+	}
+	instances[instanceID] = instance
+}
+
+// keepLastSeen carries the last seen over to a user that went offline with
+// the instance that reported it.
+func keepLastSeen(after PresencePayload, before PresencePayload) PresencePayload {
+	if after.LastSeen == nil {
+		after.LastSeen = before.LastSeen
+	}
+	return after
+}
@@ -0,0 +1,130 @@
+package presence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/bus"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSharedTracker starts a tracker as a server instance would, sharing
+// presence over eventBus.
+func newSharedTracker(t *testing.T, eventBus domain.EventBusInterface, instanceID string, gracePeriod time.Duration) (*Tracker, *recordingPublisher, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+	tracker := newTracker(db, publisher, gracePeriod)
+	tracker.Share(eventBus, instanceID, time.Minute)
+	return tracker, publisher, mock
+}
+
+func run(t *testing.T, tracker *Tracker) context.CancelFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracker.Run(ctx, time.Minute)
+	}()
+
+	stop := func() {
+		cancel()
+		<-done
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func Test_User_Connected_To_Another_Instance_Is_Online(t *testing.T) {
+	eventBus := bus.NewInProcessBus()
+	first, firstPublisher, firstMock := newSharedTracker(t, eventBus, "first", 20*time.Millisecond)
+	second, secondPublisher, _ := newSharedTracker(t, eventBus, "second", 20*time.Millisecond)
+
+	expectContacts(firstMock)
+	expectContacts(firstMock)
+	expectContacts(firstMock)
+
+	first.Connect(1)
+	assert.Equal(t, domain.PresenceOnline, second.Presence([]int32{1})[0].Status)
+	assert.NotNil(t, second.Presence([]int32{1})[0].LastSeen)
+
+	first.SetStatus(1, domain.PresenceAway)
+	assert.Equal(t, domain.PresenceAway, second.Presence([]int32{1})[0].Status)
+
+	first.Disconnect(1)
+	waitFor(t, func() bool { return second.Presence([]int32{1})[0].Status == domain.PresenceOffline })
+
+	// The instance that saw the changes published them, the other did not.
+	waitFor(t, func() bool { return len(firstPublisher.statuses()) == 3 })
+	assert.Equal(t, []domain.PresenceStatus{domain.PresenceOnline, domain.PresenceAway, domain.PresenceOffline}, firstPublisher.statuses())
+	assert.Empty(t, secondPublisher.statuses())
+}
+
+func Test_User_Stays_Online_While_Connected_To_Another_Instance(t *testing.T) {
+	eventBus := bus.NewInProcessBus()
+	first, firstPublisher, firstMock := newSharedTracker(t, eventBus, "first", time.Millisecond)
+	second, secondPublisher, _ := newSharedTracker(t, eventBus, "second", time.Millisecond)
+
+	expectContacts(firstMock)
+
+	first.Connect(1)
+	second.Connect(1)
+	first.Disconnect(1)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, domain.PresenceOnline, first.Presence([]int32{1})[0].Status)
+	assert.Equal(t, []domain.PresenceStatus{domain.PresenceOnline}, firstPublisher.statuses())
+	assert.Empty(t, secondPublisher.statuses())
+}
+
+func Test_Starting_Instance_Learns_Who_Is_Connected(t *testing.T) {
+	eventBus := bus.NewInProcessBus()
+	first, _, firstMock := newSharedTracker(t, eventBus, "first", time.Minute)
+	expectContacts(firstMock)
+	first.Connect(1)
+
+	second, secondPublisher, _ := newSharedTracker(t, eventBus, "second", time.Minute)
+	assert.Equal(t, domain.PresenceOffline, second.Presence([]int32{1})[0].Status)
+
+	run(t, second)
+	waitFor(t, func() bool { return second.Presence([]int32{1})[0].Status == domain.PresenceOnline })
+	assert.Empty(t, secondPublisher.statuses())
+}
+
+func Test_Users_Of_A_Stopped_Instance_Go_Offline(t *testing.T) {
+	eventBus := bus.NewInProcessBus()
+	first, _, firstMock := newSharedTracker(t, eventBus, "first", time.Minute)
+	second, secondPublisher, secondMock := newSharedTracker(t, eventBus, "second", time.Minute)
+
+	expectContacts(firstMock)
+	firstMock.ExpectExec("UPDATE app_user SET last_seen").WillReturnResult(sqlmock.NewResult(0, 1))
+	expectContacts(secondMock)
+
+	stop := run(t, first)
+	first.Connect(1)
+	assert.Equal(t, domain.PresenceOnline, second.Presence([]int32{1})[0].Status)
+
+	stop()
+	assert.Equal(t, domain.PresenceOffline, second.Presence([]int32{1})[0].Status)
+	assert.Equal(t, []domain.PresenceStatus{domain.PresenceOffline}, secondPublisher.statuses())
+}
+
+func Test_Users_Of_An_Instance_That_Stopped_Sending_Heartbeats_Go_Offline(t *testing.T) {
+	eventBus := bus.NewInProcessBus()
+	first, _, firstMock := newSharedTracker(t, eventBus, "first", time.Minute)
+	second, secondPublisher, secondMock := newSharedTracker(t, eventBus, "second", time.Minute)
+
+	expectContacts(firstMock)
+	expectContacts(secondMock)
+
+	first.Connect(1)
+
+	second.sweep(time.Now().Add(missedHeartbeats * time.Minute / 2))
+	assert.Equal(t, domain.PresenceOnline, second.Presence([]int32{1})[0].Status)
+
+	second.sweep(time.Now().Add(missedHeartbeats*time.Minute + time.Second))
+	assert.Equal(t, domain.PresenceOffline, second.Presence([]int32{1})[0].Status)
+	assert.Equal(t, []domain.PresenceStatus{domain.PresenceOffline}, secondPublisher.statuses())
+}
@@ -0,0 +1,326 @@
+package presence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+)
+
+// PresencePayload is the body of the presence events pushed to the contacts
+// of a user.
+type PresencePayload struct {
+	UserID   int32                 `json:"userId"`
+	Status   domain.PresenceStatus `json:"status"`
+	LastSeen *time.Time            `json:"lastSeen,omitempty"`
+}
+
+type userPresence struct {
+	connections  int
+	status       domain.PresenceStatus
+	lastSeen     time.Time
+	dirty        bool
+	generation   int
+	offlineTimer *time.Timer
+}
+
+// statusRank orders the statuses a user may have on several instances at
+// once: online anywhere wins over away, away over offline.
+var statusRank = map[domain.PresenceStatus]int{
+	domain.PresenceOffline: 0,
+	domain.PresenceAway:    1,
+	domain.PresenceOnline:  2,
+}
+
+// Tracker derives presence from the live connections of each user. A user
+// whose last connection drops stays online for a grace period, so a page
+// reload or a flaky network does not flash them offline to everyone. Last
+// seen is kept in memory and written back by Flush. Connections held by other
+// instances only count once the tracker is shared, see Share.
+type Tracker struct {
+	PresenceRepository     domain.PresenceRepositoryInterface
+	ConversationRepository domain.ConversationRepositoryInterface
+	EventPublisher         domain.EventPublisherInterface
+	GracePeriod            time.Duration
+
+	mu     sync.Mutex
+	users  map[int32]*userPresence
+	logger logger.Interface
+
+	// remote is the presence other instances reported, by user then
+	// instance. It stays empty until the tracker is shared.
+	remote            map[int32]map[string]*remotePresence
+	bus               domain.EventBusInterface
+	instanceID        string
+	heartbeatInterval time.Duration
+}
+
+func NewTracker(presenceRepository domain.PresenceRepositoryInterface, conversationRepository domain.ConversationRepositoryInterface, eventPublisher domain.EventPublisherInterface, gracePeriod time.Duration, l logger.Interface) *Tracker {
+	return &Tracker{
+		PresenceRepository:     presenceRepository,
+		ConversationRepository: conversationRepository,
+		EventPublisher:         eventPublisher,
+		GracePeriod:            gracePeriod,
+		users:                  make(map[int32]*userPresence),
+		logger:                 l,
+		remote:                 make(map[int32]map[string]*remotePresence),
+	}
+}
+
+func (t *Tracker) Connect(userID int32) {
+	t.mu.Lock()
+	user, ok := t.users[userID]
+	if !ok {
+		user = &userPresence{status: domain.PresenceOffline}
+		t.users[userID] = user
+	}
+	before := t.current(userID)
+
+	user.connections++
+	user.generation++
+	if user.offlineTimer != nil {
+		user.offlineTimer.Stop()
+		user.offlineTimer = nil
+	}
+	t.touch(user)
+
+	changed := user.status != domain.PresenceOnline
+	user.status = domain.PresenceOnline
+	local := toPresencePayload(userID, user)
+	after := t.current(userID)
+	t.mu.Unlock()
+
+	if changed {
+		t.share(local)
+	}
+	if after.Status != before.Status {
+		t.publish(after)
+	}
+}
+
+func (t *Tracker) Disconnect(userID int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	user, ok := t.users[userID]
+	if !ok || user.connections == 0 {
+		return
+	}
+
+	user.connections--
+	t.touch(user)
+
+	if user.connections > 0 {
+		return
+	}
+
+	user.generation++
+	generation := user.generation
+	user.offlineTimer = time.AfterFunc(t.GracePeriod, func() {
+		t.expire(userID, generation)
+	})
+}
+
+// SetStatus records a status reported by one of the user connections. It is
+// ignored once the user has no connection left.
+func (t *Tracker) SetStatus(userID int32, status domain.PresenceStatus) {
+	t.mu.Lock()
+	user, ok := t.users[userID]
+	if !ok || user.connections == 0 || user.status == status {
+		t.mu.Unlock()
+		return
+	}
+	before := t.current(userID)
+
+	user.status = status
+	t.touch(user)
+	local := toPresencePayload(userID, user)
+	after := t.current(userID)
+	t.mu.Unlock()
+
+	t.share(local)
+	if after.Status != before.Status {
+		t.publish(after)
+	}
+}
+
+// Touch marks activity on a connection of the user. It only updates memory,
+// the database catches up on the next flush.
+func (t *Tracker) Touch(userID int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if user, ok := t.users[userID]; ok && user.connections > 0 {
+		t.touch(user)
+	}
+}
+
+// Presence answers from memory only, merging what other instances reported
+// when shared. Users no instance knows anything about are offline without a
+// last seen, the caller may look it up.
+func (t *Tracker) Presence(userIDs []int32) []*domain.Presence {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	presences := make([]*domain.Presence, 0, len(userIDs))
+	for _, userID := range userIDs {
+		current := t.current(userID)
+		presences = append(presences, &domain.Presence{UserID: userID, Status: current.Status, LastSeen: current.LastSeen})
+	}
+
+	return presences
+}
+
+// Flush writes every last seen changed since the previous flush. Users that
+// went offline are forgotten once written.
+func (t *Tracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	pending := make(map[int32]time.Time)
+	for userID, user := range t.users {
+		if user.dirty {
+			pending[userID] = user.lastSeen
+			user.dirty = false
+		}
+	}
+	t.mu.Unlock()
+
+	var flushErr error
+	failed := make(map[int32]bool)
+	for userID, lastSeen := range pending {
+		if err := t.PresenceRepository.SaveLastSeen(ctx, userID, lastSeen); err != nil {
+			failed[userID] = true
+			flushErr = err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for userID, user := range t.users {
+		if failed[userID] {
+			user.dirty = true
+		}
+		if !user.dirty && user.connections == 0 && user.status == domain.PresenceOffline {
+			delete(t.users, userID)
+		}
+	}
+
+	return flushErr
+}
+
+// Run flushes last seen every interval until ctx is done, then one last time
+// with a context that is not canceled. A shared tracker also sends its
+// heartbeats meanwhile, and says goodbye to the other instances on the way
+// out.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var heartbeat <-chan time.Time
+	if t.bus != nil {
+		heartbeatTicker := time.NewTicker(t.heartbeatInterval)
+		defer heartbeatTicker.Stop()
+		heartbeat = heartbeatTicker.C
+		t.send(syncMessage{Kind: syncHello, Presences: t.snapshot()})
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			t.flushAndReport(ctx)
+		case <-heartbeat:
+			t.send(syncMessage{Kind: syncSnapshot, Presences: t.snapshot()})
+			t.sweep(time.Now())
+		case <-ctx.Done():
+			if t.bus != nil {
+				t.send(syncMessage{Kind: syncGoodbye})
+			}
+			t.flushAndReport(context.WithoutCancel(ctx))
+			return
+		}
+	}
+}
+
+func (t *Tracker) flushAndReport(ctx context.Context) {
+	if err := t.Flush(ctx); err != nil {
+		t.logger.Error(ctx, "presence - could not possible to flush last seen", "error", err)
+	}
+}
+
+func (t *Tracker) expire(userID int32, generation int) {
+	t.mu.Lock()
+	user, ok := t.users[userID]
+	if !ok || user.generation != generation || user.connections > 0 {
+		t.mu.Unlock()
+		return
+	}
+	before := t.current(userID)
+
+	user.status = domain.PresenceOffline
+	user.offlineTimer = nil
+	local := toPresencePayload(userID, user)
+	after := t.current(userID)
+	t.mu.Unlock()
+
+	t.share(local)
+	if after.Status != before.Status {
+		t.publish(after)
+	}
+}
+
+// current merges the presence of the user on this instance with the one
+// other instances reported. It must be called with the lock held.
+func (t *Tracker) current(userID int32) PresencePayload {
+	current := PresencePayload{UserID: userID, Status: domain.PresenceOffline}
+
+	var lastSeen time.Time
+	known := false
+	if user, ok := t.users[userID]; ok {
+		current.Status = user.status
+		lastSeen = user.lastSeen
+		known = true
+	}
+	for _, instance := range t.remote[userID] {
+		if statusRank[instance.status] > statusRank[current.Status] {
+			current.Status = instance.status
+		}
+		if instance.lastSeen.After(lastSeen) {
+			lastSeen = instance.lastSeen
+		}
+		known = true
+	}
+
+	if known {
+		current.LastSeen = &lastSeen
+	}
+	return current
+}
+
+// touch must be called with the lock held.
+func (t *Tracker) touch(user *userPresence) {
+	user.lastSeen = time.Now()
+	user.dirty = true
+}
+
+// publish is best effort: contacts that miss the event can still ask for the
+// current presence through the REST API. It runs after connections close and
+// from grace period timers, so it is not bound to any request.
+func (t *Tracker) publish(payload PresencePayload) {
+	contactIDs, err := t.ConversationRepository.ListContactIDs(context.Background(), payload.UserID)
+	if err != nil || len(contactIDs) == 0 {
+		return
+	}
+
+	event, err := domain.NewEvent(domain.PresenceUpdatedEvent, payload)
+	if err != nil {
+		return
+	}
+
+	t.EventPublisher.SendToUsers(contactIDs, event)
+}
+
+func toPresencePayload(userID int32, user *userPresence) PresencePayload {
+	lastSeen := user.lastSeen
+	return PresencePayload{UserID: userID, Status: user.status, LastSeen: &lastSeen}
+}
@@ -0,0 +1,162 @@
+package presence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (p *recordingPublisher) SendToUsers(userIDs []int32, event domain.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) statuses() []domain.PresenceStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := make([]domain.PresenceStatus, 0, len(p.events))
+	for _, event := range p.events {
+		var payload PresencePayload
+		_ = json.Unmarshal(event.Payload, &payload)
+		statuses = append(statuses, payload.Status)
+	}
+	return statuses
+}
+
+func newTracker(db *sql.DB, publisher domain.EventPublisherInterface, gracePeriod time.Duration) *Tracker {
+	return NewTracker(repository.NewPresenceRepository(db), repository.NewConversationRepository(db), publisher, gracePeriod, logger.Discard())
+}
+
+func expectContacts(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT DISTINCT other.user_id FROM room_member").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition was not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func Test_User_Goes_Offline_After_The_Grace_Period(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+	tracker := newTracker(db, publisher, 20*time.Millisecond)
+
+	expectContacts(mock)
+	expectContacts(mock)
+
+	tracker.Connect(1)
+	assert.Equal(t, domain.PresenceOnline, tracker.Presence([]int32{1})[0].Status)
+
+	tracker.Disconnect(1)
+	assert.Equal(t, domain.PresenceOnline, tracker.Presence([]int32{1})[0].Status)
+
+	waitFor(t, func() bool { return tracker.Presence([]int32{1})[0].Status == domain.PresenceOffline })
+	waitFor(t, func() bool { return len(publisher.statuses()) == 2 })
+	assert.Equal(t, []domain.PresenceStatus{domain.PresenceOnline, domain.PresenceOffline}, publisher.statuses())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Reconnecting_Within_The_Grace_Period_Stays_Online(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+	tracker := newTracker(db, publisher, 30*time.Millisecond)
+
+	expectContacts(mock)
+
+	tracker.Connect(1)
+	tracker.Disconnect(1)
+	tracker.Connect(1)
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, domain.PresenceOnline, tracker.Presence([]int32{1})[0].Status)
+	assert.Equal(t, []domain.PresenceStatus{domain.PresenceOnline}, publisher.statuses())
+}
+
+func Test_User_Stays_Online_While_Another_Connection_Is_Open(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	tracker := newTracker(db, &recordingPublisher{}, time.Millisecond)
+
+	expectContacts(mock)
+
+	tracker.Connect(1)
+	tracker.Connect(1)
+	tracker.Disconnect(1)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, domain.PresenceOnline, tracker.Presence([]int32{1})[0].Status)
+}
+
+func Test_Reported_Status_Is_Published_Once(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+	tracker := newTracker(db, publisher, time.Minute)
+
+	expectContacts(mock)
+	expectContacts(mock)
+
+	tracker.Connect(1)
+	tracker.SetStatus(1, domain.PresenceAway)
+	tracker.SetStatus(1, domain.PresenceAway)
+	tracker.SetStatus(2, domain.PresenceAway)
+
+	assert.Equal(t, domain.PresenceAway, tracker.Presence([]int32{1})[0].Status)
+	assert.Equal(t, []domain.PresenceStatus{domain.PresenceOnline, domain.PresenceAway}, publisher.statuses())
+	assert.Equal(t, domain.PresenceOffline, tracker.Presence([]int32{2})[0].Status)
+	assert.Nil(t, tracker.Presence([]int32{2})[0].LastSeen)
+}
+
+func Test_Last_Seen_Is_Flushed_Once_Per_Change(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	tracker := newTracker(db, &recordingPublisher{}, time.Millisecond)
+
+	expectContacts(mock)
+	expectContacts(mock)
+	mock.ExpectExec("UPDATE app_user SET last_seen").WithArgs(int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tracker.Connect(1)
+	tracker.Touch(1)
+	tracker.Touch(1)
+	tracker.Disconnect(1)
+	waitFor(t, func() bool { return tracker.Presence([]int32{1})[0].Status == domain.PresenceOffline })
+
+	assert.Nil(t, tracker.Flush(context.Background()))
+	assert.Nil(t, tracker.Flush(context.Background()))
+	assert.Nil(t, tracker.Presence([]int32{1})[0].LastSeen)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Failed_Flush_Is_Retried(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	tracker := newTracker(db, &recordingPublisher{}, time.Minute)
+
+	expectContacts(mock)
+	mock.ExpectExec("UPDATE app_user SET last_seen").WillReturnError(sql.ErrConnDone)
+	mock.ExpectExec("UPDATE app_user SET last_seen").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tracker.Connect(1)
+
+	assert.NotNil(t, tracker.Flush(context.Background()))
+	assert.Nil(t, tracker.Flush(context.Background()))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type AuditLogRepository struct {
+	Db *sql.DB
+}
+
+func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		Db: db,
+	}
+}
+
+func (auditRepo *AuditLogRepository) Save(ctx context.Context, log *domain.AuditLog) (int32, error) {
+	lastInsertId := 0
+	err := executorFrom(ctx, auditRepo.Db).QueryRowContext(ctx, "INSERT INTO audit_log (action, user_id, ip, detail, created) VALUES ($1,$2,$3,$4,$5) RETURNING id",
+		log.Action, log.UserID, log.IP, log.Detail, log.Created).Scan(&lastInsertId)
+	if err != nil {
+		return IdError, err
+	}
+
+	return int32(lastInsertId), nil
+}
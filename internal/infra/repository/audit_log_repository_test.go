@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Audit_Log_Is_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	userID := int32(1)
+	log := domain.NewAuditLog(domain.AuditLoginLocked, &userID, "127.0.0.1", "account locked for 1m0s")
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(4)
+	mock.ExpectQuery("INSERT INTO audit_log").WithArgs(log.Action, userID, log.IP, log.Detail, AnyTime{}).WillReturnRows(rows)
+
+	createdId, err := NewAuditLogRepository(db).Save(context.Background(), log)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), createdId)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Audit_Log_Is_Saved_Without_User(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	log := domain.NewAuditLog(domain.AuditLoginLocked, nil, "127.0.0.1", "ip locked for 1m0s")
+
+	mock.ExpectQuery("INSERT INTO audit_log").WithArgs(log.Action, nil, log.IP, log.Detail, AnyTime{}).WillReturnError(errors.New("an internal error"))
+
+	createdId, err := NewAuditLogRepository(db).Save(context.Background(), log)
+	assert.NotNil(t, err)
+	assert.Equal(t, IdError, createdId)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
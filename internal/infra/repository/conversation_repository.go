@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ConversationRepository struct {
+	Db *sql.DB
+}
+
+func NewConversationRepository(db *sql.DB) *ConversationRepository {
+	return &ConversationRepository{
+		Db: db,
+	}
+}
+
+// ListConversations returns every room the user is an active member of, most
+// recently active first. Activity is the newest message, or the room creation
+// while it has none. Unread counts come in the same round trip, each one an
+// index range scan past the user read receipt.
+func (conversationRepo *ConversationRepository) ListConversations(ctx context.Context, userID int32) ([]*domain.Conversation, error) {
+	rows, err := executorFrom(ctx, conversationRepo.Db).QueryContext(ctx, "SELECT r.id, r.name, r.topic, r.visibility, r.kind, r.owner_id, r.archived_at, r.created, "+
+		"COALESCE(lm.created, r.created) AS last_activity_at, peer.id, peer.username, peer.displayname, "+
+		"COALESCE(rr.message_id, 0), unread.total "+
+		"FROM room_member me "+
+		"JOIN room r ON r.id = me.room_id "+
+		"LEFT JOIN LATERAL (SELECT created FROM message WHERE room_id = r.id ORDER BY id DESC LIMIT 1) lm ON true "+
+		"LEFT JOIN read_receipt rr ON rr.room_id = r.id AND rr.user_id = me.user_id "+
+		"LEFT JOIN LATERAL (SELECT COUNT(*) AS total FROM message WHERE room_id = r.id AND id > COALESCE(rr.message_id, 0) "+
+		"AND sender_id <> me.user_id AND deleted_at IS NULL) unread ON true "+
+		"LEFT JOIN room_member pm ON r.kind = 'direct' AND pm.room_id = r.id AND pm.user_id <> me.user_id "+
+		"LEFT JOIN app_user peer ON peer.id = pm.user_id "+
+		"WHERE me.user_id = $1 AND me.status = 'active' "+
+		"ORDER BY last_activity_at DESC, r.id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := make([]*domain.Conversation, 0)
+	for rows.Next() {
+		conversation, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, conversation)
+	}
+
+	return conversations, rows.Err()
+}
+
+func (conversationRepo *ConversationRepository) ListContactIDs(ctx context.Context, userID int32) ([]int32, error) {
+	rows, err := executorFrom(ctx, conversationRepo.Db).QueryContext(ctx, "SELECT DISTINCT other.user_id FROM room_member me "+
+		"JOIN room_member other ON other.room_id = me.room_id AND other.user_id <> me.user_id AND other.status = 'active' "+
+		"WHERE me.user_id = $1 AND me.status = 'active'", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := make([]int32, 0)
+	for rows.Next() {
+		var contactID int32
+		if err := rows.Scan(&contactID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, contactID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+func scanConversation(row rowScanner) (*domain.Conversation, error) {
+	room := domain.Room{}
+	conversation := domain.Conversation{Room: &room}
+	var archivedAt sql.NullTime
+	var peerID sql.NullInt32
+	var peerUserName, peerDisplayName sql.NullString
+
+	err := row.Scan(&room.ID, &room.Name, &room.Topic, &room.Visibility, &room.Kind, &room.OwnerID, &archivedAt, &room.Created,
+		&conversation.LastActivityAt, &peerID, &peerUserName, &peerDisplayName, &conversation.LastReadMessageID, &conversation.UnreadCount)
+	if err != nil {
+		return nil, err
+	}
+
+	room.ArchivedAt = nullTimeToPointer(archivedAt)
+	if peerID.Valid {
+		conversation.Peer = &domain.User{ID: peerID.Int32, UserName: peerUserName.String, DisplayName: peerDisplayName.String}
+	}
+	return &conversation, nil
+}
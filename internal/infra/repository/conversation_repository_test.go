@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var conversationTableColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created", "last_activity_at", "peer_id", "peer_username", "peer_displayname", "last_read_message_id", "unread_count"}
+
+func Test_If_The_Conversations_Are_Listed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	lastActivity := time.Now()
+	rows := sqlmock.NewRows(conversationTableColumns).
+		AddRow(7, "dm:1:3", "", "private", "direct", 1, nil, time.Now(), lastActivity, 3, "joaquim2019", nil, 40, 2).
+		AddRow(5, "general", "", "public", "channel", 2, nil, time.Now(), time.Now(), nil, nil, nil, 0, 0)
+	mock.ExpectQuery("SELECT (.+) COALESCE\\(rr.message_id, 0\\), unread.total FROM room_member me (.+) ORDER BY last_activity_at DESC").WithArgs(int32(1)).WillReturnRows(rows)
+
+	conversations, err := NewConversationRepository(db).ListConversations(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Len(t, conversations, 2)
+
+	assert.Equal(t, domain.RoomKindDirect, conversations[0].Room.Kind)
+	assert.Equal(t, int32(3), conversations[0].Peer.ID)
+	assert.Equal(t, "joaquim2019", conversations[0].Peer.UserName)
+	assert.Equal(t, lastActivity.Unix(), conversations[0].LastActivityAt.Unix())
+	assert.Equal(t, int64(40), conversations[0].LastReadMessageID)
+	assert.Equal(t, 2, conversations[0].UnreadCount)
+
+	assert.Equal(t, "general", conversations[1].Room.Name)
+	assert.Nil(t, conversations[1].Peer)
+}
+
+func Test_If_The_Contacts_Are_Listed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT DISTINCT other.user_id FROM room_member me (.+) WHERE me.user_id = \\$1").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
+
+	contactIDs, err := NewConversationRepository(db).ListContactIDs(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{2, 3}, contactIDs)
+}
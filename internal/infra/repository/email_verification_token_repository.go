@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+const emailVerificationTokenColumns = "id, user_id, token_hash, expires_at, used_at, created"
+
+type EmailVerificationTokenRepository struct {
+	Db *sql.DB
+}
+
+func NewEmailVerificationTokenRepository(db *sql.DB) *EmailVerificationTokenRepository {
+	return &EmailVerificationTokenRepository{
+		Db: db,
+	}
+}
+
+func (tokenRepo *EmailVerificationTokenRepository) Save(ctx context.Context, token *domain.EmailVerificationToken) (int32, error) {
+	lastInsertId := 0
+	err := executorFrom(ctx, tokenRepo.Db).QueryRowContext(ctx, "INSERT INTO email_verification_token (user_id, token_hash, expires_at, created) VALUES ($1,$2,$3,$4) RETURNING id",
+		token.UserID, token.TokenHash, token.ExpiresAt, token.Created).Scan(&lastInsertId)
+	if err != nil {
+		return IdError, err
+	}
+
+	return int32(lastInsertId), nil
+}
+
+func (tokenRepo *EmailVerificationTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	return scanEmailVerificationToken(executorFrom(ctx, tokenRepo.Db).QueryRowContext(ctx, "SELECT "+emailVerificationTokenColumns+" FROM email_verification_token WHERE token_hash = $1", tokenHash))
+}
+
+// GetLatestByUser returns the token mailed last to the user, used or not.
+func (tokenRepo *EmailVerificationTokenRepository) GetLatestByUser(ctx context.Context, userID int32) (*domain.EmailVerificationToken, error) {
+	return scanEmailVerificationToken(executorFrom(ctx, tokenRepo.Db).QueryRowContext(ctx, "SELECT "+emailVerificationTokenColumns+" FROM email_verification_token WHERE user_id = $1 ORDER BY created DESC LIMIT 1", userID))
+}
+
+// MarkAsUsed consumes the verification link; a second click on the same
+// link, or a resend racing with it, gets false back.
+func (tokenRepo *EmailVerificationTokenRepository) MarkAsUsed(ctx context.Context, id int32) (bool, error) {
+	return markTokenAsUsed(ctx, tokenRepo.Db, "email_verification_token", id)
+}
+
+func (tokenRepo *EmailVerificationTokenRepository) InvalidateForUser(ctx context.Context, userID int32) error {
+	_, err := executorFrom(ctx, tokenRepo.Db).ExecContext(ctx, "UPDATE email_verification_token SET used_at = $2 WHERE user_id = $1 AND used_at IS NULL", userID, time.Now())
+	return err
+}
+
+func scanEmailVerificationToken(row rowScanner) (*domain.EmailVerificationToken, error) {
+	token := domain.EmailVerificationToken{}
+	var usedAt sql.NullTime
+	err := row.Scan(&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &usedAt, &token.Created)
+	if err != nil {
+		return nil, err
+	}
+	token.UsedAt = nullTimeToPointer(usedAt)
+	return &token, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/lib/pq"
+)
+
+type EventLogRepository struct {
+	Db *sql.DB
+}
+
+func NewEventLogRepository(db *sql.DB) *EventLogRepository {
+	return &EventLogRepository{
+		Db: db,
+	}
+}
+
+// Append takes the next id under a lock on the table, held until commit, so
+// an event only becomes visible once every event with a lower id is.
+// Delivering before the commit keeps the next append, and its delivery,
+// waiting until then.
+func (eventLogRepo *EventLogRepository) Append(ctx context.Context, userIDs []int32, event domain.Event, deliver func(domain.Event)) error {
+	return inTransaction(ctx, eventLogRepo.Db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "LOCK TABLE event_log IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+			return err
+		}
+
+		err := tx.QueryRowContext(ctx, "INSERT INTO event_log (user_ids, type, payload, created) VALUES ($1,$2,$3,$4) RETURNING id",
+			pq.Array(userIDs), event.Type, string(event.Payload), time.Now()).Scan(&event.ID)
+		if err != nil {
+			return err
+		}
+
+		deliver(event)
+		return nil
+	})
+}
+
+// ListAfter locks the table in a mode appends conflict with, so an event
+// already delivered but not yet committed is waited for rather than missed.
+func (eventLogRepo *EventLogRepository) ListAfter(ctx context.Context, userID int32, afterID int64, limit int) ([]domain.Event, error) {
+	events := make([]domain.Event, 0)
+
+	err := inTransaction(ctx, eventLogRepo.Db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "LOCK TABLE event_log IN SHARE MODE"); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, "SELECT id, type, payload FROM event_log WHERE user_ids @> ARRAY[$1]::integer[] AND id > $2 ORDER BY id ASC LIMIT $3",
+			userID, afterID, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var event domain.Event
+			var payload string
+			if err := rows.Scan(&event.ID, &event.Type, &payload); err != nil {
+				return err
+			}
+			event.Payload = []byte(payload)
+			events = append(events, event)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (eventLogRepo *EventLogRepository) OldestID(ctx context.Context) (int64, error) {
+	var id int64
+	err := executorFrom(ctx, eventLogRepo.Db).QueryRowContext(ctx, "SELECT COALESCE(MIN(id), 0) FROM event_log").Scan(&id)
+	return id, err
+}
+
+func (eventLogRepo *EventLogRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	_, err := executorFrom(ctx, eventLogRepo.Db).ExecContext(ctx, "DELETE FROM event_log WHERE created < $1 AND id < (SELECT MAX(id) FROM event_log)", before)
+	return err
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Event_Is_Appended_And_Delivered_Before_Commit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	event := domain.Event{Type: "message.new", Payload: []byte(`{"id":42}`)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE event_log IN SHARE ROW EXCLUSIVE MODE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO event_log").WithArgs("{1,2}", "message.new", `{"id":42}`, AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	delivered := make([]domain.Event, 0)
+	err = NewEventLogRepository(db).Append(context.Background(), []int32{1, 2}, event, func(event domain.Event) {
+		assert.Nil(t, mock.ExpectationsWereMet(), "the event must be delivered before the commit")
+		delivered = append(delivered, event)
+		mock.ExpectCommit()
+	})
+	assert.Nil(t, err)
+	assert.Len(t, delivered, 1)
+	assert.Equal(t, int64(7), delivered[0].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Event_Is_Not_Delivered_When_Append_Fails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE event_log").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO event_log").WillReturnError(errors.New("error to append event"))
+	mock.ExpectRollback()
+
+	delivered := false
+	err = NewEventLogRepository(db).Append(context.Background(), []int32{1}, domain.Event{Type: "message.new"}, func(domain.Event) {
+		delivered = true
+	})
+	assert.EqualError(t, err, "error to append event")
+	assert.False(t, delivered)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Events_After_An_ID_Are_Listed_Waiting_For_Appends(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "type", "payload"}).
+		AddRow(11, "message.new", `{"id":42}`).
+		AddRow(12, "receipt.updated", `{"conversationId":5}`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE event_log IN SHARE MODE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, type, payload FROM event_log WHERE user_ids @> ARRAY\\[\\$1\\]::integer\\[\\] AND id > \\$2 ORDER BY id ASC LIMIT \\$3").
+		WithArgs(int32(1), int64(10), 501).WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	events, err := NewEventLogRepository(db).ListAfter(context.Background(), 1, 10, 501)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.Event{
+		{ID: 11, Type: "message.new", Payload: []byte(`{"id":42}`)},
+		{ID: 12, Type: "receipt.updated", Payload: []byte(`{"conversationId":5}`)},
+	}, events)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Oldest_Event_ID_Is_Returned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COALESCE\\(MIN\\(id\\), 0\\) FROM event_log").WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(3))
+
+	id, err := NewEventLogRepository(db).OldestID(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), id)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_Old_Events_Are_Deleted_Keeping_The_Newest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	before := time.Now().Add(-24 * time.Hour)
+	mock.ExpectExec("DELETE FROM event_log WHERE created < \\$1 AND id < \\(SELECT MAX\\(id\\) FROM event_log\\)").
+		WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 4))
+
+	assert.Nil(t, NewEventLogRepository(db).DeleteBefore(context.Background(), before))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+const messageColumns = "m.id, m.room_id, m.sender_id, COALESCE(NULLIF(u.displayname, ''), u.username), m.body, m.edited_at, m.deleted_at, m.created"
+
+type MessageRepository struct {
+	Db *sql.DB
+}
+
+func NewMessageRepository(db *sql.DB) *MessageRepository {
+	return &MessageRepository{
+		Db: db,
+	}
+}
+
+func (messageRepo *MessageRepository) Save(ctx context.Context, message *domain.Message) (int64, error) {
+	var lastInsertId int64
+	err := executorFrom(ctx, messageRepo.Db).QueryRowContext(ctx, "INSERT INTO message (room_id, sender_id, body, created) VALUES ($1,$2,$3,$4) RETURNING id",
+		message.RoomID, message.SenderID, message.Body, message.Created).Scan(&lastInsertId)
+	if err != nil {
+		return int64(IdError), err
+	}
+
+	return lastInsertId, nil
+}
+
+func (messageRepo *MessageRepository) GetMessageById(ctx context.Context, id int64) (*domain.Message, error) {
+	return scanMessage(executorFrom(ctx, messageRepo.Db).QueryRowContext(ctx, "SELECT "+messageColumns+" FROM message m JOIN app_user u ON u.id = m.sender_id WHERE m.id = $1", id))
+}
+
+// ListMessages walks the (room_id, id) index from the page bound, so deep
+// history costs the same as the latest page. Results are always returned
+// oldest first.
+func (messageRepo *MessageRepository) ListMessages(ctx context.Context, page domain.MessagePage) ([]*domain.Message, error) {
+	var rows *sql.Rows
+	var err error
+
+	ascending := page.AfterID > 0
+	if ascending {
+		rows, err = executorFrom(ctx, messageRepo.Db).QueryContext(ctx, "SELECT "+messageColumns+" FROM message m JOIN app_user u ON u.id = m.sender_id "+
+			"WHERE m.room_id = $1 AND m.id > $2 ORDER BY m.id ASC LIMIT $3", page.RoomID, page.AfterID, page.Limit)
+	} else if page.BeforeID > 0 {
+		rows, err = executorFrom(ctx, messageRepo.Db).QueryContext(ctx, "SELECT "+messageColumns+" FROM message m JOIN app_user u ON u.id = m.sender_id "+
+			"WHERE m.room_id = $1 AND m.id < $2 ORDER BY m.id DESC LIMIT $3", page.RoomID, page.BeforeID, page.Limit)
+	} else {
+		rows, err = executorFrom(ctx, messageRepo.Db).QueryContext(ctx, "SELECT "+messageColumns+" FROM message m JOIN app_user u ON u.id = m.sender_id "+
+			"WHERE m.room_id = $1 ORDER BY m.id DESC LIMIT $2", page.RoomID, page.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]*domain.Message, 0)
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !ascending {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	return messages, nil
+}
+
+// Edit stores the new body and the previous version together, so the history
+// never misses a step.
+func (messageRepo *MessageRepository) Edit(ctx context.Context, message *domain.Message, previous *domain.MessageEdit) error {
+	return inTransaction(ctx, messageRepo.Db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO message_edit (message_id, body, edited_by, created) VALUES ($1,$2,$3,$4)",
+			previous.MessageID, previous.Body, previous.EditedBy, previous.Created)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE message SET body = $2, edited_at = $3 WHERE id = $1", message.ID, message.Body, message.EditedAt)
+		return err
+	})
+}
+
+func (messageRepo *MessageRepository) SoftDelete(ctx context.Context, id int64) error {
+	_, err := executorFrom(ctx, messageRepo.Db).ExecContext(ctx, "UPDATE message SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL", id, time.Now())
+	return err
+}
+
+func (messageRepo *MessageRepository) ListEdits(ctx context.Context, messageID int64) ([]*domain.MessageEdit, error) {
+	rows, err := executorFrom(ctx, messageRepo.Db).QueryContext(ctx, "SELECT id, message_id, body, edited_by, created FROM message_edit WHERE message_id = $1 ORDER BY id", messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := make([]*domain.MessageEdit, 0)
+	for rows.Next() {
+		edit := domain.MessageEdit{}
+		if err := rows.Scan(&edit.ID, &edit.MessageID, &edit.Body, &edit.EditedBy, &edit.Created); err != nil {
+			return nil, err
+		}
+		edits = append(edits, &edit)
+	}
+
+	return edits, rows.Err()
+}
+
+func scanMessage(row rowScanner) (*domain.Message, error) {
+	message := domain.Message{}
+	var editedAt, deletedAt sql.NullTime
+	err := row.Scan(&message.ID, &message.RoomID, &message.SenderID, &message.SenderName, &message.Body, &editedAt, &deletedAt, &message.Created)
+	if err != nil {
+		return nil, err
+	}
+	message.EditedAt = nullTimeToPointer(editedAt)
+	message.DeletedAt = nullTimeToPointer(deletedAt)
+	return &message, nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var messageTableColumns = []string{"id", "room_id", "sender_id", "sender_name", "body", "edited_at", "deleted_at", "created"}
+
+func Test_If_The_Message_Is_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	message, _ := domain.NewMessage(5, 1, "hello")
+	mock.ExpectQuery("INSERT INTO message").WithArgs(int32(5), int32(1), "hello", AnyTime{}).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+
+	createdId, err := NewMessageRepository(db).Save(context.Background(), message)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), createdId)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Latest_Messages_Are_Listed_Oldest_First(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(messageTableColumns).
+		AddRow(12, 5, 1, "Eduardo Lima", "second", nil, nil, time.Now()).
+		AddRow(11, 5, 2, "joaquim2019", "first", nil, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM message m (.+) WHERE m.room_id = \\$1 ORDER BY m.id DESC LIMIT \\$2").WithArgs(int32(5), 2).WillReturnRows(rows)
+
+	messages, err := NewMessageRepository(db).ListMessages(context.Background(), domain.MessagePage{RoomID: 5, Limit: 2})
+	assert.Nil(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, int64(11), messages[0].ID)
+	assert.Equal(t, int64(12), messages[1].ID)
+}
+
+func Test_If_The_Messages_Are_Paged_By_Cursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM message m (.+) m.id < \\$2 ORDER BY m.id DESC").WithArgs(int32(5), int64(11), 10).
+		WillReturnRows(sqlmock.NewRows(messageTableColumns).AddRow(10, 5, 1, "Eduardo Lima", "older", nil, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM message m (.+) m.id > \\$2 ORDER BY m.id ASC").WithArgs(int32(5), int64(12), 10).
+		WillReturnRows(sqlmock.NewRows(messageTableColumns).AddRow(13, 5, 1, "Eduardo Lima", "newer", nil, nil, time.Now()))
+
+	messageRepo := NewMessageRepository(db)
+
+	older, err := messageRepo.ListMessages(context.Background(), domain.MessagePage{RoomID: 5, BeforeID: 11, Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, "older", older[0].Body)
+
+	newer, err := messageRepo.ListMessages(context.Background(), domain.MessagePage{RoomID: 5, AfterID: 12, Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, "newer", newer[0].Body)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Message_Edit_Is_Saved_In_A_Transaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	message, _ := domain.NewMessage(5, 1, "hello")
+	message.ID = 42
+	previous, _ := message.Edit("hello, world", 1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO message_edit").WithArgs(int64(42), "hello", int32(1), AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE message SET body").WithArgs(int64(42), "hello, world", AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.Nil(t, NewMessageRepository(db).Edit(context.Background(), message, previous))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Message_Edit_Is_Rolled_Back_On_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	message, _ := domain.NewMessage(5, 1, "hello")
+	previous, _ := message.Edit("hello, world", 1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO message_edit").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE message SET body").WillReturnError(errors.New("error to update message"))
+	mock.ExpectRollback()
+
+	assert.EqualError(t, NewMessageRepository(db).Edit(context.Background(), message, previous), "error to update message")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Message_Is_Soft_Deleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE message SET deleted_at = \\$2 WHERE id = \\$1 AND deleted_at IS NULL").WithArgs(int64(42), AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, NewMessageRepository(db).SoftDelete(context.Background(), 42))
+}
+
+func Test_If_The_Message_And_Its_Edits_Are_Fetched(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM message m (.+) WHERE m.id = \\$1").WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows(messageTableColumns).AddRow(42, 5, 1, "Eduardo Lima", "hello, world", time.Now(), nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM message_edit WHERE message_id").WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "message_id", "body", "edited_by", "created"}).AddRow(1, 42, "hello", 1, time.Now()))
+
+	messageRepo := NewMessageRepository(db)
+
+	message, err := messageRepo.GetMessageById(context.Background(), 42)
+	assert.Nil(t, err)
+	assert.NotNil(t, message.EditedAt)
+	assert.Nil(t, message.DeletedAt)
+
+	edits, err := messageRepo.ListEdits(context.Background(), 42)
+	assert.Nil(t, err)
+	assert.Len(t, edits, 1)
+	assert.Equal(t, "hello", edits[0].Body)
+}
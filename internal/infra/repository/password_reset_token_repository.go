@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type PasswordResetTokenRepository struct {
+	Db *sql.DB
+}
+
+func NewPasswordResetTokenRepository(db *sql.DB) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{
+		Db: db,
+	}
+}
+
+func (tokenRepo *PasswordResetTokenRepository) Save(ctx context.Context, token *domain.PasswordResetToken) (int32, error) {
+	lastInsertId := 0
+	err := executorFrom(ctx, tokenRepo.Db).QueryRowContext(ctx, "INSERT INTO password_reset_token (user_id, token_hash, expires_at, created) VALUES ($1,$2,$3,$4) RETURNING id",
+		token.UserID, token.TokenHash, token.ExpiresAt, token.Created).Scan(&lastInsertId)
+	if err != nil {
+		return IdError, err
+	}
+
+	return int32(lastInsertId), nil
+}
+
+func (tokenRepo *PasswordResetTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	token := domain.PasswordResetToken{}
+	var usedAt sql.NullTime
+	err := executorFrom(ctx, tokenRepo.Db).QueryRowContext(ctx, "SELECT id, user_id, token_hash, expires_at, used_at, created FROM password_reset_token WHERE token_hash = $1", tokenHash).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &usedAt, &token.Created)
+	if err != nil {
+		return nil, err
+	}
+	token.UsedAt = nullTimeToPointer(usedAt)
+	return &token, nil
+}
+
+// MarkAsUsed reports false when the token was already used, so two requests
+// racing with the same token cannot both reset the password.
+func (tokenRepo *PasswordResetTokenRepository) MarkAsUsed(ctx context.Context, id int32) (bool, error) {
+	return markTokenAsUsed(ctx, tokenRepo.Db, "password_reset_token", id)
+}
+
+func (tokenRepo *PasswordResetTokenRepository) InvalidateForUser(ctx context.Context, userID int32) error {
+	_, err := executorFrom(ctx, tokenRepo.Db).ExecContext(ctx, "UPDATE password_reset_token SET used_at = $2 WHERE user_id = $1 AND used_at IS NULL", userID, time.Now())
+	return err
+}
+
+// markTokenAsUsed stamps used_at on a single-use token row only while it is
+// still unset, and reports whether this call was the one that stamped it.
+func markTokenAsUsed(ctx context.Context, db *sql.DB, table string, id int32) (bool, error) {
+	result, err := executorFrom(ctx, db).ExecContext(ctx, "UPDATE "+table+" SET used_at = $2 WHERE id = $1 AND used_at IS NULL", id, time.Now())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Password_Reset_Token_Is_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	token := domain.NewPasswordResetToken(1, "hash", time.Hour)
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(3)
+	mock.ExpectQuery("INSERT INTO password_reset_token").WithArgs(token.UserID, token.TokenHash, AnyTime{}, AnyTime{}).WillReturnRows(rows)
+
+	createdId, err := NewPasswordResetTokenRepository(db).Save(context.Background(), token)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), createdId)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Password_Reset_Token_Is_Fetched_By_Hash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	timestamp := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "used_at", "created"}).
+		AddRow(3, 1, "hash", timestamp, timestamp, timestamp)
+	mock.ExpectQuery("SELECT id, user_id, token_hash, expires_at, used_at, created FROM password_reset_token").WithArgs("hash").WillReturnRows(rows)
+
+	token, err := NewPasswordResetTokenRepository(db).GetByTokenHash(context.Background(), "hash")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), token.UserID)
+	assert.True(t, token.IsUsed())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Password_Reset_Token_Is_Used_Only_Once(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tokenRepo := NewPasswordResetTokenRepository(db)
+
+	mock.ExpectExec("UPDATE password_reset_token SET used_at").WithArgs(int32(3), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE password_reset_token SET used_at").WithArgs(int32(3), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	used, err := tokenRepo.MarkAsUsed(context.Background(), 3)
+	assert.Nil(t, err)
+	assert.True(t, used)
+
+	used, err = tokenRepo.MarkAsUsed(context.Background(), 3)
+	assert.Nil(t, err)
+	assert.False(t, used)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Pending_Password_Reset_Tokens_Of_A_User_Are_Invalidated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE password_reset_token SET used_at (.+) WHERE user_id").WithArgs(int32(1), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = NewPasswordResetTokenRepository(db).InvalidateForUser(context.Background(), 1)
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PresenceRepository struct {
+	Db *sql.DB
+}
+
+func NewPresenceRepository(db *sql.DB) *PresenceRepository {
+	return &PresenceRepository{
+		Db: db,
+	}
+}
+
+// SaveLastSeen ignores timestamps older than the stored one, so a slow flush
+// cannot move last seen backwards.
+func (presenceRepo *PresenceRepository) SaveLastSeen(ctx context.Context, userID int32, lastSeen time.Time) error {
+	_, err := executorFrom(ctx, presenceRepo.Db).ExecContext(ctx, "UPDATE app_user SET last_seen = $2 WHERE id = $1 AND (last_seen IS NULL OR last_seen < $2)", userID, lastSeen)
+	return err
+}
+
+func (presenceRepo *PresenceRepository) ListLastSeen(ctx context.Context, userIDs []int32) (map[int32]time.Time, error) {
+	rows, err := executorFrom(ctx, presenceRepo.Db).QueryContext(ctx, "SELECT id, last_seen FROM app_user WHERE id = ANY($1) AND last_seen IS NOT NULL", pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastSeen := make(map[int32]time.Time)
+	for rows.Next() {
+		var userID int32
+		var seen time.Time
+		if err := rows.Scan(&userID, &seen); err != nil {
+			return nil, err
+		}
+		lastSeen[userID] = seen
+	}
+
+	return lastSeen, rows.Err()
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Last_Seen_Is_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE app_user SET last_seen = \\$2 WHERE id = \\$1 AND \\(last_seen IS NULL OR last_seen < \\$2\\)").
+		WithArgs(int32(1), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewPresenceRepository(db).SaveLastSeen(context.Background(), 1, time.Now())
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Last_Seen_Is_Listed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	lastSeen := time.Now().Add(-time.Hour)
+	mock.ExpectQuery("SELECT id, last_seen FROM app_user WHERE id = ANY\\(\\$1\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "last_seen"}).AddRow(2, lastSeen))
+
+	seen, err := NewPresenceRepository(db).ListLastSeen(context.Background(), []int32{2, 3})
+	assert.Nil(t, err)
+	assert.Len(t, seen, 1)
+	assert.Equal(t, lastSeen.Unix(), seen[2].Unix())
+}
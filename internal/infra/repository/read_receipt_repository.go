@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ReadReceiptRepository struct {
+	Db *sql.DB
+}
+
+func NewReadReceiptRepository(db *sql.DB) *ReadReceiptRepository {
+	return &ReadReceiptRepository{
+		Db: db,
+	}
+}
+
+// Advance never moves a receipt backwards, so receipts sent out of order by
+// several devices of the same user settle on the newest one.
+func (receiptRepo *ReadReceiptRepository) Advance(ctx context.Context, receipt *domain.ReadReceipt) (bool, error) {
+	result, err := executorFrom(ctx, receiptRepo.Db).ExecContext(ctx, "INSERT INTO read_receipt (room_id, user_id, message_id, updated) VALUES ($1,$2,$3,$4) "+
+		"ON CONFLICT (room_id, user_id) DO UPDATE SET message_id = EXCLUDED.message_id, updated = EXCLUDED.updated "+
+		"WHERE read_receipt.message_id < EXCLUDED.message_id",
+		receipt.RoomID, receipt.UserID, receipt.MessageID, receipt.Updated)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (receiptRepo *ReadReceiptRepository) ListReceipts(ctx context.Context, roomID int32) ([]*domain.ReadReceipt, error) {
+	rows, err := executorFrom(ctx, receiptRepo.Db).QueryContext(ctx, "SELECT room_id, user_id, message_id, updated FROM read_receipt WHERE room_id = $1 ORDER BY message_id DESC", roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]*domain.ReadReceipt, 0)
+	for rows.Next() {
+		receipt := domain.ReadReceipt{}
+		if err := rows.Scan(&receipt.RoomID, &receipt.UserID, &receipt.MessageID, &receipt.Updated); err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, &receipt)
+	}
+
+	return receipts, rows.Err()
+}
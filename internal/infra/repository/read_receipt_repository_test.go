@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Read_Receipt_Only_Moves_Forward(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO read_receipt (.+) WHERE read_receipt.message_id < EXCLUDED.message_id").
+		WithArgs(int32(5), int32(1), int64(42), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO read_receipt").
+		WithArgs(int32(5), int32(1), int64(40), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	receiptRepo := NewReadReceiptRepository(db)
+
+	advanced, err := receiptRepo.Advance(context.Background(), domain.NewReadReceipt(5, 1, 42))
+	assert.Nil(t, err)
+	assert.True(t, advanced)
+
+	advanced, err = receiptRepo.Advance(context.Background(), domain.NewReadReceipt(5, 1, 40))
+	assert.Nil(t, err)
+	assert.False(t, advanced)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Read_Receipts_Are_Listed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM read_receipt WHERE room_id").WithArgs(int32(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"room_id", "user_id", "message_id", "updated"}).AddRow(5, 1, 42, time.Now()).AddRow(5, 3, 40, time.Now()))
+
+	receipts, err := NewReadReceiptRepository(db).ListReceipts(context.Background(), 5)
+	assert.Nil(t, err)
+	assert.Len(t, receipts, 2)
+	assert.Equal(t, int64(42), receipts[0].MessageID)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type RefreshTokenRepository struct {
+	Db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		Db: db,
+	}
+}
+
+func (tokenRepo *RefreshTokenRepository) Save(ctx context.Context, token *domain.RefreshToken) (int32, error) {
+	lastInsertId := 0
+	err := executorFrom(ctx, tokenRepo.Db).QueryRowContext(ctx, "INSERT INTO refresh_token (user_id, family_id, token_hash, expires_at, created) VALUES ($1,$2,$3,$4,$5) RETURNING id",
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.Created).Scan(&lastInsertId)
+	if err != nil {
+		return IdError, err
+	}
+
+	return int32(lastInsertId), nil
+}
+
+func (tokenRepo *RefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	token := domain.RefreshToken{}
+	var rotatedAt, revokedAt sql.NullTime
+	err := executorFrom(ctx, tokenRepo.Db).QueryRowContext(ctx, "SELECT id, user_id, family_id, token_hash, expires_at, rotated_at, revoked_at, created FROM refresh_token WHERE token_hash = $1", tokenHash).Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &rotatedAt, &revokedAt, &token.Created)
+	if err != nil {
+		return nil, err
+	}
+	token.RotatedAt = nullTimeToPointer(rotatedAt)
+	token.RevokedAt = nullTimeToPointer(revokedAt)
+	return &token, nil
+}
+
+func (tokenRepo *RefreshTokenRepository) MarkAsRotated(ctx context.Context, id int32) (bool, error) {
+	result, err := executorFrom(ctx, tokenRepo.Db).ExecContext(ctx, "UPDATE refresh_token SET rotated_at = $2 WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL", id, time.Now())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (tokenRepo *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := executorFrom(ctx, tokenRepo.Db).ExecContext(ctx, "UPDATE refresh_token SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL", familyID, time.Now())
+	return err
+}
+
+// RevokeAllForUser ends every session of the user.
+func (tokenRepo *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int32) error {
+	_, err := executorFrom(ctx, tokenRepo.Db).ExecContext(ctx, "UPDATE refresh_token SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL", userID, time.Now())
+	return err
+}
+
+func nullTimeToPointer(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Refresh_Token_Is_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tokenRepo := NewRefreshTokenRepository(db)
+	token := domain.NewRefreshToken(1, "family", "hash", time.Hour)
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(3)
+	mock.ExpectQuery("INSERT INTO refresh_token").WithArgs(token.UserID, token.FamilyID, token.TokenHash, AnyTime{}, AnyTime{}).WillReturnRows(rows)
+
+	createdId, err := tokenRepo.Save(context.Background(), token)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), createdId)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Refresh_Token_Is_Fetched_By_Hash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	timestamp := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "rotated_at", "revoked_at", "created"}).
+		AddRow(3, 1, "family", "hash", timestamp, timestamp, nil, timestamp)
+	mock.ExpectQuery("SELECT id, user_id, family_id, token_hash, expires_at, rotated_at, revoked_at, created FROM refresh_token").WithArgs("hash").WillReturnRows(rows)
+
+	token, err := NewRefreshTokenRepository(db).GetByTokenHash(context.Background(), "hash")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), token.ID)
+	assert.Equal(t, "family", token.FamilyID)
+	assert.True(t, token.IsRotated())
+	assert.False(t, token.IsRevoked())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Refresh_Token_Is_Marked_As_Rotated_Only_Once(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tokenRepo := NewRefreshTokenRepository(db)
+
+	mock.ExpectExec("UPDATE refresh_token SET rotated_at").WithArgs(int32(3), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE refresh_token SET rotated_at").WithArgs(int32(3), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rotated, err := tokenRepo.MarkAsRotated(context.Background(), 3)
+	assert.Nil(t, err)
+	assert.True(t, rotated)
+
+	rotated, err = tokenRepo.MarkAsRotated(context.Background(), 3)
+	assert.Nil(t, err)
+	assert.False(t, rotated)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Refresh_Token_Family_Is_Revoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at").WithArgs("family", AnyTime{}).WillReturnError(errors.New("error to revoke"))
+
+	err = NewRefreshTokenRepository(db).RevokeFamily(context.Background(), "family")
+	assert.EqualError(t, err, "error to revoke")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_Every_Refresh_Token_Of_A_User_Is_Revoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at (.+) WHERE user_id").WithArgs(int32(1), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = NewRefreshTokenRepository(db).RevokeAllForUser(context.Background(), 1)
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
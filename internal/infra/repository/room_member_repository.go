@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+const roomMemberColumns = "m.room_id, m.user_id, u.username, u.displayname, m.role, m.status, m.invited_by, m.created"
+
+type RoomMemberRepository struct {
+	Db *sql.DB
+}
+
+func NewRoomMemberRepository(db *sql.DB) *RoomMemberRepository {
+	return &RoomMemberRepository{
+		Db: db,
+	}
+}
+
+func (memberRepo *RoomMemberRepository) Save(ctx context.Context, member *domain.RoomMember) error {
+	_, err := executorFrom(ctx, memberRepo.Db).ExecContext(ctx, "INSERT INTO room_member (room_id, user_id, role, status, invited_by, created) VALUES ($1,$2,$3,$4,$5,$6) "+
+		"ON CONFLICT (room_id, user_id) DO UPDATE SET role = EXCLUDED.role, status = EXCLUDED.status, invited_by = EXCLUDED.invited_by",
+		member.RoomID, member.UserID, member.Role, member.Status, member.InvitedBy, member.Created)
+	return err
+}
+
+func (memberRepo *RoomMemberRepository) GetMember(ctx context.Context, roomID int32, userID int32) (*domain.RoomMember, error) {
+	return scanRoomMember(executorFrom(ctx, memberRepo.Db).QueryRowContext(ctx, "SELECT "+roomMemberColumns+" FROM room_member m JOIN app_user u ON u.id = m.user_id WHERE m.room_id = $1 AND m.user_id = $2", roomID, userID))
+}
+
+func (memberRepo *RoomMemberRepository) ListMembers(ctx context.Context, roomID int32) ([]*domain.RoomMember, error) {
+	rows, err := executorFrom(ctx, memberRepo.Db).QueryContext(ctx, "SELECT "+roomMemberColumns+" FROM room_member m JOIN app_user u ON u.id = m.user_id WHERE m.room_id = $1 AND m.status <> 'banned' ORDER BY m.created", roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]*domain.RoomMember, 0)
+	for rows.Next() {
+		member, err := scanRoomMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (memberRepo *RoomMemberRepository) Delete(ctx context.Context, roomID int32, userID int32) error {
+	_, err := executorFrom(ctx, memberRepo.Db).ExecContext(ctx, "DELETE FROM room_member WHERE room_id = $1 AND user_id = $2", roomID, userID)
+	return err
+}
+
+func scanRoomMember(row rowScanner) (*domain.RoomMember, error) {
+	member := domain.RoomMember{}
+	var displayName sql.NullString
+	var invitedBy sql.NullInt32
+	err := row.Scan(&member.RoomID, &member.UserID, &member.UserName, &displayName, &member.Role, &member.Status, &invitedBy, &member.Created)
+	if err != nil {
+		return nil, err
+	}
+	member.DisplayName = displayName.String
+	if invitedBy.Valid {
+		member.InvitedBy = &invitedBy.Int32
+	}
+	return &member, nil
+}
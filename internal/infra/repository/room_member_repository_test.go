@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var roomMemberTableColumns = []string{"room_id", "user_id", "username", "displayname", "role", "status", "invited_by", "created"}
+
+func Test_If_The_Room_Member_Is_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	member, _ := domain.NewRoomMember(5, 1, domain.RoomRoleOwner, domain.MembershipActive)
+	mock.ExpectExec("INSERT INTO room_member (.+) ON CONFLICT").WithArgs(int32(5), int32(1), domain.RoomRoleOwner, domain.MembershipActive, nil, AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, NewRoomMemberRepository(db).Save(context.Background(), member))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Room_Member_Is_Fetched(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(roomMemberTableColumns).AddRow(5, 3, "joaquim2019", nil, "member", "invited", 1, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room_member m JOIN app_user u").WithArgs(int32(5), int32(3)).WillReturnRows(rows)
+
+	member, err := NewRoomMemberRepository(db).GetMember(context.Background(), 5, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "joaquim2019", member.UserName)
+	assert.Equal(t, "", member.DisplayName)
+	assert.Equal(t, domain.MembershipInvited, member.Status)
+	assert.Equal(t, int32(1), *member.InvitedBy)
+}
+
+func Test_If_Get_No_Rows_When_User_Is_Not_Member(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+
+	_, err = NewRoomMemberRepository(db).GetMember(context.Background(), 5, 3)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func Test_If_Room_Members_Are_Listed_And_Deleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	memberRepo := NewRoomMemberRepository(db)
+	rows := sqlmock.NewRows(roomMemberTableColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "owner", "active", nil, time.Now()).
+		AddRow(5, 3, "joaquim2019", "Joaquim", "member", "active", nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room_member m JOIN app_user u (.+) m.status <> 'banned'").WithArgs(int32(5)).WillReturnRows(rows)
+	mock.ExpectExec("DELETE FROM room_member").WithArgs(int32(5), int32(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	members, err := memberRepo.ListMembers(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("error was not expected while listing members: %s", err)
+	}
+	assert.Len(t, members, 2)
+	assert.Equal(t, domain.RoomRoleOwner, members[0].Role)
+
+	assert.Nil(t, memberRepo.Delete(context.Background(), 5, 3))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/lib/pq"
+)
+
+const (
+	roomColumns = "id, name, topic, visibility, kind, owner_id, archived_at, created"
+	// roomNameIndex is the unique index on the name of rooms.
+	roomNameIndex = "room_name_key"
+)
+
+type RoomRepository struct {
+	Db *sql.DB
+}
+
+func NewRoomRepository(db *sql.DB) *RoomRepository {
+	return &RoomRepository{
+		Db: db,
+	}
+}
+
+func (roomRepo *RoomRepository) Save(ctx context.Context, room *domain.Room) (int32, error) {
+	lastInsertId := 0
+	err := executorFrom(ctx, roomRepo.Db).QueryRowContext(ctx, "INSERT INTO room (name, topic, visibility, kind, owner_id, created) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id",
+		room.Name, room.Topic, room.Visibility, room.Kind, room.OwnerID, room.Created).Scan(&lastInsertId)
+	if err != nil {
+		return IdError, translateRoomError(err)
+	}
+
+	return int32(lastInsertId), nil
+}
+
+func (roomRepo *RoomRepository) GetRoomById(ctx context.Context, id int32) (*domain.Room, error) {
+	return scanRoom(executorFrom(ctx, roomRepo.Db).QueryRowContext(ctx, "SELECT "+roomColumns+" FROM room WHERE id = $1", id))
+}
+
+func (roomRepo *RoomRepository) GetRoomByName(ctx context.Context, name string) (*domain.Room, error) {
+	return scanRoom(executorFrom(ctx, roomRepo.Db).QueryRowContext(ctx, "SELECT "+roomColumns+" FROM room WHERE name = $1", name))
+}
+
+func (roomRepo *RoomRepository) ListRoomsVisibleTo(ctx context.Context, userID int32) ([]*domain.Room, error) {
+	rows, err := executorFrom(ctx, roomRepo.Db).QueryContext(ctx, "SELECT "+roomColumns+" FROM room WHERE archived_at IS NULL AND kind = 'channel' AND (visibility = 'public' OR id IN "+
+		"(SELECT room_id FROM room_member WHERE user_id = $1 AND status IN ('active', 'invited'))) ORDER BY name", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := make([]*domain.Room, 0)
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+
+	return rooms, rows.Err()
+}
+
+func (roomRepo *RoomRepository) Update(ctx context.Context, room *domain.Room) error {
+	_, err := executorFrom(ctx, roomRepo.Db).ExecContext(ctx, "UPDATE room SET name = $2, topic = $3, visibility = $4 WHERE id = $1",
+		room.ID, room.Name, room.Topic, room.Visibility)
+	return translateRoomError(err)
+}
+
+func (roomRepo *RoomRepository) Archive(ctx context.Context, id int32) error {
+	_, err := executorFrom(ctx, roomRepo.Db).ExecContext(ctx, "UPDATE room SET archived_at = $2 WHERE id = $1 AND archived_at IS NULL", id, time.Now())
+	return err
+}
+
+// translateRoomError turns a unique violation of the room name into a
+// conflict, so two rooms created or renamed at once cannot both take a name.
+func translateRoomError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation || pqErr.Constraint != roomNameIndex {
+		return err
+	}
+
+	return domain.NewConflictError("name", "room name already exists")
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRoom(row rowScanner) (*domain.Room, error) {
+	room := domain.Room{}
+	var archivedAt sql.NullTime
+	err := row.Scan(&room.ID, &room.Name, &room.Topic, &room.Visibility, &room.Kind, &room.OwnerID, &archivedAt, &room.Created)
+	if err != nil {
+		return nil, err
+	}
+	room.ArchivedAt = nullTimeToPointer(archivedAt)
+	return &room, nil
+}
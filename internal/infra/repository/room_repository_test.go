@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var roomTableColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created"}
+
+func Test_If_The_Room_Is_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	room, _ := domain.NewRoom(0, "general", "Anything goes", domain.RoomPublic, 1)
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(5)
+	mock.ExpectQuery("INSERT INTO room").WithArgs(room.Name, room.Topic, room.Visibility, domain.RoomKindChannel, room.OwnerID, AnyTime{}).WillReturnRows(rows)
+
+	createdId, err := NewRoomRepository(db).Save(context.Background(), room)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(5), createdId)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_Get_Error_The_Room_Is_Not_Saved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	room, _ := domain.NewRoom(0, "general", "", domain.RoomPublic, 1)
+	mock.ExpectQuery("INSERT INTO room").WillReturnError(errors.New("error to insert room"))
+
+	createdId, err := NewRoomRepository(db).Save(context.Background(), room)
+	assert.EqualError(t, err, "error to insert room")
+	assert.Equal(t, IdError, createdId)
+}
+
+func Test_If_A_Taken_Room_Name_Is_A_Conflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	room, _ := domain.NewRoom(5, "general", "", domain.RoomPublic, 1)
+	mock.ExpectQuery("INSERT INTO room").WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "room_name_key"})
+	mock.ExpectExec("UPDATE room SET name").WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "room_name_key"})
+
+	_, err = NewRoomRepository(db).Save(context.Background(), room)
+	var conflictErr *domain.Error
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "name", conflictErr.Fields[0].Field)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	err = NewRoomRepository(db).Update(context.Background(), room)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_Other_Unique_Violations_Are_Not_A_Room_Conflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	room, _ := domain.NewRoom(0, "general", "", domain.RoomPublic, 1)
+	mock.ExpectQuery("INSERT INTO room").WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "room_pkey"})
+
+	_, err = NewRoomRepository(db).Save(context.Background(), room)
+	assert.NotErrorIs(t, err, domain.ErrConflict)
+}
+
+func Test_If_The_Room_Is_Fetched_By_Id(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	timestamp := time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC)
+	rows := sqlmock.NewRows(roomTableColumns).AddRow(5, "general", "Anything goes", "public", "channel", 1, timestamp, timestamp)
+	mock.ExpectQuery("SELECT id, name, topic, visibility, kind, owner_id, archived_at, created FROM room WHERE id").WithArgs(int32(5)).WillReturnRows(rows)
+
+	room, err := NewRoomRepository(db).GetRoomById(context.Background(), 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "general", room.Name)
+	assert.Equal(t, domain.RoomPublic, room.Visibility)
+	assert.True(t, room.IsArchived())
+}
+
+func Test_If_Get_No_Rows_When_Room_Name_Does_Not_Exists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WithArgs("general").WillReturnError(sql.ErrNoRows)
+
+	_, err = NewRoomRepository(db).GetRoomByName(context.Background(), "general")
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func Test_If_Rooms_Visible_To_User_Are_Listed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows(roomTableColumns).
+		AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()).
+		AddRow(6, "secret", "", "private", "channel", 1, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE archived_at IS NULL AND kind = 'channel'").WithArgs(int32(1)).WillReturnRows(rows)
+
+	rooms, err := NewRoomRepository(db).ListRoomsVisibleTo(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Len(t, rooms, 2)
+	assert.Equal(t, "secret", rooms[1].Name)
+}
+
+func Test_If_The_Room_Is_Updated_And_Archived(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	roomRepo := NewRoomRepository(db)
+	room := &domain.Room{ID: 5, Name: "random", Topic: "Off topic", Visibility: domain.RoomPrivate}
+
+	mock.ExpectExec("UPDATE room SET name").WithArgs(int32(5), "random", "Off topic", domain.RoomPrivate).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE room SET archived_at").WithArgs(int32(5), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, roomRepo.Update(context.Background(), room))
+	assert.Nil(t, roomRepo.Archive(context.Background(), 5))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
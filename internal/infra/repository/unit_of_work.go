@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	maxTransactionAttempts = 3
+	serializationFailure   = "40001"
+)
+
+type txKey struct{}
+
+// executor is the part of *sql.DB and *sql.Tx the repositories use, so the
+// same query runs inside or outside a unit of work.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// executorFrom returns the transaction of the unit of work running in ctx, or
+// db when there is none.
+func executorFrom(ctx context.Context, db *sql.DB) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTransaction runs fn in the unit of work running in ctx, or in a
+// transaction of its own when there is none.
+func inTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type UnitOfWork struct {
+	Db *sql.DB
+}
+
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{
+		Db: db,
+	}
+}
+
+// Do runs fn in a serializable transaction that every repository joins when
+// called with the context fn receives. The transaction commits when fn
+// returns nil and rolls back otherwise. Serialization failures are retried,
+// so fn must not have side effects outside the database; a nested Do joins
+// the outer transaction.
+func (uow *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 0; attempt < maxTransactionAttempts; attempt++ {
+		err = uow.attempt(ctx, fn)
+		if !isSerializationFailure(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (uow *UnitOfWork) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := uow.Db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == serializationFailure
+}
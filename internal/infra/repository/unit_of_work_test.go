@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Unit_Of_Work_Commits_Every_Repository_Call(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	userRepo := NewUserRepository(db)
+	userDomain, _ := domain.NewUser(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}))
+	mock.ExpectQuery("INSERT INTO app_user").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err = NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		_, _ = userRepo.GetUserByUserNameOrEmail(ctx, userDomain.UserName)
+		_, err := userRepo.Save(ctx, userDomain)
+		return err
+	})
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Unit_Of_Work_Is_Rolled_Back_On_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("error to create user")
+	})
+	assert.EqualError(t, err, "error to create user")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Unit_Of_Work_Retries_Serialization_Failures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM room_member").WillReturnError(&pq.Error{Code: serializationFailure})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM room_member").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	attempts := 0
+	err = NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return NewRoomMemberRepository(db).Delete(ctx, 5, 3)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_A_Nested_Transaction_Joins_The_Unit_Of_Work(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	message, _ := domain.NewMessage(5, 1, "hello")
+	previous, _ := message.Edit("hello, world", 1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO message_edit").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE message SET body").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE message SET deleted_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	messageRepo := NewMessageRepository(db)
+	err = NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		if err := messageRepo.Edit(ctx, message, previous); err != nil {
+			return err
+		}
+		return messageRepo.SoftDelete(ctx, message.ID)
+	})
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -1,12 +1,28 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/lib/pq"
 )
 
-const IdError = int32(-1)
+const userColumns = "id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at"
+
+const (
+	IdError         = int32(-1)
+	uniqueViolation = "23505"
+)
+
+// userUniqueIndexes maps the unique indexes of app_user to the field they
+// protect.
+var userUniqueIndexes = map[string]string{
+	"app_user_username_key": "username",
+	"app_user_email_key":    "email",
+}
 
 type UserRepository struct {
 	Db *sql.DB
@@ -18,23 +34,76 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	}
 }
 
-func (userRepo *UserRepository) Save(user *domain.User) (int32, error) {
+func (userRepo *UserRepository) Save(ctx context.Context, user *domain.User) (int32, error) {
 	lastInsertId := 0
-	err := userRepo.Db.QueryRow("INSERT INTO app_user (username, displayname, email, password, created) VALUES ($1,$2,$3,$4,$5) RETURNING id",
-		user.UserName, user.DisplayName, user.Email, user.Password, user.Created).Scan(&lastInsertId)
+	err := executorFrom(ctx, userRepo.Db).QueryRowContext(ctx, "INSERT INTO app_user (username, displayname, email, password, bio, avatar_url, email_verified_at, created) VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id",
+		user.UserName, user.DisplayName, user.Email, user.Password, user.Bio, user.AvatarURL, user.EmailVerifiedAt, user.Created).Scan(&lastInsertId)
 	if err != nil {
-		return IdError, err
+		return IdError, translateUserError(err)
 	}
 
 	return int32(lastInsertId), nil
 }
 
-func (userRepo *UserRepository) GetUserByUserNameOrEmail(userNameOrEmail string) (*domain.User, error) {
+func (userRepo *UserRepository) GetUserByUserNameOrEmail(ctx context.Context, userNameOrEmail string) (*domain.User, error) {
+	return scanUser(executorFrom(ctx, userRepo.Db).QueryRowContext(ctx, "SELECT "+userColumns+" FROM app_user WHERE lower(username) = lower($1) or lower(email) = lower($1)", userNameOrEmail))
+}
+
+func (userRepo *UserRepository) GetUserByUserName(ctx context.Context, userName string) (*domain.User, error) {
+	return scanUser(executorFrom(ctx, userRepo.Db).QueryRowContext(ctx, "SELECT "+userColumns+" FROM app_user WHERE lower(username) = lower($1)", userName))
+}
+
+func (userRepo *UserRepository) GetUserById(ctx context.Context, id int32) (*domain.User, error) {
+	return scanUser(executorFrom(ctx, userRepo.Db).QueryRowContext(ctx, "SELECT "+userColumns+" FROM app_user WHERE id = $1", id))
+}
+
+func (userRepo *UserRepository) UpdateProfile(ctx context.Context, user *domain.User) error {
+	_, err := executorFrom(ctx, userRepo.Db).ExecContext(ctx, "UPDATE app_user SET displayname = $2, bio = $3, avatar_url = $4 WHERE id = $1",
+		user.ID, user.DisplayName, user.Bio, user.AvatarURL)
+	return err
+}
+
+func (userRepo *UserRepository) UpdatePassword(ctx context.Context, userID int32, passwordHash string) error {
+	_, err := executorFrom(ctx, userRepo.Db).ExecContext(ctx, "UPDATE app_user SET password = $2 WHERE id = $1", userID, passwordHash)
+	return err
+}
+
+// MarkEmailAsVerified keeps the first verification time when the user
+// verifies twice.
+func (userRepo *UserRepository) MarkEmailAsVerified(ctx context.Context, userID int32) error {
+	_, err := executorFrom(ctx, userRepo.Db).ExecContext(ctx, "UPDATE app_user SET email_verified_at = $2 WHERE id = $1 AND email_verified_at IS NULL", userID, time.Now())
+	return err
+}
+
+func (userRepo *UserRepository) MarkPasswordAsChanged(ctx context.Context, userID int32, changedAt time.Time) error {
+	_, err := executorFrom(ctx, userRepo.Db).ExecContext(ctx, "UPDATE app_user SET password_changed_at = $2 WHERE id = $1", userID, changedAt)
+	return err
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
 	user := domain.User{}
-	err := userRepo.Db.QueryRow("SELECT id, username, displayname, email, password, created FROM app_user WHERE username = $1 or email = $1", userNameOrEmail).Scan(
-		&user.ID, &user.UserName, &user.DisplayName, &user.Email, &user.Password, &user.Created)
+	var emailVerifiedAt, passwordChangedAt sql.NullTime
+	err := row.Scan(&user.ID, &user.UserName, &user.DisplayName, &user.Email, &user.Password, &user.Bio, &user.AvatarURL, &emailVerifiedAt, &user.Created, &passwordChangedAt)
 	if err != nil {
 		return nil, err
 	}
+	user.EmailVerifiedAt = nullTimeToPointer(emailVerifiedAt)
+	user.PasswordChangedAt = nullTimeToPointer(passwordChangedAt)
 	return &user, nil
 }
+
+// translateUserError turns a unique violation into a conflict naming the
+// field that collided, so concurrent signups cannot both succeed.
+func translateUserError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return err
+	}
+
+	field, ok := userUniqueIndexes[pqErr.Constraint]
+	if !ok {
+		return err
+	}
+
+	return domain.NewConflictError(field, field+" already exists")
+}
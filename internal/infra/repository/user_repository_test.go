@@ -1,13 +1,16 @@
 package repository
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +22,7 @@ func (a AnyTime) Match(v driver.Value) bool {
 }
 
 func Test_If_The_User_Is_Saved(t *testing.T) {
-	const insertQuery = "INSERT INTO app_user"
+	const insertQuery = "INSERT INTO app_user \\(username, displayname, email, password, bio, avatar_url, email_verified_at, created\\) VALUES \\(\\$1,\\$2,\\$3,\\$4,\\$5,\\$6,\\$7,\\$8\\)"
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -30,9 +33,9 @@ func Test_If_The_User_Is_Saved(t *testing.T) {
 	userDomain, _ := domain.NewUser(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd")
 
 	rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-	mock.ExpectQuery(insertQuery).WithArgs(userDomain.UserName, userDomain.DisplayName, userDomain.Email, userDomain.Password, AnyTime{}).WillReturnRows(rows)
+	mock.ExpectQuery(insertQuery).WithArgs(userDomain.UserName, userDomain.DisplayName, userDomain.Email, userDomain.Password, "", "", nil, AnyTime{}).WillReturnRows(rows)
 	var createdId int32
-	if createdId, err = userRepo.Save(userDomain); err != nil {
+	if createdId, err = userRepo.Save(context.Background(), userDomain); err != nil {
 		t.Errorf("error was not expected while insert user: %s", err)
 	}
 
@@ -54,9 +57,9 @@ func Test_If_Get_Error_The_User_Is_Not_Saved(t *testing.T) {
 	userRepo := NewUserRepository(db)
 	userDomain, _ := domain.NewUser(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd")
 
-	mock.ExpectQuery(insertQuery).WithArgs(userDomain.UserName, userDomain.DisplayName, userDomain.Email, userDomain.Password, AnyTime{}).WillReturnError(errors.New("error to insert user"))
+	mock.ExpectQuery(insertQuery).WithArgs(userDomain.UserName, userDomain.DisplayName, userDomain.Email, userDomain.Password, "", "", nil, AnyTime{}).WillReturnError(errors.New("error to insert user"))
 	var createdId int32
-	if createdId, err = userRepo.Save(userDomain); err != nil {
+	if createdId, err = userRepo.Save(context.Background(), userDomain); err != nil {
 		assert.EqualError(t, err, "error to insert user")
 		assert.Equal(t, IdError, createdId)
 	}
@@ -66,18 +69,47 @@ func Test_If_Get_Error_The_User_Is_Not_Saved(t *testing.T) {
 	}
 }
 
+func Test_If_A_Taken_Username_Or_Email_Is_A_Conflict(t *testing.T) {
+	const insertQuery = "INSERT INTO app_user"
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	userRepo := NewUserRepository(db)
+	userDomain, _ := domain.NewUser(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd")
+
+	mock.ExpectQuery(insertQuery).WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "app_user_username_key"})
+	mock.ExpectQuery(insertQuery).WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "app_user_email_key"})
+
+	_, err = userRepo.Save(context.Background(), userDomain)
+	var conflictErr *domain.Error
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "username", conflictErr.Fields[0].Field)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	_, err = userRepo.Save(context.Background(), userDomain)
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "email", conflictErr.Fields[0].Field)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func Test_If_The_User_Fetched_When_Search_By_UserName(t *testing.T) {
-	const selectQuery = "SELECT id, username, displayname, email, password, created FROM app_user"
+	const selectQuery = "SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user"
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer db.Close()
 	timestamp := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
-	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", timestamp)
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, timestamp, nil)
 	mock.ExpectQuery(selectQuery).WithArgs("eduardolima806").WillReturnRows(rows)
 	userRepo := NewUserRepository(db)
-	fetchedUser, err := userRepo.GetUserByUserNameOrEmail("eduardolima806")
+	fetchedUser, err := userRepo.GetUserByUserNameOrEmail(context.Background(), "eduardolima806")
 
 	if err != nil {
 		t.Errorf("error was not expected while fetching user: %s", err)
@@ -98,7 +130,7 @@ func Test_If_The_User_Fetched_When_Search_By_UserName(t *testing.T) {
 }
 
 func Test_If_Get_Error_When_Search_By_UserName(t *testing.T) {
-	const selectQuery = "SELECT id, username, displayname, email, password, created FROM app_user"
+	const selectQuery = "SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user"
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -107,7 +139,7 @@ func Test_If_Get_Error_When_Search_By_UserName(t *testing.T) {
 	mock.ExpectQuery(selectQuery).WithArgs("eduardolima806").WillReturnError(errors.New("error to get user"))
 	userRepo := NewUserRepository(db)
 
-	_, err = userRepo.GetUserByUserNameOrEmail("eduardolima806")
+	_, err = userRepo.GetUserByUserNameOrEmail(context.Background(), "eduardolima806")
 
 	assert.EqualError(t, err, "error to get user")
 
@@ -115,3 +147,185 @@ func Test_If_Get_Error_When_Search_By_UserName(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func Test_If_The_User_Fetched_When_Search_By_Id(t *testing.T) {
+	const selectQuery = "SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user WHERE id"
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	timestamp := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, timestamp, nil)
+	mock.ExpectQuery(selectQuery).WithArgs(int32(1)).WillReturnRows(rows)
+	userRepo := NewUserRepository(db)
+	fetchedUser, err := userRepo.GetUserById(context.Background(), 1)
+
+	if err != nil {
+		t.Errorf("error was not expected while fetching user: %s", err)
+	}
+
+	assert.Equal(t, int32(1), fetchedUser.ID)
+	assert.Equal(t, "eduardolima806", fetchedUser.UserName)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Public_Profile_Is_Fetched_By_UserName(t *testing.T) {
+	const selectQuery = "SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user WHERE lower\\(username\\) = lower\\(\\$1\\)$"
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "Go developer", "https://cdn.example.com/eduardo.png", nil, time.Now(), nil)
+	mock.ExpectQuery(selectQuery).WithArgs("EduardoLima806").WillReturnRows(rows)
+	userRepo := NewUserRepository(db)
+
+	fetchedUser, err := userRepo.GetUserByUserName(context.Background(), "EduardoLima806")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Go developer", fetchedUser.Bio)
+	assert.Equal(t, "https://cdn.example.com/eduardo.png", fetchedUser.AvatarURL)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Profile_Is_Updated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	user := &domain.User{ID: 1, DisplayName: "Eduardo", Bio: "Go developer", AvatarURL: "https://cdn.example.com/eduardo.png"}
+	mock.ExpectExec("UPDATE app_user SET displayname").WithArgs(int32(1), "Eduardo", "Go developer", "https://cdn.example.com/eduardo.png").WillReturnResult(sqlmock.NewResult(0, 1))
+	userRepo := NewUserRepository(db)
+
+	err = userRepo.UpdateProfile(context.Background(), user)
+
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// captured matches any value and keeps it, so the row read back below holds
+// what was actually sent for each column.
+type captured struct {
+	value driver.Value
+}
+
+func (c *captured) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
+func Test_If_A_User_With_A_Profile_Is_Saved_And_Read_Back(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	userRepo := NewUserRepository(db)
+
+	user, _ := domain.NewUser(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd")
+	user.Bio = "Go developer"
+	user.AvatarURL = "https://cdn.example.com/eduardo.png"
+	verifiedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	user.EmailVerifiedAt = &verifiedAt
+
+	columns := []string{"username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created"}
+	values := make([]*captured, len(columns))
+	args := make([]driver.Value, len(columns))
+	for i := range values {
+		values[i] = &captured{}
+		args[i] = values[i]
+	}
+	mock.ExpectQuery("INSERT INTO app_user \\(" + strings.Join(columns, ", ") + "\\)").WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	id, err := userRepo.Save(context.Background(), user)
+	assert.Nil(t, err)
+
+	row := []driver.Value{int64(id)}
+	for _, value := range values {
+		row = append(row, value.value)
+	}
+	row = append(row, nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(7)).
+		WillReturnRows(sqlmock.NewRows(append(append([]string{"id"}, columns...), "password_changed_at")).AddRow(row...))
+
+	fetched, err := userRepo.GetUserById(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(7), fetched.ID)
+	assert.Equal(t, user.UserName, fetched.UserName)
+	assert.Equal(t, user.DisplayName, fetched.DisplayName)
+	assert.Equal(t, user.Email, fetched.Email)
+	assert.Equal(t, user.Password, fetched.Password)
+	assert.Equal(t, user.Bio, fetched.Bio)
+	assert.Equal(t, user.AvatarURL, fetched.AvatarURL)
+	assert.Equal(t, user.EmailVerifiedAt, fetched.EmailVerifiedAt)
+	assert.True(t, user.Created.Equal(fetched.Created))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Password_Is_Updated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("UPDATE app_user SET password").WithArgs(int32(1), "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewUserRepository(db).UpdatePassword(context.Background(), 1, "hash")
+
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Password_Is_Marked_As_Changed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	changedAt := time.Now()
+	mock.ExpectExec("UPDATE app_user SET password_changed_at = \\$2 WHERE id = \\$1").WithArgs(int32(1), changedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewUserRepository(db).MarkPasswordAsChanged(context.Background(), 1, changedAt)
+
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_If_The_Email_Is_Marked_As_Verified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec("UPDATE app_user SET email_verified_at (.+) AND email_verified_at IS NULL").WithArgs(int32(1), AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewUserRepository(db).MarkEmailAsVerified(context.Background(), 1)
+
+	assert.Nil(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,89 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+// InMemoryStore keeps the counters of this process only. It suits a single
+// instance, and tests.
+type InMemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		attempts: make(map[string]domain.LoginAttempt),
+	}
+}
+
+func (s *InMemoryStore) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = domain.LoginAttempt{Key: key}
+	}
+	return copyAttempt(attempt), nil
+}
+
+func (s *InMemoryStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok || isStale(attempt, now.Add(-window)) {
+		attempt = domain.LoginAttempt{Key: key}
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	s.attempts[key] = attempt
+
+	return copyAttempt(attempt), nil
+}
+
+func (s *InMemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = domain.LoginAttempt{Key: key}
+	}
+	attempt.LockedUntil = &until
+	s.attempts[key] = attempt
+	return nil
+}
+
+func (s *InMemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// isStale tells whether the failures of attempt happened before since, the
+// end of its lockout included.
+func isStale(attempt domain.LoginAttempt, since time.Time) bool {
+	lastActivity := attempt.LastFailureAt
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(lastActivity) {
+		lastActivity = *attempt.LockedUntil
+	}
+	return lastActivity.Before(since)
+}
+
+// copyAttempt keeps callers from changing the stored lockout through the
+// returned pointer.
+func copyAttempt(attempt domain.LoginAttempt) *domain.LoginAttempt {
+	if attempt.LockedUntil != nil {
+		lockedUntil := *attempt.LockedUntil
+		attempt.LockedUntil = &lockedUntil
+	}
+	return &attempt
+}
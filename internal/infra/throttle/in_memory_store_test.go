@@ -0,0 +1,56 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Failures_Add_Up_Within_The_Window(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	store.RecordFailure(ctx, "ip:127.0.0.1", now, time.Minute)
+	attempt, err := store.RecordFailure(ctx, "ip:127.0.0.1", now.Add(30*time.Second), time.Minute)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempt.Failures)
+}
+
+func Test_If_Failures_Are_Forgotten_After_The_Window(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	store.RecordFailure(ctx, "ip:127.0.0.1", now, time.Minute)
+	store.Lock(ctx, "ip:127.0.0.1", now.Add(time.Hour))
+	attempt, _ := store.RecordFailure(ctx, "ip:127.0.0.1", now.Add(30*time.Minute), time.Minute)
+	assert.Equal(t, 2, attempt.Failures, "the window counts from the end of the lockout")
+
+	attempt, _ = store.RecordFailure(ctx, "ip:127.0.0.1", now.Add(2*time.Hour), time.Minute)
+	assert.Equal(t, 1, attempt.Failures)
+	assert.Nil(t, attempt.LockedUntil)
+}
+
+func Test_If_Lock_And_Reset_Are_Kept_Per_Key(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	store.RecordFailure(ctx, "user:1", now, time.Minute)
+	assert.Nil(t, store.Lock(ctx, "user:1", now.Add(time.Minute)))
+
+	attempt, _ := store.Get(ctx, "user:1")
+	assert.True(t, attempt.IsLocked(now))
+	other, _ := store.Get(ctx, "user:2")
+	assert.False(t, other.IsLocked(now))
+	assert.Equal(t, 0, other.Failures)
+
+	assert.Nil(t, store.Reset(ctx, "user:1"))
+	attempt, _ = store.Get(ctx, "user:1")
+	assert.False(t, attempt.IsLocked(now))
+	assert.Equal(t, 0, attempt.Failures)
+}
@@ -0,0 +1,62 @@
+package throttle
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+// PostgresStore shares the counters between instances, so a client cannot
+// spread its attempts over them.
+type PostgresStore struct {
+	Db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{
+		Db: db,
+	}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	attempt, err := scanAttempt(s.Db.QueryRowContext(ctx, "SELECT key, failures, last_failure_at, locked_until FROM login_attempt WHERE key = $1", key))
+	if err == sql.ErrNoRows {
+		return &domain.LoginAttempt{Key: key}, nil
+	}
+	return attempt, err
+}
+
+// RecordFailure counts the failure in a single statement, so concurrent
+// failures on several instances all add up.
+func (s *PostgresStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error) {
+	return scanAttempt(s.Db.QueryRowContext(ctx, `INSERT INTO login_attempt (key, failures, last_failure_at) VALUES ($1, 1, $2)
+ON CONFLICT (key) DO UPDATE SET
+  failures = CASE WHEN GREATEST(login_attempt.last_failure_at, login_attempt.locked_until) < $3 THEN 1 ELSE login_attempt.failures + 1 END,
+  locked_until = CASE WHEN GREATEST(login_attempt.last_failure_at, login_attempt.locked_until) < $3 THEN NULL ELSE login_attempt.locked_until END,
+  last_failure_at = $2
+RETURNING key, failures, last_failure_at, locked_until`, key, now, now.Add(-window)))
+}
+
+func (s *PostgresStore) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := s.Db.ExecContext(ctx, "UPDATE login_attempt SET locked_until = $2 WHERE key = $1", key, until)
+	return err
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	_, err := s.Db.ExecContext(ctx, "DELETE FROM login_attempt WHERE key = $1", key)
+	return err
+}
+
+func scanAttempt(row *sql.Row) (*domain.LoginAttempt, error) {
+	attempt := domain.LoginAttempt{}
+	var lockedUntil sql.NullTime
+	if err := row.Scan(&attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &lockedUntil); err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		attempt.LockedUntil = &lockedUntil.Time
+	}
+	return &attempt, nil
+}
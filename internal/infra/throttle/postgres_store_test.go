@@ -0,0 +1,82 @@
+package throttle
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var attemptColumns = []string{"key", "failures", "last_failure_at", "locked_until"}
+
+func Test_If_Missing_Attempt_Has_No_Failures(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT key, failures, last_failure_at, locked_until FROM login_attempt WHERE key = \\$1").WithArgs("user:1").WillReturnError(sql.ErrNoRows)
+
+	attempt, err := NewPostgresStore(db).Get(context.Background(), "user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, "user:1", attempt.Key)
+	assert.Equal(t, 0, attempt.Failures)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Locked_Attempt_Is_Fetched(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	lockedUntil := now.Add(time.Minute)
+	mock.ExpectQuery("SELECT (.+) FROM login_attempt").WithArgs("user:1").
+		WillReturnRows(sqlmock.NewRows(attemptColumns).AddRow("user:1", 5, now, lockedUntil))
+
+	attempt, err := NewPostgresStore(db).Get(context.Background(), "user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, attempt.Failures)
+	assert.True(t, attempt.IsLocked(now))
+}
+
+func Test_If_Failure_Is_Counted_Atomically(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("INSERT INTO login_attempt (.+) ON CONFLICT \\(key\\) DO UPDATE").WithArgs("ip:127.0.0.1", now, now.Add(-time.Minute)).
+		WillReturnRows(sqlmock.NewRows(attemptColumns).AddRow("ip:127.0.0.1", 3, now, nil))
+
+	attempt, err := NewPostgresStore(db).RecordFailure(context.Background(), "ip:127.0.0.1", now, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempt.Failures)
+	assert.Nil(t, attempt.LockedUntil)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Failure_Error_Is_Returned(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO login_attempt").WillReturnError(errors.New("an internal error"))
+
+	attempt, err := NewPostgresStore(db).RecordFailure(context.Background(), "ip:127.0.0.1", time.Now(), time.Minute)
+	assert.Nil(t, attempt)
+	assert.NotNil(t, err)
+}
+
+func Test_If_Key_Is_Locked_And_Reset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	until := time.Now().Add(time.Minute)
+	mock.ExpectExec("UPDATE login_attempt SET locked_until = \\$2 WHERE key = \\$1").WithArgs("user:1", until).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM login_attempt WHERE key = \\$1").WithArgs("user:1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	store := NewPostgresStore(db)
+	assert.Nil(t, store.Lock(context.Background(), "user:1", until))
+	assert.Nil(t, store.Reset(context.Background(), "user:1"))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,17 @@
+package conversation_usecase
+
+import (
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ConversationBaseUseCase struct {
+	OpenDirectConversationUseCase OpenDirectConversationUseCaseInterface
+	ListConversationsUseCase      ListConversationsUseCaseInterface
+}
+
+func NewConversationBaseUseCase(userRepository domain.UserRepositoryInterface, roomRepository domain.RoomRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, conversationRepository domain.ConversationRepositoryInterface, eventPublisher domain.EventPublisherInterface, unitOfWork domain.UnitOfWorkInterface) *ConversationBaseUseCase {
+	return &ConversationBaseUseCase{
+		OpenDirectConversationUseCase: NewOpenDirectConversationUseCase(userRepository, roomRepository, roomMemberRepository, eventPublisher, unitOfWork),
+		ListConversationsUseCase:      NewListConversationsUseCase(conversationRepository),
+	}
+}
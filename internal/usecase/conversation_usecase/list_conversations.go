@@ -0,0 +1,38 @@
+package conversation_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ListConversationsInput struct {
+	UserID int32
+}
+
+type ListConversationsOutput struct {
+	Conversations []*domain.Conversation
+}
+
+type ListConversationsUseCaseInterface interface {
+	Execute(ctx context.Context, input ListConversationsInput) (*ListConversationsOutput, error)
+}
+
+type ListConversationsUseCase struct {
+	ConversationRepository domain.ConversationRepositoryInterface
+}
+
+func NewListConversationsUseCase(conversationRepository domain.ConversationRepositoryInterface) *ListConversationsUseCase {
+	return &ListConversationsUseCase{
+		ConversationRepository: conversationRepository,
+	}
+}
+
+func (uc *ListConversationsUseCase) Execute(ctx context.Context, input ListConversationsInput) (*ListConversationsOutput, error) {
+	conversations, err := uc.ConversationRepository.ListConversations(ctx, input.UserID)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to list conversations", err)
+	}
+
+	return &ListConversationsOutput{Conversations: conversations}, nil
+}
@@ -0,0 +1,38 @@
+package conversation_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var conversationColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created", "last_activity_at", "peer_id", "peer_username", "peer_displayname", "last_read_message_id", "unread_count"}
+
+func Test_Conversations_Are_Listed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucList := NewListConversationsUseCase(repository.NewConversationRepository(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room_member me").WithArgs(int32(1)).WillReturnRows(sqlmock.NewRows(conversationColumns).
+		AddRow(7, "dm:1:3", "", "private", "direct", 1, nil, time.Now(), time.Now(), 3, "joaquim2019", "Joaquim", 0, 0))
+
+	output, err := ucList.Execute(context.Background(), ListConversationsInput{UserID: 1})
+	assert.Nil(t, err)
+	assert.Len(t, output.Conversations, 1)
+	assert.Equal(t, "Joaquim", output.Conversations[0].Peer.DisplayName)
+}
+
+func Test_If_Get_Error_To_List_Conversations(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucList := NewListConversationsUseCase(repository.NewConversationRepository(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room_member me").WillReturnError(errors.New("an internal error"))
+
+	_, err := ucList.Execute(context.Background(), ListConversationsInput{UserID: 1})
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+}
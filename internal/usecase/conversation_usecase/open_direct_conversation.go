@@ -0,0 +1,139 @@
+package conversation_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type OpenDirectConversationInput struct {
+	UserID int32
+	Login  string
+}
+
+type OpenDirectConversationOutput struct {
+	Room    *domain.Room
+	Peer    *domain.User
+	Created bool
+}
+
+// ConversationPayload is the body of the conversation.new event. Each
+// participant receives the other one as peer.
+type ConversationPayload struct {
+	ID              int32  `json:"id"`
+	Kind            string `json:"kind"`
+	PeerID          int32  `json:"peerId"`
+	PeerUserName    string `json:"peerUserName"`
+	PeerDisplayName string `json:"peerDisplayName"`
+}
+
+type OpenDirectConversationUseCaseInterface interface {
+	Execute(ctx context.Context, input OpenDirectConversationInput) (*OpenDirectConversationOutput, error)
+}
+
+type OpenDirectConversationUseCase struct {
+	UserRepository       domain.UserRepositoryInterface
+	RoomRepository       domain.RoomRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	EventPublisher       domain.EventPublisherInterface
+	UnitOfWork           domain.UnitOfWorkInterface
+}
+
+func NewOpenDirectConversationUseCase(userRepository domain.UserRepositoryInterface, roomRepository domain.RoomRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, eventPublisher domain.EventPublisherInterface, unitOfWork domain.UnitOfWorkInterface) *OpenDirectConversationUseCase {
+	return &OpenDirectConversationUseCase{
+		UserRepository:       userRepository,
+		RoomRepository:       roomRepository,
+		RoomMemberRepository: roomMemberRepository,
+		EventPublisher:       eventPublisher,
+		UnitOfWork:           unitOfWork,
+	}
+}
+
+// Execute returns the direct conversation between the user and the peer,
+// creating it on first use. Opening it again, from either side, yields the
+// same room.
+func (uc *OpenDirectConversationUseCase) Execute(ctx context.Context, input OpenDirectConversationInput) (*OpenDirectConversationOutput, error) {
+	peer, err := uc.UserRepository.GetUserByUserNameOrEmail(ctx, input.Login)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.NewError(domain.ErrNotFound, "user not found")
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	if peer.ID == input.UserID {
+		return nil, domain.NewError(domain.ErrBadRequest, "cannot open a conversation with yourself")
+	}
+
+	name := domain.DirectRoomName(input.UserID, peer.ID)
+
+	room, err := uc.RoomRepository.GetRoomByName(ctx, name)
+	if err == nil {
+		return &OpenDirectConversationOutput{Room: room, Peer: peer}, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch conversation", err)
+	}
+
+	// The room and both participants are saved at once, so a conversation
+	// never exists without one of them.
+	room = domain.NewDirectRoom(input.UserID, peer.ID)
+	err = uc.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		id, err := uc.RoomRepository.Save(ctx, room)
+		if err != nil {
+			return err
+		}
+		room.ID = id
+
+		for _, userID := range []int32{input.UserID, peer.ID} {
+			member, err := domain.NewRoomMember(room.ID, userID, domain.RoomRoleMember, domain.MembershipActive)
+			if err != nil {
+				return err
+			}
+			if err := uc.RoomMemberRepository.Save(ctx, member); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		// Both users may open the conversation at once; the unique room name
+		// lets only one insert win and the other picks its room up.
+		existing, lookupErr := uc.RoomRepository.GetRoomByName(ctx, name)
+		if lookupErr != nil {
+			return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save conversation", err)
+		}
+		return &OpenDirectConversationOutput{Room: existing, Peer: peer}, nil
+	}
+
+	uc.publish(ctx, room, input.UserID, peer)
+
+	return &OpenDirectConversationOutput{Room: room, Peer: peer, Created: true}, nil
+}
+
+func (uc *OpenDirectConversationUseCase) publish(ctx context.Context, room *domain.Room, userID int32, peer *domain.User) {
+	if event, err := domain.NewEvent(domain.ConversationNewEvent, toConversationPayload(room, peer)); err == nil {
+		uc.EventPublisher.SendToUsers([]int32{userID}, event)
+	}
+
+	user, err := uc.UserRepository.GetUserById(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	if event, err := domain.NewEvent(domain.ConversationNewEvent, toConversationPayload(room, user)); err == nil {
+		uc.EventPublisher.SendToUsers([]int32{peer.ID}, event)
+	}
+}
+
+func toConversationPayload(room *domain.Room, peer *domain.User) ConversationPayload {
+	return ConversationPayload{
+		ID:              room.ID,
+		Kind:            string(room.Kind),
+		PeerID:          peer.ID,
+		PeerUserName:    peer.UserName,
+		PeerDisplayName: peer.DisplayName,
+	}
+}
@@ -0,0 +1,135 @@
+package conversation_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}
+var roomColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created"}
+
+type recordingPublisher struct {
+	userIDs []int32
+	events  []domain.Event
+}
+
+func (p *recordingPublisher) SendToUsers(userIDs []int32, event domain.Event) {
+	p.userIDs = append(p.userIDs, userIDs...)
+	p.events = append(p.events, event)
+}
+
+func newOpenDirectConversationUseCase(db *sql.DB, publisher domain.EventPublisherInterface) *OpenDirectConversationUseCase {
+	return NewOpenDirectConversationUseCase(repository.NewUserRepository(db), repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db), publisher, repository.NewUnitOfWork(db))
+}
+
+func expectPeer(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE").WithArgs("joaquim2019").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "joaquim2019", "Joaquim", "joaquim@gmail.com", "hash", "", "", nil, time.Now(), nil))
+}
+
+func Test_Direct_Conversation_Is_Created(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectPeer(mock)
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WithArgs("dm:1:3").WillReturnError(sql.ErrNoRows)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO room").WithArgs("dm:1:3", "", domain.RoomPrivate, domain.RoomKindDirect, int32(1), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(7), int32(1), domain.RoomRoleMember, domain.MembershipActive, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(7), int32(3), domain.RoomRoleMember, domain.MembershipActive, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil))
+
+	output, err := newOpenDirectConversationUseCase(db, publisher).Execute(context.Background(), OpenDirectConversationInput{UserID: 1, Login: "joaquim2019"})
+	assert.Nil(t, err)
+	assert.True(t, output.Created)
+	assert.Equal(t, int32(7), output.Room.ID)
+	assert.Equal(t, int32(3), output.Peer.ID)
+
+	assert.Equal(t, []int32{1, 3}, publisher.userIDs)
+	assert.Equal(t, domain.ConversationNewEvent, publisher.events[0].Type)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Existing_Direct_Conversation_Is_Reused(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE").WithArgs("eduardolima806").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WithArgs("dm:1:3").
+		WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(7, "dm:1:3", "", "private", "direct", 1, nil, time.Now()))
+
+	output, err := newOpenDirectConversationUseCase(db, publisher).Execute(context.Background(), OpenDirectConversationInput{UserID: 3, Login: "eduardolima806"})
+	assert.Nil(t, err)
+	assert.False(t, output.Created)
+	assert.Equal(t, int32(7), output.Room.ID)
+	assert.Empty(t, publisher.events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Concurrent_Open_Picks_Up_The_Winning_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectPeer(mock)
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnError(sql.ErrNoRows)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO room").WillReturnError(errors.New("duplicate key value violates unique constraint"))
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").
+		WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(7, "dm:1:3", "", "private", "direct", 3, nil, time.Now()))
+
+	output, err := newOpenDirectConversationUseCase(db, publisher).Execute(context.Background(), OpenDirectConversationInput{UserID: 1, Login: "joaquim2019"})
+	assert.Nil(t, err)
+	assert.False(t, output.Created)
+	assert.Equal(t, int32(7), output.Room.ID)
+	assert.Empty(t, publisher.events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Direct_Conversation_Is_Not_Created_Without_Its_Participants(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectPeer(mock)
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnError(sql.ErrNoRows)
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO room").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO room_member").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO room_member").WillReturnError(errors.New("an internal error"))
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnError(sql.ErrNoRows)
+
+	output, err := newOpenDirectConversationUseCase(db, publisher).Execute(context.Background(), OpenDirectConversationInput{UserID: 1, Login: "joaquim2019"})
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	assert.Empty(t, publisher.events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Direct_Conversation_Requires_Another_Existing_User(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucOpen := newOpenDirectConversationUseCase(db, &recordingPublisher{})
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE").WillReturnError(sql.ErrNoRows)
+	_, err := ucOpen.Execute(context.Background(), OpenDirectConversationInput{UserID: 1, Login: "nobody"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	_, err = ucOpen.Execute(context.Background(), OpenDirectConversationInput{UserID: 1, Login: "eduardolima806"})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
@@ -0,0 +1,13 @@
+package event_usecase
+
+import "github.com/eduardolima806/my-chat-server/internal/domain"
+
+type EventBaseUseCase struct {
+	ReplayEventsUseCase ReplayEventsUseCaseInterface
+}
+
+func NewEventBaseUseCase(eventLogRepository domain.EventLogRepositoryInterface) *EventBaseUseCase {
+	return &EventBaseUseCase{
+		ReplayEventsUseCase: NewReplayEventsUseCase(eventLogRepository),
+	}
+}
@@ -0,0 +1,63 @@
+package event_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+// MaxReplayEvents bounds how much a reconnecting stream replays. A client
+// that missed more than this has to reload through the REST API.
+const MaxReplayEvents = 500
+
+type ReplayEventsInput struct {
+	UserID  int32
+	AfterID int64
+}
+
+type ReplayEventsOutput struct {
+	Events    []domain.Event
+	Truncated bool
+}
+
+type ReplayEventsUseCaseInterface interface {
+	Execute(ctx context.Context, input ReplayEventsInput) (*ReplayEventsOutput, error)
+}
+
+type ReplayEventsUseCase struct {
+	EventLogRepository domain.EventLogRepositoryInterface
+}
+
+func NewReplayEventsUseCase(eventLogRepository domain.EventLogRepositoryInterface) *ReplayEventsUseCase {
+	return &ReplayEventsUseCase{
+		EventLogRepository: eventLogRepository,
+	}
+}
+
+// Execute returns the events published to a user after the given one, oldest
+// first. The replay is truncated when it hits the limit, or when events the
+// user may have missed were already dropped from the log.
+func (uc *ReplayEventsUseCase) Execute(ctx context.Context, input ReplayEventsInput) (*ReplayEventsOutput, error) {
+	if input.AfterID < 0 {
+		return nil, domain.NewError(domain.ErrBadRequest, "last event id is not valid")
+	}
+
+	// One extra row tells whether the replay had to stop short.
+	events, err := uc.EventLogRepository.ListAfter(ctx, input.UserID, input.AfterID, MaxReplayEvents+1)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to replay events", err)
+	}
+
+	// Read after listing, so events pruned meanwhile are noticed.
+	oldestID, err := uc.EventLogRepository.OldestID(ctx)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to replay events", err)
+	}
+
+	truncated := oldestID > input.AfterID+1 || len(events) > MaxReplayEvents
+	if len(events) > MaxReplayEvents {
+		events = events[:MaxReplayEvents]
+	}
+
+	return &ReplayEventsOutput{Events: events, Truncated: truncated}, nil
+}
@@ -0,0 +1,73 @@
+package event_usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var eventColumns = []string{"id", "type", "payload"}
+
+func expectEvents(mock sqlmock.Sqlmock, afterID int64, rows *sqlmock.Rows, oldestID int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE event_log").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, type, payload FROM event_log").WithArgs(int32(1), afterID, MaxReplayEvents+1).WillReturnRows(rows)
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT COALESCE\\(MIN\\(id\\), 0\\) FROM event_log").WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(oldestID))
+}
+
+func Test_Missed_Events_Of_Every_Type_Are_Replayed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectEvents(mock, 10, sqlmock.NewRows(eventColumns).
+		AddRow(11, domain.MessageUpdatedEvent, `{"id":42}`).
+		AddRow(12, domain.MessageDeletedEvent, `{"id":42}`).
+		AddRow(13, domain.ReceiptUpdatedEvent, `{"conversationId":5}`), 1)
+
+	output, err := NewReplayEventsUseCase(repository.NewEventLogRepository(db)).Execute(context.Background(), ReplayEventsInput{UserID: 1, AfterID: 10})
+	assert.Nil(t, err)
+	assert.False(t, output.Truncated)
+	assert.Len(t, output.Events, 3)
+	assert.Equal(t, []string{domain.MessageUpdatedEvent, domain.MessageDeletedEvent, domain.ReceiptUpdatedEvent},
+		[]string{output.Events[0].Type, output.Events[1].Type, output.Events[2].Type})
+	assert.Equal(t, int64(13), output.Events[2].ID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Replay_Is_Truncated_Past_The_Limit(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	rows := sqlmock.NewRows(eventColumns)
+	for id := 1; id <= MaxReplayEvents+1; id++ {
+		rows.AddRow(id, domain.MessageNewEvent, `{}`)
+	}
+	expectEvents(mock, 0, rows, 1)
+
+	output, err := NewReplayEventsUseCase(repository.NewEventLogRepository(db)).Execute(context.Background(), ReplayEventsInput{UserID: 1})
+	assert.Nil(t, err)
+	assert.True(t, output.Truncated)
+	assert.Len(t, output.Events, MaxReplayEvents)
+	assert.Equal(t, int64(MaxReplayEvents), output.Events[MaxReplayEvents-1].ID)
+}
+
+func Test_Replay_Is_Truncated_When_Missed_Events_Were_Pruned(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectEvents(mock, 10, sqlmock.NewRows(eventColumns).AddRow(30, domain.MessageNewEvent, `{}`), 20)
+
+	output, err := NewReplayEventsUseCase(repository.NewEventLogRepository(db)).Execute(context.Background(), ReplayEventsInput{UserID: 1, AfterID: 10})
+	assert.Nil(t, err)
+	assert.True(t, output.Truncated)
+	assert.Len(t, output.Events, 1)
+}
+
+func Test_Negative_Last_Event_Id_Is_Rejected(t *testing.T) {
+	db, _, _ := sqlmock.New()
+
+	_, err := NewReplayEventsUseCase(repository.NewEventLogRepository(db)).Execute(context.Background(), ReplayEventsInput{UserID: 1, AfterID: -1})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
@@ -0,0 +1,37 @@
+package message_usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const cursorPrefix = "msg:"
+
+var errInvalidCursor = errors.New("cursor is not valid")
+
+// Cursors are opaque to clients so the paging key can change without breaking
+// them; today it is the message ID.
+func encodeCursor(messageID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(messageID, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+
+	raw, ok := strings.CutPrefix(string(data), cursorPrefix)
+	if !ok {
+		return 0, errInvalidCursor
+	}
+
+	messageID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || messageID <= 0 {
+		return 0, errInvalidCursor
+	}
+
+	return messageID, nil
+}
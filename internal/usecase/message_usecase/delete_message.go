@@ -0,0 +1,73 @@
+package message_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type DeleteMessageInput struct {
+	UserID    int32
+	RoomID    int32
+	MessageID int64
+}
+
+type DeleteMessageUseCaseInterface interface {
+	Execute(ctx context.Context, input DeleteMessageInput) error
+}
+
+type DeleteMessageUseCase struct {
+	MessageRepository    domain.MessageRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface
+	EventPublisher       domain.EventPublisherInterface
+}
+
+func NewDeleteMessageUseCase(messageRepository domain.MessageRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface, eventPublisher domain.EventPublisherInterface) *DeleteMessageUseCase {
+	return &DeleteMessageUseCase{
+		MessageRepository:    messageRepository,
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+		EventPublisher:       eventPublisher,
+	}
+}
+
+// Execute soft deletes a message. Senders may delete their own messages and
+// room moderators anyone's. Deleting twice is not an error.
+func (uc *DeleteMessageUseCase) Execute(ctx context.Context, input DeleteMessageInput) error {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionParticipate,
+	})
+	if err != nil {
+		return err
+	}
+
+	message, err := fetchRoomMessage(ctx, uc.MessageRepository, input.RoomID, input.MessageID)
+	if err != nil {
+		return err
+	}
+
+	if !message.IsSentBy(input.UserID) && !access.Member.CanModerate() {
+		return domain.NewError(domain.ErrForbidden, "only the sender or room moderators can delete this message")
+	}
+
+	if message.IsDeleted() {
+		return nil
+	}
+
+	if err = uc.MessageRepository.SoftDelete(ctx, message.ID); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to delete message", err)
+	}
+
+	now := time.Now()
+	message.DeletedAt = &now
+	message.Body = ""
+
+	publishMessage(ctx, uc.RoomMemberRepository, uc.EventPublisher, domain.MessageDeletedEvent, message)
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package message_usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDeleteMessageUseCase(db *sql.DB, publisher domain.EventPublisherInterface) *DeleteMessageUseCase {
+	return NewDeleteMessageUseCase(repository.NewMessageRepository(db), repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db), publisher)
+}
+
+func expectRoomAccessAs(mock sqlmock.Sqlmock, role string) {
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.user_id = \\$2").
+		WillReturnRows(sqlmock.NewRows(roomMemberColumns).AddRow(5, 1, "eduardolima806", "Eduardo Lima", role, "active", nil, time.Now()))
+}
+
+func Test_Moderator_Deletes_Someone_Else_Message(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccessAs(mock, "admin")
+	expectMessage(mock, 5, 3, nil)
+	mock.ExpectExec("UPDATE message SET deleted_at").WithArgs(int64(42), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(sqlmock.NewRows(roomMemberColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "admin", "active", nil, time.Now()).
+		AddRow(5, 3, "joaquim2019", "", "member", "active", nil, time.Now()))
+
+	assert.Nil(t, newDeleteMessageUseCase(db, publisher).Execute(context.Background(), DeleteMessageInput{UserID: 1, RoomID: 5, MessageID: 42}))
+
+	assert.Equal(t, []int32{1, 3}, publisher.events[0].userIDs)
+	assert.Equal(t, domain.MessageDeletedEvent, publisher.events[0].event.Type)
+	var payload MessagePayload
+	assert.Nil(t, json.Unmarshal(publisher.events[0].event.Payload, &payload))
+	assert.Equal(t, int64(42), payload.ID)
+	assert.Empty(t, payload.Body)
+	assert.NotNil(t, payload.DeletedAt)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Member_Cannot_Delete_Someone_Else_Message(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccessAs(mock, "member")
+	expectMessage(mock, 5, 3, nil)
+
+	err := newDeleteMessageUseCase(db, &recordingPublisher{}).Execute(context.Background(), DeleteMessageInput{UserID: 1, RoomID: 5, MessageID: 42})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_Deleting_Twice_Is_Not_An_Error(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccessAs(mock, "member")
+	expectMessage(mock, 5, 1, time.Now())
+
+	assert.Nil(t, newDeleteMessageUseCase(db, publisher).Execute(context.Background(), DeleteMessageInput{UserID: 1, RoomID: 5, MessageID: 42}))
+	assert.Empty(t, publisher.events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Deleted_Message_Is_Listed_As_Tombstone(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("SELECT (.+) FROM message").WillReturnRows(sqlmock.NewRows(messageColumns).
+		AddRow(42, 5, 1, "Eduardo Lima", "secret", nil, time.Now(), time.Now()))
+
+	output, err := newListMessagesUseCase(db).Execute(context.Background(), ListMessagesInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.Len(t, output.Messages, 1)
+	assert.Empty(t, output.Messages[0].Body)
+	assert.True(t, output.Messages[0].IsDeleted())
+}
@@ -0,0 +1,105 @@
+package message_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type EditMessageInput struct {
+	UserID    int32
+	RoomID    int32
+	MessageID int64
+	Body      string
+}
+
+type MessageOutput struct {
+	Message *domain.Message
+}
+
+type EditMessageUseCaseInterface interface {
+	Execute(ctx context.Context, input EditMessageInput) (*MessageOutput, error)
+}
+
+type EditMessageUseCase struct {
+	MessageRepository    domain.MessageRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface
+	EventPublisher       domain.EventPublisherInterface
+}
+
+func NewEditMessageUseCase(messageRepository domain.MessageRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface, eventPublisher domain.EventPublisherInterface) *EditMessageUseCase {
+	return &EditMessageUseCase{
+		MessageRepository:    messageRepository,
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+		EventPublisher:       eventPublisher,
+	}
+}
+
+// Execute lets a sender rewrite their own message. Moderators cannot edit
+// other people's words, only delete them.
+func (uc *EditMessageUseCase) Execute(ctx context.Context, input EditMessageInput) (*MessageOutput, error) {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionParticipate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if access.Room.IsArchived() {
+		return nil, domain.NewError(domain.ErrBadRequest, "room is archived")
+	}
+
+	message, err := fetchRoomMessage(ctx, uc.MessageRepository, input.RoomID, input.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.IsDeleted() {
+		return nil, messageNotFoundError()
+	}
+
+	if !message.IsSentBy(input.UserID) {
+		return nil, domain.NewError(domain.ErrForbidden, "only the sender can edit this message")
+	}
+
+	previous, err := message.Edit(input.Body, input.UserID)
+	if err != nil {
+		return nil, domain.NewError(domain.ErrBadRequest, err.Error())
+	}
+
+	if err = uc.MessageRepository.Edit(ctx, message, previous); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to edit message", err)
+	}
+
+	publishMessage(ctx, uc.RoomMemberRepository, uc.EventPublisher, domain.MessageUpdatedEvent, message)
+
+	return &MessageOutput{Message: message}, nil
+}
+
+// fetchRoomMessage hides messages of other rooms behind the same not found
+// error, so a room permission cannot be used to reach into another room.
+func fetchRoomMessage(ctx context.Context, messageRepository domain.MessageRepositoryInterface, roomID int32, messageID int64) (*domain.Message, error) {
+	message, err := messageRepository.GetMessageById(ctx, messageID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, messageNotFoundError()
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch message", err)
+	}
+
+	if message.RoomID != roomID {
+		return nil, messageNotFoundError()
+	}
+
+	return message, nil
+}
+
+func messageNotFoundError() error {
+	return domain.NewError(domain.ErrNotFound, "message not found")
+}
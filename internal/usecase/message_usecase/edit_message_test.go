@@ -0,0 +1,93 @@
+package message_usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEditMessageUseCase(db *sql.DB, publisher domain.EventPublisherInterface) *EditMessageUseCase {
+	return NewEditMessageUseCase(repository.NewMessageRepository(db), repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db), publisher)
+}
+
+func expectMessage(mock sqlmock.Sqlmock, roomID int32, senderID int32, deletedAt any) {
+	mock.ExpectQuery("SELECT (.+) FROM message m (.+) WHERE m.id = \\$1").WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows(messageColumns).AddRow(42, roomID, senderID, "Eduardo Lima", "hello", nil, deletedAt, time.Now()))
+}
+
+func Test_Sender_Edits_Message(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 5, 1, nil)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO message_edit").WithArgs(int64(42), "hello", int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE message SET body").WithArgs(int64(42), "hello, world", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(sqlmock.NewRows(roomMemberColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()))
+
+	output, err := newEditMessageUseCase(db, publisher).Execute(context.Background(), EditMessageInput{UserID: 1, RoomID: 5, MessageID: 42, Body: "hello, world"})
+	assert.Nil(t, err)
+	assert.Equal(t, "hello, world", output.Message.Body)
+	assert.NotNil(t, output.Message.EditedAt)
+
+	assert.Equal(t, domain.MessageUpdatedEvent, publisher.events[0].event.Type)
+	var payload MessagePayload
+	assert.Nil(t, json.Unmarshal(publisher.events[0].event.Payload, &payload))
+	assert.NotNil(t, payload.EditedAt)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Only_Sender_Can_Edit_Message(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 5, 2, nil)
+
+	_, err := newEditMessageUseCase(db, &recordingPublisher{}).Execute(context.Background(), EditMessageInput{UserID: 1, RoomID: 5, MessageID: 42, Body: "hello, world"})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_Message_Of_Another_Room_Is_Not_Found(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 9, 1, nil)
+
+	_, err := newEditMessageUseCase(db, &recordingPublisher{}).Execute(context.Background(), EditMessageInput{UserID: 1, RoomID: 5, MessageID: 42, Body: "hello, world"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_Deleted_Message_Cannot_Be_Edited(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 5, 1, time.Now())
+
+	_, err := newEditMessageUseCase(db, &recordingPublisher{}).Execute(context.Background(), EditMessageInput{UserID: 1, RoomID: 5, MessageID: 42, Body: "hello, world"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_Message_Edits_Are_Listed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucListEdits := NewListMessageEditsUseCase(repository.NewMessageRepository(db), newAuthorizeRoomUseCase(db))
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 5, 1, nil)
+	mock.ExpectQuery("SELECT (.+) FROM message_edit").WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "message_id", "body", "edited_by", "created"}).AddRow(1, 42, "helo", 1, time.Now()))
+
+	output, err := ucListEdits.Execute(context.Background(), ListMessageEditsInput{UserID: 1, RoomID: 5, MessageID: 42})
+	assert.Nil(t, err)
+	assert.Len(t, output.Edits, 1)
+	assert.Equal(t, "helo", output.Edits[0].Body)
+}
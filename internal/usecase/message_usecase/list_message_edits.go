@@ -0,0 +1,63 @@
+package message_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type ListMessageEditsInput struct {
+	UserID    int32
+	RoomID    int32
+	MessageID int64
+}
+
+type ListMessageEditsOutput struct {
+	Edits []*domain.MessageEdit
+}
+
+type ListMessageEditsUseCaseInterface interface {
+	Execute(ctx context.Context, input ListMessageEditsInput) (*ListMessageEditsOutput, error)
+}
+
+type ListMessageEditsUseCase struct {
+	MessageRepository    domain.MessageRepositoryInterface
+	AuthorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface
+}
+
+func NewListMessageEditsUseCase(messageRepository domain.MessageRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface) *ListMessageEditsUseCase {
+	return &ListMessageEditsUseCase{
+		MessageRepository:    messageRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+// Execute returns the previous versions of a message, oldest first. The
+// history of a deleted message is gone with it.
+func (uc *ListMessageEditsUseCase) Execute(ctx context.Context, input ListMessageEditsInput) (*ListMessageEditsOutput, error) {
+	_, err := uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionView,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := fetchRoomMessage(ctx, uc.MessageRepository, input.RoomID, input.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.IsDeleted() {
+		return nil, messageNotFoundError()
+	}
+
+	edits, err := uc.MessageRepository.ListEdits(ctx, message.ID)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to list message edits", err)
+	}
+
+	return &ListMessageEditsOutput{Edits: edits}, nil
+}
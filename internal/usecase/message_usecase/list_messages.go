@@ -0,0 +1,131 @@
+package message_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+type ListMessagesInput struct {
+	UserID int32
+	RoomID int32
+	Before string
+	After  string
+	Limit  int
+}
+
+// ListMessagesOutput carries the page oldest first. Before points at older
+// history and is empty once the start of the room is reached; After points
+// past the newest message returned so clients can poll for new ones.
+type ListMessagesOutput struct {
+	Messages []*domain.Message
+	Before   string
+	After    string
+}
+
+type ListMessagesUseCaseInterface interface {
+	Execute(ctx context.Context, input ListMessagesInput) (*ListMessagesOutput, error)
+}
+
+type ListMessagesUseCase struct {
+	MessageRepository    domain.MessageRepositoryInterface
+	AuthorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface
+}
+
+func NewListMessagesUseCase(messageRepository domain.MessageRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface) *ListMessagesUseCase {
+	return &ListMessagesUseCase{
+		MessageRepository:    messageRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+func (uc *ListMessagesUseCase) Execute(ctx context.Context, input ListMessagesInput) (*ListMessagesOutput, error) {
+	page, err := buildMessagePage(input)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionView,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limit := page.Limit
+	// One extra row tells whether there is anything past this page.
+	page.Limit++
+
+	messages, err := uc.MessageRepository.ListMessages(ctx, page)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to list messages", err)
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		if page.AfterID > 0 {
+			messages = messages[:limit]
+		} else {
+			messages = messages[1:]
+		}
+	}
+
+	// Deleted messages stay in the page as tombstones so clients can drop
+	// them from view, but their content is never served again.
+	for _, message := range messages {
+		if message.IsDeleted() {
+			message.Body = ""
+		}
+	}
+
+	output := &ListMessagesOutput{Messages: messages}
+
+	if len(messages) == 0 {
+		if page.AfterID > 0 {
+			output.After = input.After
+		}
+		return output, nil
+	}
+
+	if hasMore || page.AfterID > 0 {
+		output.Before = encodeCursor(messages[0].ID)
+	}
+	output.After = encodeCursor(messages[len(messages)-1].ID)
+
+	return output, nil
+}
+
+func buildMessagePage(input ListMessagesInput) (domain.MessagePage, error) {
+	page := domain.MessagePage{RoomID: input.RoomID, Limit: input.Limit}
+
+	if input.Before != "" && input.After != "" {
+		return page, domain.NewError(domain.ErrBadRequest, "before and after cannot be used together")
+	}
+
+	if page.Limit == 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit < 0 || page.Limit > MaxPageLimit {
+		return page, domain.NewError(domain.ErrBadRequest, "limit must be between 1 and 100")
+	}
+
+	var err error
+	if input.Before != "" {
+		page.BeforeID, err = decodeCursor(input.Before)
+	} else if input.After != "" {
+		page.AfterID, err = decodeCursor(input.After)
+	}
+	if err != nil {
+		return page, domain.NewError(domain.ErrBadRequest, err.Error())
+	}
+
+	return page, nil
+}
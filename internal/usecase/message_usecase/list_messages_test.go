@@ -0,0 +1,104 @@
+package message_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var messageColumns = []string{"id", "room_id", "sender_id", "sender_name", "body", "edited_at", "deleted_at", "created"}
+
+func newListMessagesUseCase(db *sql.DB) *ListMessagesUseCase {
+	return NewListMessagesUseCase(repository.NewMessageRepository(db), newAuthorizeRoomUseCase(db))
+}
+
+func messageRows(ids ...int64) *sqlmock.Rows {
+	rows := sqlmock.NewRows(messageColumns)
+	for _, id := range ids {
+		rows.AddRow(id, 5, 1, "Eduardo Lima", "hello", nil, nil, time.Now())
+	}
+	return rows
+}
+
+func Test_Cursor_Round_Trip(t *testing.T) {
+	messageID, err := decodeCursor(encodeCursor(1234))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1234), messageID)
+
+	for _, cursor := range []string{"not base64!", "MTIz", encodeCursor(0)} {
+		_, err := decodeCursor(cursor)
+		assert.ErrorIs(t, err, errInvalidCursor)
+	}
+}
+
+func Test_Latest_Page_Points_To_Older_History(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("SELECT (.+) FROM message").WithArgs(int32(5), 3).WillReturnRows(messageRows(12, 11, 10))
+
+	output, err := newListMessagesUseCase(db).Execute(context.Background(), ListMessagesInput{UserID: 1, RoomID: 5, Limit: 2})
+	assert.Nil(t, err)
+	assert.Len(t, output.Messages, 2)
+	assert.Equal(t, int64(11), output.Messages[0].ID)
+	assert.Equal(t, int64(12), output.Messages[1].ID)
+	assert.Equal(t, encodeCursor(11), output.Before)
+	assert.Equal(t, encodeCursor(12), output.After)
+}
+
+func Test_Last_Page_Has_No_Before_Cursor(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("SELECT (.+) FROM message (.+) m.id < \\$2").WithArgs(int32(5), int64(11), 3).WillReturnRows(messageRows(10))
+
+	output, err := newListMessagesUseCase(db).Execute(context.Background(), ListMessagesInput{UserID: 1, RoomID: 5, Before: encodeCursor(11), Limit: 2})
+	assert.Nil(t, err)
+	assert.Len(t, output.Messages, 1)
+	assert.Empty(t, output.Before)
+	assert.Equal(t, encodeCursor(10), output.After)
+}
+
+func Test_Newer_Messages_Are_Listed_After_Cursor(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("SELECT (.+) FROM message (.+) m.id > \\$2").WithArgs(int32(5), int64(12), 3).WillReturnRows(sqlmock.NewRows(messageColumns))
+
+	after := encodeCursor(12)
+	output, err := newListMessagesUseCase(db).Execute(context.Background(), ListMessagesInput{UserID: 1, RoomID: 5, After: after, Limit: 2})
+	assert.Nil(t, err)
+	assert.Empty(t, output.Messages)
+	assert.Equal(t, after, output.After)
+}
+
+func Test_Invalid_Page_Is_Rejected(t *testing.T) {
+	ucList := newListMessagesUseCase(nil)
+
+	testsCases := []ListMessagesInput{
+		{UserID: 1, RoomID: 5, Before: encodeCursor(1), After: encodeCursor(2)},
+		{UserID: 1, RoomID: 5, Limit: MaxPageLimit + 1},
+		{UserID: 1, RoomID: 5, Before: "garbage"},
+	}
+
+	for _, input := range testsCases {
+		_, err := ucList.Execute(context.Background(), input)
+		assert.ErrorIs(t, err, domain.ErrBadRequest)
+	}
+}
+
+func Test_Private_Room_History_Is_Hidden_From_Non_Members(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+
+	_, err := newListMessagesUseCase(db).Execute(context.Background(), ListMessagesInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,53 @@
+package message_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type ListReceiptsInput struct {
+	UserID int32
+	RoomID int32
+}
+
+type ListReceiptsOutput struct {
+	Receipts []*domain.ReadReceipt
+}
+
+type ListReceiptsUseCaseInterface interface {
+	Execute(ctx context.Context, input ListReceiptsInput) (*ListReceiptsOutput, error)
+}
+
+type ListReceiptsUseCase struct {
+	ReadReceiptRepository domain.ReadReceiptRepositoryInterface
+	AuthorizeRoomUseCase  room_usecase.AuthorizeRoomUseCaseInterface
+}
+
+func NewListReceiptsUseCase(readReceiptRepository domain.ReadReceiptRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface) *ListReceiptsUseCase {
+	return &ListReceiptsUseCase{
+		ReadReceiptRepository: readReceiptRepository,
+		AuthorizeRoomUseCase:  authorizeRoomUseCase,
+	}
+}
+
+// Execute returns how far every participant has read, so a client joining
+// late can render the receipts it missed.
+func (uc *ListReceiptsUseCase) Execute(ctx context.Context, input ListReceiptsInput) (*ListReceiptsOutput, error) {
+	_, err := uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionView,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := uc.ReadReceiptRepository.ListReceipts(ctx, input.RoomID)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to list read receipts", err)
+	}
+
+	return &ListReceiptsOutput{Receipts: receipts}, nil
+}
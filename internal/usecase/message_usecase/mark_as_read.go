@@ -0,0 +1,92 @@
+package message_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type MarkAsReadInput struct {
+	UserID    int32
+	RoomID    int32
+	MessageID int64
+}
+
+type MarkAsReadOutput struct {
+	Receipt  *domain.ReadReceipt
+	Advanced bool
+}
+
+// ReceiptPayload is the body of the receipt events pushed to the other
+// participants of a room.
+type ReceiptPayload struct {
+	RoomID    int32     `json:"roomId"`
+	UserID    int32     `json:"userId"`
+	MessageID int64     `json:"messageId"`
+	ReadAt    time.Time `json:"readAt"`
+}
+
+type MarkAsReadUseCaseInterface interface {
+	Execute(ctx context.Context, input MarkAsReadInput) (*MarkAsReadOutput, error)
+}
+
+type MarkAsReadUseCase struct {
+	MessageRepository     domain.MessageRepositoryInterface
+	ReadReceiptRepository domain.ReadReceiptRepositoryInterface
+	RoomMemberRepository  domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase  room_usecase.AuthorizeRoomUseCaseInterface
+	EventPublisher        domain.EventPublisherInterface
+}
+
+func NewMarkAsReadUseCase(messageRepository domain.MessageRepositoryInterface, readReceiptRepository domain.ReadReceiptRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface, eventPublisher domain.EventPublisherInterface) *MarkAsReadUseCase {
+	return &MarkAsReadUseCase{
+		MessageRepository:     messageRepository,
+		ReadReceiptRepository: readReceiptRepository,
+		RoomMemberRepository:  roomMemberRepository,
+		AuthorizeRoomUseCase:  authorizeRoomUseCase,
+		EventPublisher:        eventPublisher,
+	}
+}
+
+// Execute moves the reader's receipt forward to the given message. Receipts
+// never move backwards, so reading an older message again is a no-op and
+// nobody is notified.
+func (uc *MarkAsReadUseCase) Execute(ctx context.Context, input MarkAsReadInput) (*MarkAsReadOutput, error) {
+	_, err := uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionParticipate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := fetchRoomMessage(ctx, uc.MessageRepository, input.RoomID, input.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := domain.NewReadReceipt(input.RoomID, input.UserID, message.ID)
+
+	advanced, err := uc.ReadReceiptRepository.Advance(ctx, receipt)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to mark message as read", err)
+	}
+
+	if advanced {
+		publishToRoom(ctx, uc.RoomMemberRepository, uc.EventPublisher, input.RoomID, domain.ReceiptUpdatedEvent, ToReceiptPayload(receipt), input.UserID)
+	}
+
+	return &MarkAsReadOutput{Receipt: receipt, Advanced: advanced}, nil
+}
+
+func ToReceiptPayload(receipt *domain.ReadReceipt) ReceiptPayload {
+	return ReceiptPayload{
+		RoomID:    receipt.RoomID,
+		UserID:    receipt.UserID,
+		MessageID: receipt.MessageID,
+		ReadAt:    receipt.Updated,
+	}
+}
@@ -0,0 +1,84 @@
+package message_usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMarkAsReadUseCase(db *sql.DB, publisher domain.EventPublisherInterface) *MarkAsReadUseCase {
+	return NewMarkAsReadUseCase(repository.NewMessageRepository(db), repository.NewReadReceiptRepository(db), repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db), publisher)
+}
+
+func Test_Read_Receipt_Is_Advanced_And_Published_To_Others(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 5, 2, nil)
+	mock.ExpectExec("INSERT INTO read_receipt").WithArgs(int32(5), int32(1), int64(42), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(sqlmock.NewRows(roomMemberColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()).
+		AddRow(5, 2, "joaquim2019", "Joaquim", "member", "active", nil, time.Now()))
+
+	output, err := newMarkAsReadUseCase(db, publisher).Execute(context.Background(), MarkAsReadInput{UserID: 1, RoomID: 5, MessageID: 42})
+	assert.Nil(t, err)
+	assert.True(t, output.Advanced)
+	assert.Equal(t, int64(42), output.Receipt.MessageID)
+
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, []int32{2}, publisher.events[0].userIDs)
+	assert.Equal(t, domain.ReceiptUpdatedEvent, publisher.events[0].event.Type)
+	var payload ReceiptPayload
+	assert.Nil(t, json.Unmarshal(publisher.events[0].event.Payload, &payload))
+	assert.Equal(t, int32(1), payload.UserID)
+	assert.Equal(t, int64(42), payload.MessageID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Older_Read_Receipt_Is_Not_Published(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 5, 2, nil)
+	mock.ExpectExec("INSERT INTO read_receipt").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	output, err := newMarkAsReadUseCase(db, publisher).Execute(context.Background(), MarkAsReadInput{UserID: 1, RoomID: 5, MessageID: 42})
+	assert.Nil(t, err)
+	assert.False(t, output.Advanced)
+	assert.Empty(t, publisher.events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Message_Of_Another_Room_Cannot_Be_Read(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	expectMessage(mock, 9, 2, nil)
+
+	_, err := newMarkAsReadUseCase(db, &recordingPublisher{}).Execute(context.Background(), MarkAsReadInput{UserID: 1, RoomID: 5, MessageID: 42})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_Read_Receipts_Are_Listed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("SELECT (.+) FROM read_receipt WHERE room_id").WithArgs(int32(5)).WillReturnRows(sqlmock.NewRows([]string{"room_id", "user_id", "message_id", "updated"}).
+		AddRow(5, 2, 42, time.Now()).
+		AddRow(5, 1, 40, time.Now()))
+
+	output, err := NewListReceiptsUseCase(repository.NewReadReceiptRepository(db), newAuthorizeRoomUseCase(db)).Execute(context.Background(), ListReceiptsInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.Len(t, output.Receipts, 2)
+	assert.Equal(t, int64(42), output.Receipts[0].MessageID)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,30 @@
+package message_usecase
+
+import (
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type MessageBaseUseCase struct {
+	SendMessageUseCase      SendMessageUseCaseInterface
+	ListMessagesUseCase     ListMessagesUseCaseInterface
+	EditMessageUseCase      EditMessageUseCaseInterface
+	DeleteMessageUseCase    DeleteMessageUseCaseInterface
+	ListMessageEditsUseCase ListMessageEditsUseCaseInterface
+	MarkAsReadUseCase       MarkAsReadUseCaseInterface
+	ListReceiptsUseCase     ListReceiptsUseCaseInterface
+	SendTypingUseCase       SendTypingUseCaseInterface
+}
+
+func NewMessageBaseUseCase(messageRepository domain.MessageRepositoryInterface, readReceiptRepository domain.ReadReceiptRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface, eventPublisher domain.EventPublisherInterface) *MessageBaseUseCase {
+	return &MessageBaseUseCase{
+		SendMessageUseCase:      NewSendMessageUseCase(messageRepository, roomMemberRepository, authorizeRoomUseCase, eventPublisher),
+		ListMessagesUseCase:     NewListMessagesUseCase(messageRepository, authorizeRoomUseCase),
+		EditMessageUseCase:      NewEditMessageUseCase(messageRepository, roomMemberRepository, authorizeRoomUseCase, eventPublisher),
+		DeleteMessageUseCase:    NewDeleteMessageUseCase(messageRepository, roomMemberRepository, authorizeRoomUseCase, eventPublisher),
+		ListMessageEditsUseCase: NewListMessageEditsUseCase(messageRepository, authorizeRoomUseCase),
+		MarkAsReadUseCase:       NewMarkAsReadUseCase(messageRepository, readReceiptRepository, roomMemberRepository, authorizeRoomUseCase, eventPublisher),
+		ListReceiptsUseCase:     NewListReceiptsUseCase(readReceiptRepository, authorizeRoomUseCase),
+		SendTypingUseCase:       NewSendTypingUseCase(roomMemberRepository, authorizeRoomUseCase, eventPublisher),
+	}
+}
@@ -0,0 +1,127 @@
+package message_usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type SendMessageInput struct {
+	UserID int32
+	RoomID int32
+	Body   string
+}
+
+type SendMessageOutput struct {
+	Message *domain.Message
+}
+
+// MessagePayload is the body of the message events pushed to room members. A
+// deleted message is sent as a tombstone, without its body.
+type MessagePayload struct {
+	ID         int64      `json:"id"`
+	RoomID     int32      `json:"roomId"`
+	SenderID   int32      `json:"senderId"`
+	SenderName string     `json:"senderName"`
+	Body       string     `json:"body"`
+	EditedAt   *time.Time `json:"editedAt,omitempty"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty"`
+	Created    time.Time  `json:"created"`
+}
+
+type SendMessageUseCaseInterface interface {
+	Execute(ctx context.Context, input SendMessageInput) (*SendMessageOutput, error)
+}
+
+type SendMessageUseCase struct {
+	MessageRepository    domain.MessageRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface
+	EventPublisher       domain.EventPublisherInterface
+}
+
+func NewSendMessageUseCase(messageRepository domain.MessageRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface, eventPublisher domain.EventPublisherInterface) *SendMessageUseCase {
+	return &SendMessageUseCase{
+		MessageRepository:    messageRepository,
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+		EventPublisher:       eventPublisher,
+	}
+}
+
+func (uc *SendMessageUseCase) Execute(ctx context.Context, input SendMessageInput) (*SendMessageOutput, error) {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionParticipate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if access.Room.IsArchived() {
+		return nil, domain.NewError(domain.ErrBadRequest, "room is archived")
+	}
+
+	message, err := domain.NewMessage(input.RoomID, input.UserID, input.Body)
+	if err != nil {
+		return nil, domain.NewError(domain.ErrBadRequest, err.Error())
+	}
+
+	message.SenderName = access.Member.DisplayName
+	if message.SenderName == "" {
+		message.SenderName = access.Member.UserName
+	}
+
+	message.ID, err = uc.MessageRepository.Save(ctx, message)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save message", err)
+	}
+
+	publishMessage(ctx, uc.RoomMemberRepository, uc.EventPublisher, domain.MessageNewEvent, message)
+
+	return &SendMessageOutput{Message: message}, nil
+}
+
+func publishMessage(ctx context.Context, roomMemberRepository domain.RoomMemberRepositoryInterface, eventPublisher domain.EventPublisherInterface, eventType string, message *domain.Message) {
+	publishToRoom(ctx, roomMemberRepository, eventPublisher, message.RoomID, eventType, ToMessagePayload(message))
+}
+
+// publishToRoom is best effort: the change is already stored and members who
+// miss the event will find it through the REST API.
+func publishToRoom(ctx context.Context, roomMemberRepository domain.RoomMemberRepositoryInterface, eventPublisher domain.EventPublisherInterface, roomID int32, eventType string, payload any, excludedUserIDs ...int32) {
+	members, err := roomMemberRepository.ListMembers(ctx, roomID)
+	if err != nil {
+		return
+	}
+
+	recipients := make([]int32, 0, len(members))
+	for _, member := range members {
+		if member.IsActive() && !slices.Contains(excludedUserIDs, member.UserID) {
+			recipients = append(recipients, member.UserID)
+		}
+	}
+
+	event, err := domain.NewEvent(eventType, payload)
+	if err != nil {
+		return
+	}
+
+	eventPublisher.SendToUsers(recipients, event)
+}
+
+func ToMessagePayload(message *domain.Message) MessagePayload {
+	return MessagePayload{
+		ID:         message.ID,
+		RoomID:     message.RoomID,
+		SenderID:   message.SenderID,
+		SenderName: message.SenderName,
+		Body:       message.Body,
+		EditedAt:   message.EditedAt,
+		DeletedAt:  message.DeletedAt,
+		Created:    message.Created,
+	}
+}
@@ -0,0 +1,112 @@
+package message_usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+var roomColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created"}
+var roomMemberColumns = []string{"room_id", "user_id", "username", "displayname", "role", "status", "invited_by", "created"}
+
+type publishedEvent struct {
+	userIDs []int32
+	event   domain.Event
+}
+
+type recordingPublisher struct {
+	events []publishedEvent
+}
+
+func (p *recordingPublisher) SendToUsers(userIDs []int32, event domain.Event) {
+	p.events = append(p.events, publishedEvent{userIDs: userIDs, event: event})
+}
+
+func newAuthorizeRoomUseCase(db *sql.DB) *room_usecase.AuthorizeRoomUseCase {
+	return room_usecase.NewAuthorizeRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db))
+}
+
+func newSendMessageUseCase(db *sql.DB, publisher domain.EventPublisherInterface) *SendMessageUseCase {
+	return NewSendMessageUseCase(repository.NewMessageRepository(db), repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db), publisher)
+}
+
+func expectRoomAccess(mock sqlmock.Sqlmock, archivedAt any, status string) {
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, archivedAt, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.user_id = \\$2").
+		WillReturnRows(sqlmock.NewRows(roomMemberColumns).AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", status, nil, time.Now()))
+}
+
+func Test_Message_Is_Saved_And_Published_To_Active_Members(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("INSERT INTO message").WithArgs(int32(5), int32(1), "hello", sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(sqlmock.NewRows(roomMemberColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()).
+		AddRow(5, 2, "joaquim2019", "", "owner", "active", nil, time.Now()).
+		AddRow(5, 3, "maria", "", "member", "invited", 2, time.Now()))
+
+	output, err := newSendMessageUseCase(db, publisher).Execute(context.Background(), SendMessageInput{UserID: 1, RoomID: 5, Body: " hello "})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), output.Message.ID)
+	assert.Equal(t, "Eduardo Lima", output.Message.SenderName)
+
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, []int32{1, 2}, publisher.events[0].userIDs)
+	assert.Equal(t, domain.MessageNewEvent, publisher.events[0].event.Type)
+
+	var payload MessagePayload
+	assert.Nil(t, json.Unmarshal(publisher.events[0].event.Payload, &payload))
+	assert.Equal(t, int64(42), payload.ID)
+	assert.Equal(t, "hello", payload.Body)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Invited_User_Cannot_Send_Message(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "invited")
+
+	_, err := newSendMessageUseCase(db, &recordingPublisher{}).Execute(context.Background(), SendMessageInput{UserID: 1, RoomID: 5, Body: "hello"})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_Message_Cannot_Be_Sent_To_Archived_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, time.Now(), "active")
+
+	_, err := newSendMessageUseCase(db, &recordingPublisher{}).Execute(context.Background(), SendMessageInput{UserID: 1, RoomID: 5, Body: "hello"})
+	assert.EqualError(t, err, domain.NewError(domain.ErrBadRequest, "room is archived").Error())
+}
+
+func Test_Empty_Message_Is_Rejected(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	expectRoomAccess(mock, nil, "active")
+
+	_, err := newSendMessageUseCase(db, &recordingPublisher{}).Execute(context.Background(), SendMessageInput{UserID: 1, RoomID: 5, Body: "   "})
+	assert.EqualError(t, err, domain.NewError(domain.ErrBadRequest, "message body is required").Error())
+}
+
+func Test_If_Get_Error_When_Message_Is_Not_Saved(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("INSERT INTO message").WillReturnError(errors.New("an internal error"))
+
+	_, err := newSendMessageUseCase(db, publisher).Execute(context.Background(), SendMessageInput{UserID: 1, RoomID: 5, Body: "hello"})
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	assert.Empty(t, publisher.events)
+}
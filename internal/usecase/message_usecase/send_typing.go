@@ -0,0 +1,63 @@
+package message_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/usecase/room_usecase"
+)
+
+type SendTypingInput struct {
+	UserID int32
+	RoomID int32
+	Typing bool
+}
+
+type TypingPayload struct {
+	RoomID int32 `json:"roomId"`
+	UserID int32 `json:"userId"`
+}
+
+type SendTypingUseCaseInterface interface {
+	Execute(ctx context.Context, input SendTypingInput) error
+}
+
+type SendTypingUseCase struct {
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface
+	EventPublisher       domain.EventPublisherInterface
+}
+
+func NewSendTypingUseCase(roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase room_usecase.AuthorizeRoomUseCaseInterface, eventPublisher domain.EventPublisherInterface) *SendTypingUseCase {
+	return &SendTypingUseCase{
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+		EventPublisher:       eventPublisher,
+	}
+}
+
+// Execute relays a typing indicator to the other members of the room. Nothing
+// is stored: clients are expected to drop an indicator that is not refreshed.
+func (uc *SendTypingUseCase) Execute(ctx context.Context, input SendTypingInput) error {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, room_usecase.AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: room_usecase.PermissionParticipate,
+	})
+	if err != nil {
+		return err
+	}
+
+	if access.Room.IsArchived() {
+		return domain.NewError(domain.ErrBadRequest, "room is archived")
+	}
+
+	eventType := domain.TypingStoppedEvent
+	if input.Typing {
+		eventType = domain.TypingStartedEvent
+	}
+
+	publishToRoom(ctx, uc.RoomMemberRepository, uc.EventPublisher, input.RoomID, eventType, TypingPayload{RoomID: input.RoomID, UserID: input.UserID}, input.UserID)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package message_usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Typing_Is_Relayed_To_The_Other_Members(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccess(mock, nil, "active")
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(sqlmock.NewRows(roomMemberColumns).
+		AddRow(5, 1, "eduardolima806", "Eduardo Lima", "member", "active", nil, time.Now()).
+		AddRow(5, 2, "joaquim2019", "Joaquim", "member", "active", nil, time.Now()))
+
+	err := NewSendTypingUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db), publisher).Execute(context.Background(), SendTypingInput{UserID: 1, RoomID: 5, Typing: true})
+	assert.Nil(t, err)
+
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, domain.TypingStartedEvent, publisher.events[0].event.Type)
+	assert.Equal(t, []int32{2}, publisher.events[0].userIDs)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Typing_In_An_Archived_Room_Is_Rejected(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+
+	expectRoomAccess(mock, time.Now(), "active")
+
+	err := NewSendTypingUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db), publisher).Execute(context.Background(), SendTypingInput{UserID: 1, RoomID: 5, Typing: false})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+	assert.Empty(t, publisher.events)
+}
@@ -0,0 +1,67 @@
+package presence_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ListPresenceInput struct {
+	UserID int32
+}
+
+type ListPresenceOutput struct {
+	Presences []*domain.Presence
+}
+
+type ListPresenceUseCaseInterface interface {
+	Execute(ctx context.Context, input ListPresenceInput) (*ListPresenceOutput, error)
+}
+
+type ListPresenceUseCase struct {
+	ConversationRepository domain.ConversationRepositoryInterface
+	PresenceRepository     domain.PresenceRepositoryInterface
+	PresenceTracker        domain.PresenceTrackerInterface
+}
+
+func NewListPresenceUseCase(conversationRepository domain.ConversationRepositoryInterface, presenceRepository domain.PresenceRepositoryInterface, presenceTracker domain.PresenceTrackerInterface) *ListPresenceUseCase {
+	return &ListPresenceUseCase{
+		ConversationRepository: conversationRepository,
+		PresenceRepository:     presenceRepository,
+		PresenceTracker:        presenceTracker,
+	}
+}
+
+// Execute returns the presence of everyone the user shares a conversation
+// with. Live status comes from the tracker; the last seen of users it no
+// longer remembers comes from the database.
+func (uc *ListPresenceUseCase) Execute(ctx context.Context, input ListPresenceInput) (*ListPresenceOutput, error) {
+	contactIDs, err := uc.ConversationRepository.ListContactIDs(ctx, input.UserID)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to list contacts", err)
+	}
+
+	presences := uc.PresenceTracker.Presence(contactIDs)
+
+	unknown := make([]int32, 0)
+	for _, presence := range presences {
+		if presence.LastSeen == nil {
+			unknown = append(unknown, presence.UserID)
+		}
+	}
+
+	if len(unknown) > 0 {
+		lastSeen, err := uc.PresenceRepository.ListLastSeen(ctx, unknown)
+		if err != nil {
+			return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch last seen", err)
+		}
+
+		for _, presence := range presences {
+			if seen, ok := lastSeen[presence.UserID]; ok {
+				presence.LastSeen = &seen
+			}
+		}
+	}
+
+	return &ListPresenceOutput{Presences: presences}, nil
+}
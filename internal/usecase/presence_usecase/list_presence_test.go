@@ -0,0 +1,60 @@
+package presence_usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedTracker struct {
+	online map[int32]time.Time
+}
+
+func (f *fixedTracker) Presence(userIDs []int32) []*domain.Presence {
+	presences := make([]*domain.Presence, 0, len(userIDs))
+	for _, userID := range userIDs {
+		presence := &domain.Presence{UserID: userID, Status: domain.PresenceOffline}
+		if seen, ok := f.online[userID]; ok {
+			presence.Status = domain.PresenceOnline
+			presence.LastSeen = &seen
+		}
+		presences = append(presences, presence)
+	}
+	return presences
+}
+
+func Test_Presence_Of_Contacts_Is_Listed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	lastSeen := time.Now().Add(-time.Hour)
+	tracker := &fixedTracker{online: map[int32]time.Time{2: time.Now()}}
+
+	mock.ExpectQuery("SELECT DISTINCT other.user_id FROM room_member").WithArgs(int32(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3).AddRow(4))
+	mock.ExpectQuery("SELECT id, last_seen FROM app_user").WillReturnRows(sqlmock.NewRows([]string{"id", "last_seen"}).AddRow(3, lastSeen))
+
+	output, err := NewListPresenceUseCase(repository.NewConversationRepository(db), repository.NewPresenceRepository(db), tracker).Execute(context.Background(), ListPresenceInput{UserID: 1})
+	assert.Nil(t, err)
+	assert.Len(t, output.Presences, 3)
+
+	assert.Equal(t, domain.PresenceOnline, output.Presences[0].Status)
+	assert.Equal(t, domain.PresenceOffline, output.Presences[1].Status)
+	assert.Equal(t, lastSeen.Unix(), output.Presences[1].LastSeen.Unix())
+	assert.Nil(t, output.Presences[2].LastSeen)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Presence_Without_Contacts_Skips_Last_Seen(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT DISTINCT other.user_id FROM room_member").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	output, err := NewListPresenceUseCase(repository.NewConversationRepository(db), repository.NewPresenceRepository(db), &fixedTracker{}).Execute(context.Background(), ListPresenceInput{UserID: 1})
+	assert.Nil(t, err)
+	assert.Empty(t, output.Presences)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,15 @@
+package presence_usecase
+
+import (
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type PresenceBaseUseCase struct {
+	ListPresenceUseCase ListPresenceUseCaseInterface
+}
+
+func NewPresenceBaseUseCase(conversationRepository domain.ConversationRepositoryInterface, presenceRepository domain.PresenceRepositoryInterface, presenceTracker domain.PresenceTrackerInterface) *PresenceBaseUseCase {
+	return &PresenceBaseUseCase{
+		ListPresenceUseCase: NewListPresenceUseCase(conversationRepository, presenceRepository, presenceTracker),
+	}
+}
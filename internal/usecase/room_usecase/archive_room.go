@@ -0,0 +1,55 @@
+package room_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ArchiveRoomInput struct {
+	UserID int32
+	RoomID int32
+}
+
+type ArchiveRoomUseCaseInterface interface {
+	Execute(ctx context.Context, input ArchiveRoomInput) (*RoomOutput, error)
+}
+
+type ArchiveRoomUseCase struct {
+	RoomRepository       domain.RoomRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewArchiveRoomUseCase(roomRepository domain.RoomRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *ArchiveRoomUseCase {
+	return &ArchiveRoomUseCase{
+		RoomRepository:       roomRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+func (uc *ArchiveRoomUseCase) Execute(ctx context.Context, input ArchiveRoomInput) (*RoomOutput, error) {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: PermissionOwn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	room := access.Room
+
+	if room.IsArchived() {
+		return &RoomOutput{Room: room}, nil
+	}
+
+	if err = uc.RoomRepository.Archive(ctx, room.ID); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to archive room", err)
+	}
+
+	now := time.Now()
+	room.ArchivedAt = &now
+
+	return &RoomOutput{Room: room}, nil
+}
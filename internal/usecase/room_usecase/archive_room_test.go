@@ -0,0 +1,39 @@
+package room_usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Only_Owner_Can_Archive_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucArchive := NewArchiveRoomUseCase(repository.NewRoomRepository(db), newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleAdmin, domain.MembershipActive))
+
+	_, err := ucArchive.Execute(context.Background(), ArchiveRoomInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_Room_Is_Archived_By_Owner(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucArchive := NewArchiveRoomUseCase(repository.NewRoomRepository(db), newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleOwner, domain.MembershipActive))
+	mock.ExpectExec("UPDATE room SET archived_at").WithArgs(int32(5), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	output, err := ucArchive.Execute(context.Background(), ArchiveRoomInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.True(t, output.Room.IsArchived())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,89 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type RoomPermission uint8
+
+const (
+	// PermissionView allows reading a public room, or a private one the user belongs or was invited to.
+	PermissionView RoomPermission = iota
+	// PermissionParticipate requires an active membership.
+	PermissionParticipate
+	// PermissionModerate requires an active owner or admin.
+	PermissionModerate
+	// PermissionOwn requires the room owner.
+	PermissionOwn
+)
+
+type AuthorizeRoomInput struct {
+	UserID     int32
+	RoomID     int32
+	Permission RoomPermission
+}
+
+type AuthorizeRoomOutput struct {
+	Room   *domain.Room
+	Member *domain.RoomMember
+}
+
+type AuthorizeRoomUseCaseInterface interface {
+	Execute(ctx context.Context, input AuthorizeRoomInput) (*AuthorizeRoomOutput, error)
+}
+
+type AuthorizeRoomUseCase struct {
+	RoomRepository       domain.RoomRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+}
+
+func NewAuthorizeRoomUseCase(roomRepository domain.RoomRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface) *AuthorizeRoomUseCase {
+	return &AuthorizeRoomUseCase{
+		RoomRepository:       roomRepository,
+		RoomMemberRepository: roomMemberRepository,
+	}
+}
+
+func (uc *AuthorizeRoomUseCase) Execute(ctx context.Context, input AuthorizeRoomInput) (*AuthorizeRoomOutput, error) {
+	room, err := fetchRoom(ctx, uc.RoomRepository, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := uc.RoomMemberRepository.GetMember(ctx, input.RoomID, input.UserID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch room member", err)
+		}
+		member = nil
+	}
+
+	if member != nil && member.IsBanned() {
+		return nil, domain.NewError(domain.ErrForbidden, "user is banned from this room")
+	}
+
+	// Private rooms are invisible to anyone who was never let in.
+	if member == nil && room.Visibility == domain.RoomPrivate {
+		return nil, roomNotFoundError()
+	}
+
+	switch input.Permission {
+	case PermissionParticipate:
+		if member == nil || !member.IsActive() {
+			return nil, domain.NewError(domain.ErrForbidden, "user is not a member of this room")
+		}
+	case PermissionModerate:
+		if member == nil || !member.CanModerate() {
+			return nil, domain.NewError(domain.ErrForbidden, "only the room owner or admins can do this")
+		}
+	case PermissionOwn:
+		if member == nil || !member.IsActive() || member.Role != domain.RoomRoleOwner {
+			return nil, domain.NewError(domain.ErrForbidden, "only the room owner can do this")
+		}
+	}
+
+	return &AuthorizeRoomOutput{Room: room, Member: member}, nil
+}
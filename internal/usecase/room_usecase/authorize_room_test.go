@@ -0,0 +1,56 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Room_Permissions_Are_Checked_Against_Membership(t *testing.T) {
+	testsCases := []struct {
+		name       string
+		visibility string
+		member     *sqlmock.Rows
+		permission RoomPermission
+		errorCode  error
+	}{
+		{"public room is viewable by non members", "public", nil, PermissionView, nil},
+		{"private room is hidden from non members", "private", nil, PermissionView, domain.ErrNotFound},
+		{"non member cannot participate", "public", nil, PermissionParticipate, domain.ErrForbidden},
+		{"invited user cannot participate", "private", memberRow(5, 1, domain.RoomRoleMember, domain.MembershipInvited), PermissionParticipate, domain.ErrForbidden},
+		{"active member can participate", "private", memberRow(5, 1, domain.RoomRoleMember, domain.MembershipActive), PermissionParticipate, nil},
+		{"member cannot moderate", "public", memberRow(5, 1, domain.RoomRoleMember, domain.MembershipActive), PermissionModerate, domain.ErrForbidden},
+		{"admin can moderate", "public", memberRow(5, 1, domain.RoomRoleAdmin, domain.MembershipActive), PermissionModerate, nil},
+		{"admin does not own", "public", memberRow(5, 1, domain.RoomRoleAdmin, domain.MembershipActive), PermissionOwn, domain.ErrForbidden},
+		{"owner owns", "public", memberRow(5, 1, domain.RoomRoleOwner, domain.MembershipActive), PermissionOwn, nil},
+		{"banned user has no access", "public", memberRow(5, 1, domain.RoomRoleMember, domain.MembershipBanned), PermissionView, domain.ErrForbidden},
+	}
+
+	for _, tc := range testsCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			ucAuthorize := newAuthorizeRoomUseCase(db)
+
+			mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", tc.visibility, "channel", 2, nil, time.Now()))
+			if tc.member == nil {
+				mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+			} else {
+				mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(tc.member)
+			}
+
+			output, err := ucAuthorize.Execute(context.Background(), AuthorizeRoomInput{UserID: 1, RoomID: 5, Permission: tc.permission})
+
+			if tc.errorCode == nil {
+				assert.Nil(t, err)
+				assert.Equal(t, int32(5), output.Room.ID)
+			} else {
+				assert.ErrorIs(t, err, tc.errorCode)
+			}
+		})
+	}
+}
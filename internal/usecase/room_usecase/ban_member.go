@@ -0,0 +1,62 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type BanMemberUseCaseInterface interface {
+	Execute(ctx context.Context, input ModerateMemberInput) (*MemberOutput, error)
+}
+
+type BanMemberUseCase struct {
+	UserRepository       domain.UserRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewBanMemberUseCase(userRepository domain.UserRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *BanMemberUseCase {
+	return &BanMemberUseCase{
+		UserRepository:       userRepository,
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+// Execute bans the user from the room, removing any membership or invite. Users
+// who never joined can be banned too, so they cannot join later.
+func (uc *BanMemberUseCase) Execute(ctx context.Context, input ModerateMemberInput) (*MemberOutput, error) {
+	moderation, err := authorizeModeration(ctx, uc.AuthorizeRoomUseCase, uc.RoomMemberRepository, input, PermissionModerate)
+	if err != nil {
+		return nil, err
+	}
+
+	member := moderation.target
+	if member == nil {
+		user, err := uc.UserRepository.GetUserById(ctx, input.TargetUserID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, domain.NewError(domain.ErrNotFound, "user does not exists")
+			}
+			return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+		}
+
+		member, err = domain.NewRoomMember(input.RoomID, user.ID, domain.RoomRoleMember, domain.MembershipBanned)
+		if err != nil {
+			return nil, domain.NewError(domain.ErrInternalServerError, err.Error())
+		}
+		member.UserName = user.UserName
+		member.DisplayName = user.DisplayName
+	}
+
+	member.Role = domain.RoomRoleMember
+	member.Status = domain.MembershipBanned
+
+	if err = uc.RoomMemberRepository.Save(ctx, member); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save room member", err)
+	}
+
+	return &MemberOutput{Member: member}, nil
+}
@@ -0,0 +1,55 @@
+package room_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ChangeMemberRoleInput struct {
+	ModerateMemberInput
+	Role string
+}
+
+type ChangeMemberRoleUseCaseInterface interface {
+	Execute(ctx context.Context, input ChangeMemberRoleInput) (*MemberOutput, error)
+}
+
+type ChangeMemberRoleUseCase struct {
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewChangeMemberRoleUseCase(roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *ChangeMemberRoleUseCase {
+	return &ChangeMemberRoleUseCase{
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+// Execute promotes a member to admin or demotes an admin. Only the owner may do
+// it, and ownership itself cannot be transferred this way.
+func (uc *ChangeMemberRoleUseCase) Execute(ctx context.Context, input ChangeMemberRoleInput) (*MemberOutput, error) {
+	role := domain.RoomRole(input.Role)
+	if role != domain.RoomRoleAdmin && role != domain.RoomRoleMember {
+		return nil, domain.NewError(domain.ErrBadRequest, "room role must be admin or member")
+	}
+
+	moderation, err := authorizeModeration(ctx, uc.AuthorizeRoomUseCase, uc.RoomMemberRepository, input.ModerateMemberInput, PermissionOwn)
+	if err != nil {
+		return nil, err
+	}
+
+	member := moderation.target
+	if member == nil || !member.IsActive() {
+		return nil, memberNotFoundError()
+	}
+
+	member.Role = role
+
+	if err = uc.RoomMemberRepository.Save(ctx, member); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save room member", err)
+	}
+
+	return &MemberOutput{Member: member}, nil
+}
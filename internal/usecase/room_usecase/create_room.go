@@ -0,0 +1,99 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type CreateRoomInput struct {
+	OwnerID    int32
+	Name       string
+	Topic      string
+	Visibility string
+}
+
+type RoomOutput struct {
+	Room *domain.Room
+}
+
+type CreateRoomUseCaseInterface interface {
+	Execute(ctx context.Context, input CreateRoomInput) (*RoomOutput, error)
+}
+
+type CreateRoomUseCase struct {
+	RoomRepository       domain.RoomRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	UnitOfWork           domain.UnitOfWorkInterface
+}
+
+const IdDummy = 0
+
+func NewCreateRoomUseCase(roomRepository domain.RoomRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, unitOfWork domain.UnitOfWorkInterface) *CreateRoomUseCase {
+	return &CreateRoomUseCase{
+		RoomRepository:       roomRepository,
+		RoomMemberRepository: roomMemberRepository,
+		UnitOfWork:           unitOfWork,
+	}
+}
+
+func (uc *CreateRoomUseCase) Execute(ctx context.Context, input CreateRoomInput) (*RoomOutput, error) {
+	visibility := domain.RoomVisibility(input.Visibility)
+	if visibility == "" {
+		visibility = domain.RoomPublic
+	}
+
+	room, err := domain.NewRoom(IdDummy, input.Name, input.Topic, visibility, input.OwnerID)
+	if err != nil {
+		return nil, domain.NewError(domain.ErrBadRequest, err.Error())
+	}
+
+	// A room left without its owner could never be moderated nor archived,
+	// so both are saved at once.
+	err = uc.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := checkIfRoomNameIsTaken(ctx, uc.RoomRepository, room.Name, IdDummy); err != nil {
+			return err
+		}
+
+		id, err := uc.RoomRepository.Save(ctx, room)
+		if err != nil {
+			return err
+		}
+		room.ID = id
+
+		owner, err := domain.NewRoomMember(room.ID, room.OwnerID, domain.RoomRoleOwner, domain.MembershipActive)
+		if err != nil {
+			return err
+		}
+		return uc.RoomMemberRepository.Save(ctx, owner)
+	})
+
+	if errors.Is(err, domain.ErrConflict) {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save room", err)
+	}
+
+	return &RoomOutput{Room: room}, nil
+}
+
+func checkIfRoomNameIsTaken(ctx context.Context, roomRepository domain.RoomRepositoryInterface, name string, roomID int32) error {
+	existing, err := roomRepository.GetRoomByName(ctx, name)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch room", err)
+	}
+
+	if existing.ID != roomID {
+		return domain.NewConflictError("name", "room name already exists")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var roomColumns = []string{"id", "name", "topic", "visibility", "kind", "owner_id", "archived_at", "created"}
+
+var roomMemberColumns = []string{"room_id", "user_id", "username", "displayname", "role", "status", "invited_by", "created"}
+
+func newAuthorizeRoomUseCase(db *sql.DB) *AuthorizeRoomUseCase {
+	return NewAuthorizeRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db))
+}
+
+func memberRow(roomID int32, userID int32, role domain.RoomRole, status domain.MembershipStatus) *sqlmock.Rows {
+	return sqlmock.NewRows(roomMemberColumns).AddRow(roomID, userID, "user", "", role, status, nil, time.Now())
+}
+
+func Test_If_Get_Error_To_Create_Invalid_Room(t *testing.T) {
+	ucCreate := NewCreateRoomUseCase(repository.NewRoomRepository(nil), repository.NewRoomMemberRepository(nil), repository.NewUnitOfWork(nil))
+
+	_, err := ucCreate.Execute(context.Background(), CreateRoomInput{OwnerID: 1, Name: "General Chat"})
+	expectedError := domain.NewError(domain.ErrBadRequest, "room name must have 2 to 50 lowercase letters, numbers, hyphens or underscores")
+	assert.EqualError(t, err, expectedError.Error())
+}
+
+func Test_If_Get_Conflict_When_Room_Name_Already_Exists(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucCreate := NewCreateRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db), repository.NewUnitOfWork(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now())
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WithArgs("general").WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	_, err := ucCreate.Execute(context.Background(), CreateRoomInput{OwnerID: 1, Name: "general"})
+	expectedError := domain.NewError(domain.ErrConflict, "room name already exists")
+	assert.EqualError(t, err, expectedError.Error())
+}
+
+func Test_If_Get_Conflict_When_Room_Name_Is_Taken_Concurrently(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucCreate := NewCreateRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db), repository.NewUnitOfWork(db))
+
+	// Another room took the name between the check and the insert.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WithArgs("general").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO room").WillReturnError(&pq.Error{Code: "23505", Constraint: "room_name_key"})
+	mock.ExpectRollback()
+
+	output, err := ucCreate.Execute(context.Background(), CreateRoomInput{OwnerID: 1, Name: "general"})
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Error_When_Room_Is_Not_Saved(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucCreate := NewCreateRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db), repository.NewUnitOfWork(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO room").WillReturnError(errors.New("an internal error"))
+	mock.ExpectRollback()
+
+	output, err := ucCreate.Execute(context.Background(), CreateRoomInput{OwnerID: 1, Name: "general"})
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Room_Is_Not_Created_When_Its_Owner_Is_Not_Saved(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucCreate := NewCreateRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db), repository.NewUnitOfWork(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO room").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectExec("INSERT INTO room_member").WillReturnError(errors.New("an internal error"))
+	mock.ExpectRollback()
+
+	output, err := ucCreate.Execute(context.Background(), CreateRoomInput{OwnerID: 1, Name: "general"})
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Room_Is_Created_Public_By_Default(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucCreate := NewCreateRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db), repository.NewUnitOfWork(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO room").WithArgs("general", "Anything goes", domain.RoomPublic, domain.RoomKindChannel, int32(1), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(5), int32(1), domain.RoomRoleOwner, domain.MembershipActive, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	output, err := ucCreate.Execute(context.Background(), CreateRoomInput{OwnerID: 1, Name: "general", Topic: "Anything goes"})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(5), output.Room.ID)
+	assert.Equal(t, int32(1), output.Room.OwnerID)
+	assert.Equal(t, domain.RoomPublic, output.Room.Visibility)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
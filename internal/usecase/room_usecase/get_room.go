@@ -0,0 +1,57 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type GetRoomInput struct {
+	UserID int32
+	RoomID int32
+}
+
+type GetRoomUseCaseInterface interface {
+	Execute(ctx context.Context, input GetRoomInput) (*RoomOutput, error)
+}
+
+type GetRoomUseCase struct {
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewGetRoomUseCase(authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *GetRoomUseCase {
+	return &GetRoomUseCase{
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+func (uc *GetRoomUseCase) Execute(ctx context.Context, input GetRoomInput) (*RoomOutput, error) {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: PermissionView,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RoomOutput{Room: access.Room}, nil
+}
+
+func fetchRoom(ctx context.Context, roomRepository domain.RoomRepositoryInterface, roomID int32) (*domain.Room, error) {
+	room, err := roomRepository.GetRoomById(ctx, roomID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, roomNotFoundError()
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch room", err)
+	}
+
+	return room, nil
+}
+
+func roomNotFoundError() error {
+	return domain.NewError(domain.ErrNotFound, "room does not exists")
+}
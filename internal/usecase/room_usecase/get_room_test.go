@@ -0,0 +1,72 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Get_Not_Found_When_Room_Does_Not_Exists(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucGet := NewGetRoomUseCase(newAuthorizeRoomUseCase(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnError(sql.ErrNoRows)
+
+	_, err := ucGet.Execute(context.Background(), GetRoomInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_If_Private_Room_Is_Hidden_From_Other_Users(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucGet := NewGetRoomUseCase(newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WithArgs(int32(5)).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WithArgs(int32(5), int32(1)).WillReturnError(sql.ErrNoRows)
+
+	_, err := ucGet.Execute(context.Background(), GetRoomInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_If_Public_Room_Is_Fetched(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucGet := NewGetRoomUseCase(newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WithArgs(int32(5)).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WithArgs(int32(5), int32(1)).WillReturnError(sql.ErrNoRows)
+
+	output, err := ucGet.Execute(context.Background(), GetRoomInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, "general", output.Room.Name)
+}
+
+func Test_If_Private_Room_Is_Visible_To_Invited_User(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucGet := NewGetRoomUseCase(newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WithArgs(int32(5)).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipInvited))
+
+	output, err := ucGet.Execute(context.Background(), GetRoomInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", output.Room.Name)
+}
+
+func Test_If_Banned_User_Cannot_See_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucGet := NewGetRoomUseCase(newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WithArgs(int32(5)).WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipBanned))
+
+	_, err := ucGet.Execute(context.Background(), GetRoomInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
@@ -0,0 +1,89 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type InviteMemberInput struct {
+	UserID int32
+	RoomID int32
+	Login  string
+}
+
+type InviteMemberUseCaseInterface interface {
+	Execute(ctx context.Context, input InviteMemberInput) (*MemberOutput, error)
+}
+
+type InviteMemberUseCase struct {
+	UserRepository       domain.UserRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewInviteMemberUseCase(userRepository domain.UserRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *InviteMemberUseCase {
+	return &InviteMemberUseCase{
+		UserRepository:       userRepository,
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+func (uc *InviteMemberUseCase) Execute(ctx context.Context, input InviteMemberInput) (*MemberOutput, error) {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: PermissionParticipate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if access.Room.Visibility == domain.RoomPrivate && !access.Member.CanModerate() {
+		return nil, domain.NewError(domain.ErrForbidden, "only the room owner or admins can invite to a private room")
+	}
+
+	if access.Room.IsArchived() {
+		return nil, domain.NewError(domain.ErrBadRequest, "room is archived")
+	}
+
+	invitee, err := uc.UserRepository.GetUserByUserNameOrEmail(ctx, input.Login)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.NewError(domain.ErrNotFound, "user does not exists")
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	member, err := uc.RoomMemberRepository.GetMember(ctx, input.RoomID, invitee.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch room member", err)
+	}
+
+	if member != nil {
+		switch member.Status {
+		case domain.MembershipBanned:
+			return nil, domain.NewError(domain.ErrForbidden, "user is banned from this room")
+		case domain.MembershipActive:
+			return nil, domain.NewError(domain.ErrConflict, "user is already a member of this room")
+		default:
+			return &MemberOutput{Member: member}, nil
+		}
+	}
+
+	member, err = domain.NewRoomMember(input.RoomID, invitee.ID, domain.RoomRoleMember, domain.MembershipInvited)
+	if err != nil {
+		return nil, domain.NewError(domain.ErrInternalServerError, err.Error())
+	}
+	member.UserName = invitee.UserName
+	member.DisplayName = invitee.DisplayName
+	member.InvitedBy = &input.UserID
+
+	if err = uc.RoomMemberRepository.Save(ctx, member); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save room member", err)
+	}
+
+	return &MemberOutput{Member: member}, nil
+}
@@ -0,0 +1,68 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}
+
+func newInviteMemberUseCase(db *sql.DB) *InviteMemberUseCase {
+	return NewInviteMemberUseCase(repository.NewUserRepository(db), repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+}
+
+func Test_Admin_Invites_User_To_Private_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WithArgs(int32(5), int32(1)).WillReturnRows(memberRow(5, 1, domain.RoomRoleAdmin, domain.MembershipActive))
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WithArgs("joaquim2019").WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "joaquim2019", "Joaquim", "joaquim@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WithArgs(int32(5), int32(3)).WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(5), int32(3), domain.RoomRoleMember, domain.MembershipInvited, int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	output, err := newInviteMemberUseCase(db).Execute(context.Background(), InviteMemberInput{UserID: 1, RoomID: 5, Login: "joaquim2019"})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.MembershipInvited, output.Member.Status)
+	assert.Equal(t, "joaquim2019", output.Member.UserName)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Member_Cannot_Invite_To_Private_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipActive))
+
+	_, err := newInviteMemberUseCase(db).Execute(context.Background(), InviteMemberInput{UserID: 1, RoomID: 5, Login: "joaquim2019"})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_Invite_Fails_When_User_Does_Not_Exists(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipActive))
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+	_, err := newInviteMemberUseCase(db).Execute(context.Background(), InviteMemberInput{UserID: 1, RoomID: 5, Login: "nobody"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_Invite_Fails_When_User_Is_Already_Member(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipActive))
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "joaquim2019", "Joaquim", "joaquim@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 3, domain.RoomRoleMember, domain.MembershipActive))
+
+	_, err := newInviteMemberUseCase(db).Execute(context.Background(), InviteMemberInput{UserID: 1, RoomID: 5, Login: "joaquim2019"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
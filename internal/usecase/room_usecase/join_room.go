@@ -0,0 +1,76 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type RoomMembershipInput struct {
+	UserID int32
+	RoomID int32
+}
+
+type MemberOutput struct {
+	Member *domain.RoomMember
+}
+
+type JoinRoomUseCaseInterface interface {
+	Execute(ctx context.Context, input RoomMembershipInput) (*MemberOutput, error)
+}
+
+type JoinRoomUseCase struct {
+	RoomRepository       domain.RoomRepositoryInterface
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+}
+
+func NewJoinRoomUseCase(roomRepository domain.RoomRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface) *JoinRoomUseCase {
+	return &JoinRoomUseCase{
+		RoomRepository:       roomRepository,
+		RoomMemberRepository: roomMemberRepository,
+	}
+}
+
+func (uc *JoinRoomUseCase) Execute(ctx context.Context, input RoomMembershipInput) (*MemberOutput, error) {
+	room, err := fetchRoom(ctx, uc.RoomRepository, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := uc.RoomMemberRepository.GetMember(ctx, input.RoomID, input.UserID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch room member", err)
+	}
+
+	if member == nil && room.Visibility == domain.RoomPrivate {
+		return nil, roomNotFoundError()
+	}
+
+	if room.IsArchived() {
+		return nil, domain.NewError(domain.ErrBadRequest, "room is archived")
+	}
+
+	if member != nil {
+		switch member.Status {
+		case domain.MembershipBanned:
+			return nil, domain.NewError(domain.ErrForbidden, "user is banned from this room")
+		case domain.MembershipActive:
+			return &MemberOutput{Member: member}, nil
+		}
+
+		// Accepting a pending invite.
+		member.Status = domain.MembershipActive
+	} else {
+		member, err = domain.NewRoomMember(room.ID, input.UserID, domain.RoomRoleMember, domain.MembershipActive)
+		if err != nil {
+			return nil, domain.NewError(domain.ErrInternalServerError, err.Error())
+		}
+	}
+
+	if err = uc.RoomMemberRepository.Save(ctx, member); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save room member", err)
+	}
+
+	return &MemberOutput{Member: member}, nil
+}
@@ -0,0 +1,73 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newJoinRoomUseCase(db *sql.DB) *JoinRoomUseCase {
+	return NewJoinRoomUseCase(repository.NewRoomRepository(db), repository.NewRoomMemberRepository(db))
+}
+
+func Test_User_Joins_Public_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(5), int32(1), domain.RoomRoleMember, domain.MembershipActive, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	output, err := newJoinRoomUseCase(db).Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.True(t, output.Member.IsActive())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_User_Cannot_Join_Private_Room_Without_Invite(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+
+	_, err := newJoinRoomUseCase(db).Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_Invited_User_Joins_Private_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "secret", "", "private", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipInvited))
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(5), int32(1), domain.RoomRoleMember, domain.MembershipActive, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	output, err := newJoinRoomUseCase(db).Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.True(t, output.Member.IsActive())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Banned_User_Cannot_Join_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipBanned))
+
+	_, err := newJoinRoomUseCase(db).Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_User_Cannot_Join_Archived_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, time.Now(), time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+
+	_, err := newJoinRoomUseCase(db).Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
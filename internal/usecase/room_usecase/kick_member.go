@@ -0,0 +1,40 @@
+package room_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type KickMemberUseCaseInterface interface {
+	Execute(ctx context.Context, input ModerateMemberInput) error
+}
+
+type KickMemberUseCase struct {
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewKickMemberUseCase(roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *KickMemberUseCase {
+	return &KickMemberUseCase{
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+func (uc *KickMemberUseCase) Execute(ctx context.Context, input ModerateMemberInput) error {
+	moderation, err := authorizeModeration(ctx, uc.AuthorizeRoomUseCase, uc.RoomMemberRepository, input, PermissionModerate)
+	if err != nil {
+		return err
+	}
+
+	if moderation.target == nil || moderation.target.IsBanned() {
+		return memberNotFoundError()
+	}
+
+	if err = uc.RoomMemberRepository.Delete(ctx, input.RoomID, input.TargetUserID); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to remove room member", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package room_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type LeaveRoomUseCaseInterface interface {
+	Execute(ctx context.Context, input RoomMembershipInput) error
+}
+
+type LeaveRoomUseCase struct {
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewLeaveRoomUseCase(roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *LeaveRoomUseCase {
+	return &LeaveRoomUseCase{
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+// Execute removes the user from the room. It is also how a pending invite is declined.
+func (uc *LeaveRoomUseCase) Execute(ctx context.Context, input RoomMembershipInput) error {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: PermissionView,
+	})
+	if err != nil {
+		return err
+	}
+
+	if access.Member == nil {
+		return domain.NewError(domain.ErrForbidden, "user is not a member of this room")
+	}
+
+	if access.Room.IsDirect() {
+		return domain.NewError(domain.ErrBadRequest, "direct conversations cannot be left")
+	}
+
+	if access.Member.Role == domain.RoomRoleOwner {
+		return domain.NewError(domain.ErrBadRequest, "room owner cannot leave the room")
+	}
+
+	if err = uc.RoomMemberRepository.Delete(ctx, input.RoomID, input.UserID); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to remove room member", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Member_Leaves_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucLeave := NewLeaveRoomUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleAdmin, domain.MembershipActive))
+	mock.ExpectExec("DELETE FROM room_member").WithArgs(int32(5), int32(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, ucLeave.Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5}))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Owner_Cannot_Leave_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucLeave := NewLeaveRoomUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleOwner, domain.MembershipActive))
+
+	err := ucLeave.Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
+
+func Test_Non_Member_Cannot_Leave_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucLeave := NewLeaveRoomUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnError(sql.ErrNoRows)
+
+	err := ucLeave.Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_Direct_Conversation_Cannot_Be_Left(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucLeave := NewLeaveRoomUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(7, "dm:1:3", "", "private", "direct", 3, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(7, 1, domain.RoomRoleMember, domain.MembershipActive))
+
+	err := ucLeave.Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 7})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
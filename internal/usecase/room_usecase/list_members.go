@@ -0,0 +1,45 @@
+package room_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ListMembersOutput struct {
+	Members []*domain.RoomMember
+}
+
+type ListMembersUseCaseInterface interface {
+	Execute(ctx context.Context, input RoomMembershipInput) (*ListMembersOutput, error)
+}
+
+type ListMembersUseCase struct {
+	RoomMemberRepository domain.RoomMemberRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewListMembersUseCase(roomMemberRepository domain.RoomMemberRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *ListMembersUseCase {
+	return &ListMembersUseCase{
+		RoomMemberRepository: roomMemberRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+func (uc *ListMembersUseCase) Execute(ctx context.Context, input RoomMembershipInput) (*ListMembersOutput, error) {
+	_, err := uc.AuthorizeRoomUseCase.Execute(ctx, AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: PermissionView,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := uc.RoomMemberRepository.ListMembers(ctx, input.RoomID)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to list room members", err)
+	}
+
+	return &ListMembersOutput{Members: members}, nil
+}
@@ -0,0 +1,27 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Members_Of_Public_Room_Are_Listed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucList := NewListMembersUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.user_id = \\$2").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member (.+) m.status <> 'banned'").WillReturnRows(memberRow(5, 2, domain.RoomRoleOwner, domain.MembershipActive))
+
+	output, err := ucList.Execute(context.Background(), RoomMembershipInput{UserID: 1, RoomID: 5})
+	assert.Nil(t, err)
+	assert.Len(t, output.Members, 1)
+	assert.Equal(t, domain.RoomRoleOwner, output.Members[0].Role)
+}
@@ -0,0 +1,39 @@
+package room_usecase
+
+import (
+	"context"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ListRoomsInput struct {
+	UserID int32
+}
+
+type ListRoomsOutput struct {
+	Rooms []*domain.Room
+}
+
+type ListRoomsUseCaseInterface interface {
+	Execute(ctx context.Context, input ListRoomsInput) (*ListRoomsOutput, error)
+}
+
+type ListRoomsUseCase struct {
+	RoomRepository domain.RoomRepositoryInterface
+}
+
+func NewListRoomsUseCase(roomRepository domain.RoomRepositoryInterface) *ListRoomsUseCase {
+	return &ListRoomsUseCase{
+		RoomRepository: roomRepository,
+	}
+}
+
+func (uc *ListRoomsUseCase) Execute(ctx context.Context, input ListRoomsInput) (*ListRoomsOutput, error) {
+	rooms, err := uc.RoomRepository.ListRoomsVisibleTo(ctx, input.UserID)
+
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to list rooms", err)
+	}
+
+	return &ListRoomsOutput{Rooms: rooms}, nil
+}
@@ -0,0 +1,35 @@
+package room_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Rooms_Are_Listed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucList := NewListRoomsUseCase(repository.NewRoomRepository(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room").WithArgs(int32(1)).WillReturnRows(rows)
+
+	output, err := ucList.Execute(context.Background(), ListRoomsInput{UserID: 1})
+	assert.Nil(t, err)
+	assert.Len(t, output.Rooms, 1)
+}
+
+func Test_If_Get_Error_When_Try_List_Rooms(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucList := NewListRoomsUseCase(repository.NewRoomRepository(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room").WillReturnError(errors.New("an internal error"))
+
+	_, err := ucList.Execute(context.Background(), ListRoomsInput{UserID: 1})
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+}
@@ -0,0 +1,54 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type ModerateMemberInput struct {
+	UserID       int32
+	RoomID       int32
+	TargetUserID int32
+}
+
+type moderation struct {
+	actor  *domain.RoomMember
+	target *domain.RoomMember
+}
+
+// authorizeModeration checks that the actor may act on the target member. The
+// target is nil when the user has no membership row in the room.
+func authorizeModeration(ctx context.Context, authorizeRoomUseCase AuthorizeRoomUseCaseInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, input ModerateMemberInput, permission RoomPermission) (*moderation, error) {
+	access, err := authorizeRoomUseCase.Execute(ctx, AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: permission,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.TargetUserID == input.UserID {
+		return nil, domain.NewError(domain.ErrBadRequest, "user cannot moderate themselves")
+	}
+
+	target, err := roomMemberRepository.GetMember(ctx, input.RoomID, input.TargetUserID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch room member", err)
+		}
+		target = nil
+	}
+
+	if target != nil && !access.Member.Outranks(target) {
+		return nil, domain.NewError(domain.ErrForbidden, "user cannot moderate a member with the same or a higher role")
+	}
+
+	return &moderation{actor: access.Member, target: target}, nil
+}
+
+func memberNotFoundError() error {
+	return domain.NewError(domain.ErrNotFound, "user is not a member of this room")
+}
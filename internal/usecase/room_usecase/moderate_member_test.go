@@ -0,0 +1,113 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectModeration(mock sqlmock.Sqlmock, actorRole domain.RoomRole, target *sqlmock.Rows) {
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WithArgs(int32(5), int32(1)).WillReturnRows(memberRow(5, 1, actorRole, domain.MembershipActive))
+	if target == nil {
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WithArgs(int32(5), int32(3)).WillReturnError(sql.ErrNoRows)
+	} else {
+		mock.ExpectQuery("SELECT (.+) FROM room_member").WithArgs(int32(5), int32(3)).WillReturnRows(target)
+	}
+}
+
+func Test_Admin_Kicks_Member(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucKick := NewKickMemberUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	expectModeration(mock, domain.RoomRoleAdmin, memberRow(5, 3, domain.RoomRoleMember, domain.MembershipActive))
+	mock.ExpectExec("DELETE FROM room_member").WithArgs(int32(5), int32(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, ucKick.Execute(context.Background(), ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 3}))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Admin_Cannot_Kick_Another_Admin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucKick := NewKickMemberUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	expectModeration(mock, domain.RoomRoleAdmin, memberRow(5, 3, domain.RoomRoleAdmin, domain.MembershipActive))
+
+	err := ucKick.Execute(context.Background(), ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 3})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_Kick_Fails_When_Target_Is_Not_Member(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucKick := NewKickMemberUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	expectModeration(mock, domain.RoomRoleOwner, nil)
+
+	err := ucKick.Execute(context.Background(), ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 3})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func Test_Member_Cannot_Moderate_Themselves(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucKick := NewKickMemberUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, nil, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleOwner, domain.MembershipActive))
+
+	err := ucKick.Execute(context.Background(), ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 1})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
+
+func Test_Owner_Bans_Admin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucBan := NewBanMemberUseCase(repository.NewUserRepository(db), repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	expectModeration(mock, domain.RoomRoleOwner, memberRow(5, 3, domain.RoomRoleAdmin, domain.MembershipActive))
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(5), int32(3), domain.RoomRoleMember, domain.MembershipBanned, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	output, err := ucBan.Execute(context.Background(), ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 3})
+	assert.Nil(t, err)
+	assert.True(t, output.Member.IsBanned())
+	assert.Equal(t, domain.RoomRoleMember, output.Member.Role)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Admin_Bans_User_Who_Never_Joined(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucBan := NewBanMemberUseCase(repository.NewUserRepository(db), repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	expectModeration(mock, domain.RoomRoleAdmin, nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(3)).WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "joaquim2019", "Joaquim", "joaquim@gmail.com", "hash", "", "", nil, time.Now(), nil))
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(5), int32(3), domain.RoomRoleMember, domain.MembershipBanned, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	output, err := ucBan.Execute(context.Background(), ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 3})
+	assert.Nil(t, err)
+	assert.True(t, output.Member.IsBanned())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Owner_Promotes_Member_To_Admin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucChangeRole := NewChangeMemberRoleUseCase(repository.NewRoomMemberRepository(db), newAuthorizeRoomUseCase(db))
+
+	expectModeration(mock, domain.RoomRoleOwner, memberRow(5, 3, domain.RoomRoleMember, domain.MembershipActive))
+	mock.ExpectExec("INSERT INTO room_member").WithArgs(int32(5), int32(3), domain.RoomRoleAdmin, domain.MembershipActive, nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	output, err := ucChangeRole.Execute(context.Background(), ChangeMemberRoleInput{ModerateMemberInput: ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 3}, Role: "admin"})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.RoomRoleAdmin, output.Member.Role)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Ownership_Cannot_Be_Granted(t *testing.T) {
+	ucChangeRole := NewChangeMemberRoleUseCase(repository.NewRoomMemberRepository(nil), newAuthorizeRoomUseCase(nil))
+
+	_, err := ucChangeRole.Execute(context.Background(), ChangeMemberRoleInput{ModerateMemberInput: ModerateMemberInput{UserID: 1, RoomID: 5, TargetUserID: 3}, Role: "owner"})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
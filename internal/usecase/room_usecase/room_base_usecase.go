@@ -0,0 +1,41 @@
+package room_usecase
+
+import (
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type RoomBaseUseCase struct {
+	AuthorizeRoomUseCase    AuthorizeRoomUseCaseInterface
+	CreateRoomUseCase       CreateRoomUseCaseInterface
+	ListRoomsUseCase        ListRoomsUseCaseInterface
+	GetRoomUseCase          GetRoomUseCaseInterface
+	UpdateRoomUseCase       UpdateRoomUseCaseInterface
+	ArchiveRoomUseCase      ArchiveRoomUseCaseInterface
+	JoinRoomUseCase         JoinRoomUseCaseInterface
+	LeaveRoomUseCase        LeaveRoomUseCaseInterface
+	InviteMemberUseCase     InviteMemberUseCaseInterface
+	ListMembersUseCase      ListMembersUseCaseInterface
+	ChangeMemberRoleUseCase ChangeMemberRoleUseCaseInterface
+	KickMemberUseCase       KickMemberUseCaseInterface
+	BanMemberUseCase        BanMemberUseCaseInterface
+}
+
+func NewRoomBaseUseCase(roomRepository domain.RoomRepositoryInterface, roomMemberRepository domain.RoomMemberRepositoryInterface, userRepository domain.UserRepositoryInterface, unitOfWork domain.UnitOfWorkInterface) *RoomBaseUseCase {
+	authorizeRoomUseCase := NewAuthorizeRoomUseCase(roomRepository, roomMemberRepository)
+
+	return &RoomBaseUseCase{
+		AuthorizeRoomUseCase:    authorizeRoomUseCase,
+		CreateRoomUseCase:       NewCreateRoomUseCase(roomRepository, roomMemberRepository, unitOfWork),
+		ListRoomsUseCase:        NewListRoomsUseCase(roomRepository),
+		GetRoomUseCase:          NewGetRoomUseCase(authorizeRoomUseCase),
+		UpdateRoomUseCase:       NewUpdateRoomUseCase(roomRepository, authorizeRoomUseCase),
+		ArchiveRoomUseCase:      NewArchiveRoomUseCase(roomRepository, authorizeRoomUseCase),
+		JoinRoomUseCase:         NewJoinRoomUseCase(roomRepository, roomMemberRepository),
+		LeaveRoomUseCase:        NewLeaveRoomUseCase(roomMemberRepository, authorizeRoomUseCase),
+		InviteMemberUseCase:     NewInviteMemberUseCase(userRepository, roomMemberRepository, authorizeRoomUseCase),
+		ListMembersUseCase:      NewListMembersUseCase(roomMemberRepository, authorizeRoomUseCase),
+		ChangeMemberRoleUseCase: NewChangeMemberRoleUseCase(roomMemberRepository, authorizeRoomUseCase),
+		KickMemberUseCase:       NewKickMemberUseCase(roomMemberRepository, authorizeRoomUseCase),
+		BanMemberUseCase:        NewBanMemberUseCase(userRepository, roomMemberRepository, authorizeRoomUseCase),
+	}
+}
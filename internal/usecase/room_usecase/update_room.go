@@ -0,0 +1,80 @@
+package room_usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type UpdateRoomInput struct {
+	UserID     int32
+	RoomID     int32
+	Name       *string
+	Topic      *string
+	Visibility *string
+}
+
+type UpdateRoomUseCaseInterface interface {
+	Execute(ctx context.Context, input UpdateRoomInput) (*RoomOutput, error)
+}
+
+type UpdateRoomUseCase struct {
+	RoomRepository       domain.RoomRepositoryInterface
+	AuthorizeRoomUseCase AuthorizeRoomUseCaseInterface
+}
+
+func NewUpdateRoomUseCase(roomRepository domain.RoomRepositoryInterface, authorizeRoomUseCase AuthorizeRoomUseCaseInterface) *UpdateRoomUseCase {
+	return &UpdateRoomUseCase{
+		RoomRepository:       roomRepository,
+		AuthorizeRoomUseCase: authorizeRoomUseCase,
+	}
+}
+
+func (uc *UpdateRoomUseCase) Execute(ctx context.Context, input UpdateRoomInput) (*RoomOutput, error) {
+	access, err := uc.AuthorizeRoomUseCase.Execute(ctx, AuthorizeRoomInput{
+		UserID:     input.UserID,
+		RoomID:     input.RoomID,
+		Permission: PermissionModerate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	room := access.Room
+
+	if room.IsArchived() {
+		return nil, domain.NewError(domain.ErrBadRequest, "room is archived")
+	}
+
+	if input.Name != nil {
+		room.Name = *input.Name
+	}
+	if input.Topic != nil {
+		room.Topic = *input.Topic
+	}
+	if input.Visibility != nil {
+		room.Visibility = domain.RoomVisibility(*input.Visibility)
+	}
+
+	if err = room.Validate(); err != nil {
+		return nil, domain.NewError(domain.ErrBadRequest, err.Error())
+	}
+
+	if input.Name != nil {
+		if err = checkIfRoomNameIsTaken(ctx, uc.RoomRepository, room.Name, room.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Another rename may take the name after the check above, which the
+	// unique index reports as a conflict.
+	if err = uc.RoomRepository.Update(ctx, room); errors.Is(err, domain.ErrConflict) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to update room", err)
+	}
+
+	return &RoomOutput{Room: room}, nil
+}
@@ -0,0 +1,71 @@
+package room_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Only_Moderators_Can_Update_Room(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucUpdate := NewUpdateRoomUseCase(repository.NewRoomRepository(db), newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleMember, domain.MembershipActive))
+
+	topic := "New topic"
+	_, err := ucUpdate.Execute(context.Background(), UpdateRoomInput{UserID: 1, RoomID: 5, Topic: &topic})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func Test_If_Archived_Room_Cannot_Be_Updated(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucUpdate := NewUpdateRoomUseCase(repository.NewRoomRepository(db), newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, time.Now(), time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleOwner, domain.MembershipActive))
+
+	topic := "New topic"
+	_, err := ucUpdate.Execute(context.Background(), UpdateRoomInput{UserID: 1, RoomID: 5, Topic: &topic})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
+
+func Test_If_Get_Error_When_Update_Is_Invalid(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucUpdate := NewUpdateRoomUseCase(repository.NewRoomRepository(db), newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 1, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleOwner, domain.MembershipActive))
+
+	visibility := "secret"
+	_, err := ucUpdate.Execute(context.Background(), UpdateRoomInput{UserID: 1, RoomID: 5, Visibility: &visibility})
+	expectedError := domain.NewError(domain.ErrBadRequest, "room visibility must be public or private")
+	assert.EqualError(t, err, expectedError.Error())
+}
+
+func Test_Room_Is_Updated_By_Admin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucUpdate := NewUpdateRoomUseCase(repository.NewRoomRepository(db), newAuthorizeRoomUseCase(db))
+
+	rows := sqlmock.NewRows(roomColumns).AddRow(5, "general", "", "public", "channel", 2, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE id").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM room_member").WillReturnRows(memberRow(5, 1, domain.RoomRoleAdmin, domain.MembershipActive))
+	mock.ExpectQuery("SELECT (.+) FROM room WHERE name").WithArgs("lobby").WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE room SET name").WithArgs(int32(5), "lobby", "", domain.RoomPrivate).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	name, visibility := "lobby", "private"
+	output, err := ucUpdate.Execute(context.Background(), UpdateRoomInput{UserID: 1, RoomID: 5, Name: &name, Visibility: &visibility})
+	assert.Nil(t, err)
+	assert.Equal(t, "lobby", output.Room.Name)
+	assert.Equal(t, domain.RoomPrivate, output.Room.Visibility)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
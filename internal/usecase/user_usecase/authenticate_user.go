@@ -0,0 +1,57 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type AuthenticateUserOutput struct {
+	User *domain.User
+	// AccessTokenExpiresAt is when the token presented stops being valid, and
+	// the connections opened with it must be closed.
+	AccessTokenExpiresAt time.Time
+}
+
+type AuthenticateUserUseCaseInterface interface {
+	Execute(ctx context.Context, accessToken string) (*AuthenticateUserOutput, error)
+}
+
+type AuthenticateUserUseCase struct {
+	UserRepository domain.UserRepositoryInterface
+	TokenManager   util.TokenManager
+}
+
+func NewAuthenticateUserUseCase(userRepository domain.UserRepositoryInterface, tokenManager util.TokenManager) *AuthenticateUserUseCase {
+	return &AuthenticateUserUseCase{
+		UserRepository: userRepository,
+		TokenManager:   tokenManager,
+	}
+}
+
+func (uc *AuthenticateUserUseCase) Execute(ctx context.Context, accessToken string) (*AuthenticateUserOutput, error) {
+	claims, err := uc.TokenManager.ParseToken(accessToken)
+	if err != nil {
+		return nil, domain.NewError(domain.ErrUnauthorized, err.Error())
+	}
+
+	user, err := uc.UserRepository.GetUserById(ctx, claims.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.NewError(domain.ErrUnauthorized, "user does not exists")
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	if user.IsTokenRevoked(claims.IssuedAt) {
+		return nil, domain.NewError(domain.ErrUnauthorized, "token was revoked by a password reset")
+	}
+
+	return &AuthenticateUserOutput{
+		User:                 user,
+		AccessTokenExpiresAt: claims.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_User_Is_Authenticated_With_Valid_Token(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	userRepository := repository.NewUserRepository(db)
+	tokenManager := util.NewJWTTokenManager("secret", time.Minute)
+	ucAuth := NewAuthenticateUserUseCase(userRepository, tokenManager)
+
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(7, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user WHERE id").WithArgs(int32(7)).WillReturnRows(rows)
+
+	accessToken, expiresAt, _ := tokenManager.GenerateToken(7)
+	output, err := ucAuth.Execute(context.Background(), accessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(7), output.User.ID)
+	assert.Equal(t, expiresAt.Unix(), output.AccessTokenExpiresAt.Unix())
+}
+
+func Test_If_Get_Unauthorized_With_A_Token_Issued_Before_A_Password_Reset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	tokenManagerMock := &util.MockTokenManager{}
+	ucAuth := NewAuthenticateUserUseCase(repository.NewUserRepository(db), tokenManagerMock)
+
+	// The reset happened later in the same second the token was issued.
+	issuedAt := time.Now().Truncate(time.Second)
+	rows := sqlmock.NewRows(userColumns).AddRow(7, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), issuedAt.Add(500*time.Millisecond))
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WillReturnRows(rows)
+	tokenManagerMock.On("ParseToken", "token").Return(&util.TokenClaims{UserID: 7, IssuedAt: issuedAt, ExpiresAt: issuedAt.Add(time.Minute)}, nil)
+
+	_, err := ucAuth.Execute(context.Background(), "token")
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func Test_If_User_Is_Authenticated_With_A_Token_Issued_After_A_Password_Reset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	tokenManagerMock := &util.MockTokenManager{}
+	ucAuth := NewAuthenticateUserUseCase(repository.NewUserRepository(db), tokenManagerMock)
+
+	resetAt := time.Now().Add(-time.Minute)
+	rows := sqlmock.NewRows(userColumns).AddRow(7, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), resetAt)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WillReturnRows(rows)
+	tokenManagerMock.On("ParseToken", "token").Return(&util.TokenClaims{UserID: 7, IssuedAt: time.Now().Truncate(time.Second), ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+	output, err := ucAuth.Execute(context.Background(), "token")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(7), output.User.ID)
+}
+
+func Test_If_Get_Unauthorized_With_Invalid_Token(t *testing.T) {
+	tokenManagerMock := &util.MockTokenManager{}
+	ucAuth := NewAuthenticateUserUseCase(repository.NewUserRepository(nil), tokenManagerMock)
+
+	tokenManagerMock.On("ParseToken", "invalid").Return(nil, util.ErrInvalidToken)
+
+	_, err := ucAuth.Execute(context.Background(), "invalid")
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func Test_If_Get_Unauthorized_When_User_No_Longer_Exists(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	tokenManagerMock := &util.MockTokenManager{}
+	ucAuth := NewAuthenticateUserUseCase(repository.NewUserRepository(db), tokenManagerMock)
+
+	tokenManagerMock.On("ParseToken", "token").Return(&util.TokenClaims{UserID: 7}, nil)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user WHERE id").WillReturnError(sql.ErrNoRows)
+
+	_, err := ucAuth.Execute(context.Background(), "token")
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func Test_If_Get_Internal_Error_When_Try_Fetch_Authenticated_User(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	tokenManagerMock := &util.MockTokenManager{}
+	ucAuth := NewAuthenticateUserUseCase(repository.NewUserRepository(db), tokenManagerMock)
+
+	tokenManagerMock.On("ParseToken", "token").Return(&util.TokenClaims{UserID: 7}, nil)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user WHERE id").WillReturnError(errors.New("an internal error"))
+
+	_, err := ucAuth.Execute(context.Background(), "token")
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+}
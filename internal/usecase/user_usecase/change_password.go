@@ -0,0 +1,62 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type ChangePasswordInput struct {
+	UserID          int32
+	CurrentPassword string
+	NewPassword     string
+}
+
+type ChangePasswordUseCaseInterface interface {
+	Execute(ctx context.Context, input ChangePasswordInput) error
+}
+
+type ChangePasswordUseCase struct {
+	UserRepository domain.UserRepositoryInterface
+	PasswordHasher util.PasswordHasher
+}
+
+func NewChangePasswordUseCase(userRepository domain.UserRepositoryInterface, passwordHasher util.PasswordHasher) *ChangePasswordUseCase {
+	return &ChangePasswordUseCase{
+		UserRepository: userRepository,
+		PasswordHasher: passwordHasher,
+	}
+}
+
+func (uc *ChangePasswordUseCase) Execute(ctx context.Context, input ChangePasswordInput) error {
+	user, err := uc.UserRepository.GetUserById(ctx, input.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.NewError(domain.ErrNotFound, "user does not exists")
+		}
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	if !uc.PasswordHasher.VerifyPassword(input.CurrentPassword, user.Password) {
+		return domain.NewFieldError(domain.ErrBadRequest, "current password does not match",
+			domain.FieldError{Field: "currentPassword", Message: "does not match"})
+	}
+
+	if err = domain.ValidatePassword(input.NewPassword); err != nil {
+		return domain.NewFieldError(domain.ErrBadRequest, err.Error(),
+			domain.FieldError{Field: "newPassword", Message: "is not secure"})
+	}
+
+	passwordHash, err := uc.PasswordHasher.HashPassword(input.NewPassword)
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to hash password", err)
+	}
+
+	if err = uc.UserRepository.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to update password", err)
+	}
+
+	return nil
+}
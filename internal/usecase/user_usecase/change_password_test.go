@@ -0,0 +1,59 @@
+package user_usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Password_Is_Changed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passHasherMock := &util.MockPasswordHasher{}
+	ucChange := NewChangePasswordUseCase(repository.NewUserRepository(db), passHasherMock)
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(rows)
+	mock.ExpectExec("UPDATE app_user SET password").WithArgs(int32(1), "newHash").WillReturnResult(sqlmock.NewResult(0, 1))
+	passHasherMock.On("VerifyPassword", "P4$$w0rd", "hash").Return(true)
+	passHasherMock.On("HashPassword", "N3w$$w0rd").Return("newHash", nil)
+
+	err := ucChange.Execute(context.Background(), ChangePasswordInput{UserID: 1, CurrentPassword: "P4$$w0rd", NewPassword: "N3w$$w0rd"})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Error_When_Current_Password_Does_Not_Match(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passHasherMock := &util.MockPasswordHasher{}
+	ucChange := NewChangePasswordUseCase(repository.NewUserRepository(db), passHasherMock)
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WillReturnRows(rows)
+	passHasherMock.On("VerifyPassword", "wrong", "hash").Return(false)
+
+	err := ucChange.Execute(context.Background(), ChangePasswordInput{UserID: 1, CurrentPassword: "wrong", NewPassword: "N3w$$w0rd"})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+	assert.EqualError(t, err, "current password does not match")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Error_When_New_Password_Is_Not_Secure(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passHasherMock := &util.MockPasswordHasher{}
+	ucChange := NewChangePasswordUseCase(repository.NewUserRepository(db), passHasherMock)
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WillReturnRows(rows)
+	passHasherMock.On("VerifyPassword", "P4$$w0rd", "hash").Return(true)
+
+	err := ucChange.Execute(context.Background(), ChangePasswordInput{UserID: 1, CurrentPassword: "P4$$w0rd", NewPassword: "weak"})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+	assert.EqualError(t, err, "password is not secure")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
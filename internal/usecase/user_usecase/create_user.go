@@ -1,12 +1,16 @@
 package user_usecase
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/eduardolima806/my-chat-server/internal/domain"
 	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 )
 
 type UserInput struct {
@@ -21,71 +25,107 @@ type UserOutput struct {
 }
 
 type CreateUserUseCaseInterface interface {
-	Execute(input UserInput) (*UserOutput, error)
+	Execute(ctx context.Context, input UserInput) (*UserOutput, error)
 }
 
 type CreateUserUseCase struct {
 	UserRepository domain.UserRepositoryInterface
 	PasswordHasher util.PasswordHasher
+	UnitOfWork     domain.UnitOfWorkInterface
+	verifier       *emailVerifier
+	metrics        *Metrics
+	logger         logger.Interface
 }
 
 const IdDummy = 0
 
-func NewCreateUserUseCase(userRepository domain.UserRepositoryInterface, passwordHasher util.PasswordHasher) *CreateUserUseCase {
+func NewCreateUserUseCase(userRepository domain.UserRepositoryInterface, emailVerificationTokenRepository domain.EmailVerificationTokenRepositoryInterface, passwordHasher util.PasswordHasher, mailer util.Mailer, unitOfWork domain.UnitOfWorkInterface, emailVerificationTTL time.Duration, verifyEmailURL string, m *Metrics, l logger.Interface) *CreateUserUseCase {
 	return &CreateUserUseCase{
 		UserRepository: userRepository,
 		PasswordHasher: passwordHasher,
+		UnitOfWork:     unitOfWork,
+		verifier: &emailVerifier{
+			tokenRepository: emailVerificationTokenRepository,
+			mailer:          mailer,
+			ttl:             emailVerificationTTL,
+			verifyURL:       verifyEmailURL,
+		},
+		metrics: m,
+		logger:  l,
 	}
 }
 
-func (cUser *CreateUserUseCase) Execute(userInput UserInput) (*UserOutput, error) {
+func (cUser *CreateUserUseCase) Execute(ctx context.Context, userInput UserInput) (*UserOutput, error) {
 	user, err := domain.NewUser(IdDummy, userInput.UserName, userInput.DisplayName, userInput.Email, userInput.Password)
 	if err != nil {
-		return nil, domain.CreateError(domain.ErrBadRequest.Error(), err.Error())
-	}
-
-	err = checkIfUserExists(cUser, userInput)
-	if err != nil {
-		return nil, domain.CreateError(domain.ErrBadRequest.Error(), err.Error())
+		return nil, domain.NewError(domain.ErrBadRequest, err.Error())
 	}
 
+	// Hashing is slow, so it is done before the transaction is opened.
 	user.Password, err = cUser.PasswordHasher.HashPassword(user.Password)
 
 	if err != nil {
-		return nil, domain.CreateError(domain.ErrInternalServerError.Error(), err.Error())
+		return nil, domain.NewError(domain.ErrInternalServerError, err.Error())
 	}
 
-	idUserCreated, err := cUser.UserRepository.Save(user)
+	var verificationToken string
+	err = cUser.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := checkIfUserExists(ctx, cUser, userInput); err != nil {
+			return err
+		}
+
+		id, err := cUser.UserRepository.Save(ctx, user)
+		if err != nil {
+			return err
+		}
+		user.ID = id
+
+		verificationToken, err = cUser.verifier.issue(ctx, user.ID)
+		return err
+	})
+
+	// The unique indexes on app_user catch the signups that race past the
+	// check, so both paths report the same conflict.
+	if errors.Is(err, domain.ErrConflict) {
+		return nil, err
+	}
 
 	if err != nil {
-		return nil, domain.CreateError(domain.ErrInternalServerError.Error(), "could not possible to save user")
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save user", err)
+	}
+	cUser.metrics.signedUp()
+
+	// The account exists at this point, so a mail that could not be sent is
+	// not an error: the user can ask for another one.
+	if err = cUser.verifier.send(ctx, user, verificationToken); err != nil {
+		cUser.logger.Warn(ctx, "could not possible to send the verification email", "user_id", user.ID, "error", err)
 	}
 
 	return &UserOutput{
-		CreatedUserId: idUserCreated,
+		CreatedUserId: user.ID,
 	}, nil
 }
 
-func checkIfUserExists(cUser *CreateUserUseCase, userInput UserInput) error {
+func checkIfUserExists(ctx context.Context, cUser *CreateUserUseCase, userInput UserInput) error {
 
-	userToCheck, err := cUser.UserRepository.GetUserByUserNameOrEmail(userInput.UserName)
+	userToCheck, err := cUser.UserRepository.GetUserByUserNameOrEmail(ctx, userInput.UserName)
 
 	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
 
-	if userToCheck != nil && userInput.UserName == userToCheck.UserName {
-		return errors.New("username already exists")
+	if userToCheck != nil && strings.EqualFold(userInput.UserName, userToCheck.UserName) {
+		return domain.NewConflictError("username", "username already exists")
 	}
 
-	userToCheck, err = cUser.UserRepository.GetUserByUserNameOrEmail(userInput.Email)
+	userToCheck, err = cUser.UserRepository.GetUserByUserNameOrEmail(ctx, userInput.Email)
 
 	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
 
-	if userToCheck != nil && userToCheck.Email == userInput.Email {
-		return fmt.Errorf("already exists an user with this e-email: %s", userInput.Email)
+	if userToCheck != nil && strings.EqualFold(userToCheck.Email, userInput.Email) {
+		return domain.NewConflictError("email", fmt.Sprintf("already exists an user with this e-email: %s", userInput.Email))
 	}
 
 	return nil
@@ -1,9 +1,11 @@
 package user_usecase
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -11,16 +13,23 @@ import (
 	"github.com/eduardolima806/my-chat-server/internal/domain"
 	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
 	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+const verifyEmailURL = "http://localhost:8080/api/v1/users/verify-email"
+
 func Test_If_Get_Error_To_Create_Invalid_User(t *testing.T) {
 	userRepository := repository.NewUserRepository(nil)
 	passHasherMock := &util.MockPasswordHasher{}
 	userInput := UserInput{UserName: "ed12"}
-	ucCreate := NewCreateUserUseCase(userRepository, passHasherMock)
-	_, err := ucCreate.Execute(userInput)
-	expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "username must has at least 5 alphanumerics characters")
+	ucCreate := NewCreateUserUseCase(userRepository, repository.NewEmailVerificationTokenRepository(nil), passHasherMock, &util.MockMailer{}, repository.NewUnitOfWork(nil), time.Hour, verifyEmailURL, NewMetrics(prometheus.NewRegistry()), logger.Discard())
+	_, err := ucCreate.Execute(context.Background(), userInput)
+	expectedError := domain.NewError(domain.ErrBadRequest, "username must has at least 5 alphanumerics characters")
 	assert.EqualError(t, err, expectedError.Error())
 }
 
@@ -29,64 +38,156 @@ func Test_If_Get_Error_When_Create_An_Existing_User(t *testing.T) {
 	userRepository := repository.NewUserRepository(db)
 	passHasherMock := &util.MockPasswordHasher{}
 	userInput := UserInput{UserName: "eduardolima806", Email: "eduardolima.dev.io@gmail.com", Password: "P4$$w0rd"}
-	ucCreate := NewCreateUserUseCase(userRepository, passHasherMock)
+	ucCreate := NewCreateUserUseCase(userRepository, repository.NewEmailVerificationTokenRepository(db), passHasherMock, &util.MockMailer{}, repository.NewUnitOfWork(db), time.Hour, verifyEmailURL, NewMetrics(prometheus.NewRegistry()), logger.Discard())
+	passHasherMock.On("HashPassword", userInput.Password).Return("hashedPassword", nil)
 
 	t.Run("username already exists", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows)
+		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows)
+		mock.ExpectRollback()
 
-		_, err := ucCreate.Execute(userInput)
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), "username already exists")
+		_, err := ucCreate.Execute(context.Background(), userInput)
+		expectedError := domain.NewError(domain.ErrConflict, "username already exists")
 		assert.EqualError(t, err, expectedError.Error())
 	})
 
 	t.Run("email already exists", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-		rows2 := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows)
-		mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows2)
+		rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		rows2 := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", "", "", nil, time.Now(), nil)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows)
+		mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows2)
+		mock.ExpectRollback()
 
 		userInput.UserName = "eduardo123"
-		_, err := ucCreate.Execute(userInput)
-		expectedError := domain.CreateError(domain.ErrBadRequest.Error(), fmt.Sprintf("already exists an user with this e-email: %s", userInput.Email))
+		_, err := ucCreate.Execute(context.Background(), userInput)
+		expectedError := domain.NewError(domain.ErrConflict, fmt.Sprintf("already exists an user with this e-email: %s", userInput.Email))
 		assert.EqualError(t, err, expectedError.Error())
 	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
 }
 
 func Test_If_Get_Error_When_Try_Encrypt_Password(t *testing.T) {
-	db, mock, _ := sqlmock.New()
+	db, _, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
 	passHasherMock := &util.MockPasswordHasher{}
 	userInput := UserInput{UserName: "edulima", Email: "eduardolima@gmail.com", Password: "P4$$w0rd"}
-	ucCreate := NewCreateUserUseCase(userRepository, passHasherMock)
-
-	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-	rows2 := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "P4$$w0rd", time.Now())
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows)
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows2)
+	ucCreate := NewCreateUserUseCase(userRepository, repository.NewEmailVerificationTokenRepository(db), passHasherMock, &util.MockMailer{}, repository.NewUnitOfWork(db), time.Hour, verifyEmailURL, NewMetrics(prometheus.NewRegistry()), logger.Discard())
 
 	passHasherMock.On("HashPassword", userInput.Password).Return("", errors.New("encryptation error")).Once()
 
-	userCreateOutput, err := ucCreate.Execute(userInput)
+	userCreateOutput, err := ucCreate.Execute(context.Background(), userInput)
 	assert.Nil(t, userCreateOutput)
-	expecteError := domain.CreateError(domain.ErrInternalServerError.Error(), "encryptation error")
+	expecteError := domain.NewError(domain.ErrInternalServerError, "encryptation error")
 	assert.EqualError(t, err, expecteError.Error())
 }
 
 func Test_User_Is_Created_When_User_No_Existing(t *testing.T) {
-	db, mock, _ := sqlmock.New()
+	db, mockDb, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
 	passHasherMock := &util.MockPasswordHasher{}
 	userInput := UserInput{UserName: "eduardolimaNew", Email: "eduardolima.dev.io@gmail.com", Password: "P4$$w0rd"}
-	ucCreate := NewCreateUserUseCase(userRepository, passHasherMock)
+	mailerMock := &util.MockMailer{}
+	userMetrics := NewMetrics(prometheus.NewRegistry())
+	ucCreate := NewCreateUserUseCase(userRepository, repository.NewEmailVerificationTokenRepository(db), passHasherMock, mailerMock, repository.NewUnitOfWork(db), time.Hour, verifyEmailURL, userMetrics, logger.Discard())
 	passHasherMock.On("HashPassword", userInput.Password).Return("hashedPassword", nil)
 
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 	rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-	mock.ExpectQuery("INSERT INTO app_user").WillReturnRows(rows)
+	mockDb.ExpectQuery("INSERT INTO app_user").WillReturnRows(rows)
+	mockDb.ExpectQuery("INSERT INTO email_verification_token").WithArgs(int32(1), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDb.ExpectCommit()
+
+	var sent util.Mail
+	mailerMock.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).(util.Mail)
+	}).Return(nil)
 
-	userOutput, _ := ucCreate.Execute(userInput)
+	userOutput, _ := ucCreate.Execute(context.Background(), userInput)
 	assert.Equal(t, int32(1), userOutput.CreatedUserId)
 	passHasherMock.AssertExpectations(t)
+	assert.Equal(t, userInput.Email, sent.To)
+	assert.Contains(t, sent.Body, verifyEmailURL+"?token=")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(userMetrics.signups))
+
+	if err := mockDb.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_User_Is_Created_When_The_Verification_Mail_Is_Not_Sent(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	userRepository := repository.NewUserRepository(db)
+	passHasherMock := &util.MockPasswordHasher{}
+	mailerMock := &util.MockMailer{}
+	userInput := UserInput{UserName: "eduardolimaNew", Email: "eduardolima.dev.io@gmail.com", Password: "P4$$w0rd"}
+	ucCreate := NewCreateUserUseCase(userRepository, repository.NewEmailVerificationTokenRepository(db), passHasherMock, mailerMock, repository.NewUnitOfWork(db), time.Hour, verifyEmailURL, NewMetrics(prometheus.NewRegistry()), logger.Discard())
+	passHasherMock.On("HashPassword", userInput.Password).Return("hashedPassword", nil)
+	mailerMock.On("Send", mock.Anything, mock.Anything).Return(errors.New("mail server is down"))
+
+	mockDb.ExpectBegin()
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+	mockDb.ExpectQuery("INSERT INTO app_user").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDb.ExpectQuery("INSERT INTO email_verification_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDb.ExpectCommit()
+
+	userOutput, err := ucCreate.Execute(context.Background(), userInput)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), userOutput.CreatedUserId)
+}
+
+func Test_User_Is_Not_Created_When_The_Insert_Fails(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	userRepository := repository.NewUserRepository(db)
+	passHasherMock := &util.MockPasswordHasher{}
+	userInput := UserInput{UserName: "eduardolimaNew", Email: "eduardolima.dev.io@gmail.com", Password: "P4$$w0rd"}
+	ucCreate := NewCreateUserUseCase(userRepository, repository.NewEmailVerificationTokenRepository(db), passHasherMock, &util.MockMailer{}, repository.NewUnitOfWork(db), time.Hour, verifyEmailURL, NewMetrics(prometheus.NewRegistry()), logger.Discard())
+	passHasherMock.On("HashPassword", userInput.Password).Return("hashedPassword", nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO app_user").WillReturnError(errors.New("error to insert user"))
+	mock.ExpectRollback()
+
+	userOutput, err := ucCreate.Execute(context.Background(), userInput)
+	assert.Nil(t, userOutput)
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	assert.EqualError(t, err, "could not possible to save user: error to insert user")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_User_Is_Not_Created_When_A_Concurrent_Signup_Took_The_Username(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	userRepository := repository.NewUserRepository(db)
+	passHasherMock := &util.MockPasswordHasher{}
+	userInput := UserInput{UserName: "eduardolimaNew", Email: "eduardolima.dev.io@gmail.com", Password: "P4$$w0rd"}
+	ucCreate := NewCreateUserUseCase(userRepository, repository.NewEmailVerificationTokenRepository(db), passHasherMock, &util.MockMailer{}, repository.NewUnitOfWork(db), time.Hour, verifyEmailURL, NewMetrics(prometheus.NewRegistry()), logger.Discard())
+	passHasherMock.On("HashPassword", userInput.Password).Return("hashedPassword", nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO app_user").WillReturnError(&pq.Error{Code: "23505", Constraint: "app_user_username_key"})
+	mock.ExpectRollback()
+
+	userOutput, err := ucCreate.Execute(context.Background(), userInput)
+	assert.Nil(t, userOutput)
+	assert.Equal(t, http.StatusConflict, domain.GetHttpStatusCode(err))
+	assert.Equal(t, "username", domain.NewProblem(err).Errors[0].Field)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
 }
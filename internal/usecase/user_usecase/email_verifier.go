@@ -0,0 +1,49 @@
+package user_usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type emailVerifier struct {
+	tokenRepository domain.EmailVerificationTokenRepositoryInterface
+	mailer          util.Mailer
+	ttl             time.Duration
+	verifyURL       string
+}
+
+// issue saves a new verification token for the user and returns it in
+// plain text, to be mailed once the unit of work in ctx commits.
+func (v *emailVerifier) issue(ctx context.Context, userID int32) (string, error) {
+	verificationToken, err := util.GenerateSecureToken()
+	if err != nil {
+		return "", domain.WrapError(domain.ErrInternalServerError, "could not possible to issue verification token", err)
+	}
+
+	token := domain.NewEmailVerificationToken(userID, util.HashToken(verificationToken), v.ttl)
+
+	if _, err = v.tokenRepository.Save(ctx, token); err != nil {
+		return "", domain.WrapError(domain.ErrInternalServerError, "could not possible to save verification token", err)
+	}
+
+	return verificationToken, nil
+}
+
+func (v *emailVerifier) send(ctx context.Context, user *domain.User, verificationToken string) error {
+	err := v.mailer.Send(ctx, util.Mail{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Follow this link to verify the email of %s. It expires in %s.\r\n\r\n%s?%s",
+			user.UserName, v.ttl, v.verifyURL, url.Values{"token": {verificationToken}}.Encode()),
+	})
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to send verification mail", err)
+	}
+
+	return nil
+}
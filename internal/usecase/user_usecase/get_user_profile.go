@@ -0,0 +1,35 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type GetUserProfileUseCaseInterface interface {
+	Execute(ctx context.Context, userName string) (*domain.User, error)
+}
+
+type GetUserProfileUseCase struct {
+	UserRepository domain.UserRepositoryInterface
+}
+
+func NewGetUserProfileUseCase(userRepository domain.UserRepositoryInterface) *GetUserProfileUseCase {
+	return &GetUserProfileUseCase{
+		UserRepository: userRepository,
+	}
+}
+
+func (uc *GetUserProfileUseCase) Execute(ctx context.Context, userName string) (*domain.User, error) {
+	user, err := uc.UserRepository.GetUserByUserName(ctx, userName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.NewError(domain.ErrNotFound, fmt.Sprintf("user %s not found", userName))
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	return user, nil
+}
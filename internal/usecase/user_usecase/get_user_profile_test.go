@@ -0,0 +1,49 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}
+
+func Test_If_The_Profile_Is_Fetched_By_UserName(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucProfile := NewGetUserProfileUseCase(repository.NewUserRepository(db))
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "Go developer", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE lower\\(username\\)").WithArgs("eduardolima806").WillReturnRows(rows)
+
+	user, err := ucProfile.Execute(context.Background(), "eduardolima806")
+	assert.Nil(t, err)
+	assert.Equal(t, "Go developer", user.Bio)
+}
+
+func Test_If_Get_Not_Found_When_Profile_Does_Not_Exists(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucProfile := NewGetUserProfileUseCase(repository.NewUserRepository(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+	_, err := ucProfile.Execute(context.Background(), "nobody")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.EqualError(t, err, "user nobody not found")
+}
+
+func Test_If_Get_Internal_Error_When_Try_Fetch_Profile(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucProfile := NewGetUserProfileUseCase(repository.NewUserRepository(db))
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(errors.New("an internal error"))
+
+	_, err := ucProfile.Execute(context.Background(), "eduardolima806")
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+}
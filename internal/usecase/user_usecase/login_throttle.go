@@ -0,0 +1,77 @@
+package user_usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+// loginThrottle locks accounts and client IPs out after repeated failed
+// logins. The account lockout stops guessing the password of one user, the
+// IP lockout stops a client from guessing over many users.
+type loginThrottle struct {
+	store              domain.LoginAttemptStoreInterface
+	auditLogRepository domain.AuditLogRepositoryInterface
+	accountPolicy      domain.LockoutPolicy
+	ipPolicy           domain.LockoutPolicy
+}
+
+func accountKey(userID int32) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// retryAfter is how long key stays locked, zero when it is not.
+func (t *loginThrottle) retryAfter(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	attempt, err := t.store.Get(ctx, key)
+	if err != nil {
+		return 0, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch login attempts", err)
+	}
+	return attempt.RetryAfter(now), nil
+}
+
+// fail counts a failed login for key and locks it once the policy says so,
+// recording the lockout in the audit log. It returns the lockout, zero when
+// key is not locked.
+func (t *loginThrottle) fail(ctx context.Context, key string, policy domain.LockoutPolicy, userID *int32, ip string, now time.Time) (time.Duration, error) {
+	attempt, err := t.store.RecordFailure(ctx, key, now, policy.FailureWindow)
+	if err != nil {
+		return 0, domain.WrapError(domain.ErrInternalServerError, "could not possible to record login attempt", err)
+	}
+
+	lockout := policy.LockoutFor(attempt.Failures)
+	if lockout == 0 {
+		return 0, nil
+	}
+
+	if err := t.store.Lock(ctx, key, now.Add(lockout)); err != nil {
+		return 0, domain.WrapError(domain.ErrInternalServerError, "could not possible to lock login", err)
+	}
+
+	detail := fmt.Sprintf("%s locked for %s after %d failed logins", key, lockout, attempt.Failures)
+	if _, err := t.auditLogRepository.Save(ctx, domain.NewAuditLog(domain.AuditLoginLocked, userID, ip, detail)); err != nil {
+		return 0, domain.WrapError(domain.ErrInternalServerError, "could not possible to save audit log", err)
+	}
+
+	return lockout, nil
+}
+
+// failIP counts a failed login for the client IP, if it is known.
+func (t *loginThrottle) failIP(ctx context.Context, ip string, now time.Time) (time.Duration, error) {
+	if ip == "" {
+		return 0, nil
+	}
+	return t.fail(ctx, ipKey(ip), t.ipPolicy, nil, ip, now)
+}
+
+func (t *loginThrottle) reset(ctx context.Context, key string) error {
+	if err := t.store.Reset(ctx, key); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to reset login attempts", err)
+	}
+	return nil
+}
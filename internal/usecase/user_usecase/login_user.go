@@ -1,8 +1,10 @@
 package user_usecase
 
 import (
+	"context"
 	"database/sql"
 	"regexp"
+	"time"
 
 	"github.com/eduardolima806/my-chat-server/internal/domain"
 	"github.com/eduardolima806/my-chat-server/internal/util"
@@ -11,46 +13,103 @@ import (
 type LoginInput struct {
 	Login    string
 	Password string
+	// IP is the address of the client, used to lock it out after repeated
+	// failures. An empty IP is not tracked.
+	IP string
 }
 
 type LoginErrorType struct {
 	Code        uint8
 	Description string
+	// Reason names the error type in metrics.
+	Reason string
 }
 
 var (
-	UserLoginNotExists   = LoginErrorType{0, "user login does not exists"}
-	EmailNotExists       = LoginErrorType{1, "email does not exists"}
-	PasswordDoesNotMatch = LoginErrorType{2, "password does not match"}
+	UserLoginNotExists   = LoginErrorType{0, "user login does not exists", "user_login_not_exists"}
+	EmailNotExists       = LoginErrorType{1, "email does not exists", "email_not_exists"}
+	PasswordDoesNotMatch = LoginErrorType{2, "password does not match", "password_does_not_match"}
+	EmailNotVerified     = LoginErrorType{3, "email is not verified", "email_not_verified"}
+	TooManyAttempts      = LoginErrorType{4, "too many failed login attempts", "too_many_attempts"}
 )
 
 type LoginOuput struct {
 	IsSucceed bool
 	ErrorType LoginErrorType
+	Session   *SessionOutput
+	// RetryAfter is how long the client must wait before trying again, set
+	// along with TooManyAttempts.
+	RetryAfter time.Duration
 }
 
 type LoginUserUseCase struct {
 	UserRepository domain.UserRepositoryInterface
 	PasswordHasher util.PasswordHasher
+	// RequireVerifiedEmail refuses to log in users who did not verify the
+	// email of the account.
+	RequireVerifiedEmail bool
+	issuer               *sessionIssuer
+	throttle             *loginThrottle
+	metrics              *Metrics
 }
 
 type LoginUserUseCaseInterface interface {
-	Execute(input LoginInput) (*LoginOuput, error)
+	Execute(ctx context.Context, input LoginInput) (*LoginOuput, error)
 }
 
-func NewLoginUserUseCase(userRepo domain.UserRepositoryInterface, passwordHasher util.PasswordHasher) *LoginUserUseCase {
+func NewLoginUserUseCase(userRepo domain.UserRepositoryInterface, refreshTokenRepo domain.RefreshTokenRepositoryInterface, loginAttemptStore domain.LoginAttemptStoreInterface, auditLogRepo domain.AuditLogRepositoryInterface, passwordHasher util.PasswordHasher, tokenManager util.TokenManager, refreshTokenTTL time.Duration, requireVerifiedEmail bool, accountLockout domain.LockoutPolicy, ipLockout domain.LockoutPolicy, m *Metrics) *LoginUserUseCase {
 	return &LoginUserUseCase{
-		UserRepository: userRepo,
-		PasswordHasher: passwordHasher,
+		UserRepository:       userRepo,
+		PasswordHasher:       passwordHasher,
+		RequireVerifiedEmail: requireVerifiedEmail,
+		issuer: &sessionIssuer{
+			refreshTokenRepository: refreshTokenRepo,
+			tokenManager:           tokenManager,
+			refreshTokenTTL:        refreshTokenTTL,
+		},
+		throttle: &loginThrottle{
+			store:              loginAttemptStore,
+			auditLogRepository: auditLogRepo,
+			accountPolicy:      accountLockout,
+			ipPolicy:           ipLockout,
+		},
+		metrics: m,
 	}
 }
 
-func (uc *LoginUserUseCase) Execute(loginInput LoginInput) (*LoginOuput, error) {
+func (uc *LoginUserUseCase) Execute(ctx context.Context, loginInput LoginInput) (*LoginOuput, error) {
+	output, err := uc.login(ctx, loginInput)
+	if err == nil {
+		uc.metrics.loggedIn(output)
+	}
+	return output, err
+}
 
-	userToCheck, err := uc.UserRepository.GetUserByUserNameOrEmail(loginInput.Login)
+func (uc *LoginUserUseCase) login(ctx context.Context, loginInput LoginInput) (*LoginOuput, error) {
+	now := time.Now()
+
+	if loginInput.IP != "" {
+		retryAfter, err := uc.throttle.retryAfter(ctx, ipKey(loginInput.IP), now)
+		if err != nil {
+			return nil, err
+		}
+		if retryAfter > 0 {
+			return tooManyAttempts(retryAfter), nil
+		}
+	}
+
+	userToCheck, err := uc.UserRepository.GetUserByUserNameOrEmail(ctx, loginInput.Login)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
+			lockout, err := uc.throttle.failIP(ctx, loginInput.IP, now)
+			if err != nil {
+				return nil, err
+			}
+			if lockout > 0 {
+				return tooManyAttempts(lockout), nil
+			}
+
 			errType := UserLoginNotExists
 			if checkIsEmail(loginInput.Login) {
 				errType = EmailNotExists
@@ -60,23 +119,70 @@ func (uc *LoginUserUseCase) Execute(loginInput LoginInput) (*LoginOuput, error)
 				ErrorType: errType,
 			}, nil
 		} else {
-			return nil, domain.CreateError(domain.ErrInternalServerError.Error(), "could not possible to fetch user")
+			return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
 		}
 	}
 
-	if userToCheck != nil &&
-		!uc.PasswordHasher.VerifyPassword(loginInput.Password, userToCheck.Password) {
+	retryAfter, err := uc.throttle.retryAfter(ctx, accountKey(userToCheck.ID), now)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		return tooManyAttempts(retryAfter), nil
+	}
+
+	if !uc.PasswordHasher.VerifyPassword(loginInput.Password, userToCheck.Password) {
+		accountLockout, err := uc.throttle.fail(ctx, accountKey(userToCheck.ID), uc.throttle.accountPolicy, &userToCheck.ID, loginInput.IP, now)
+		if err != nil {
+			return nil, err
+		}
+		ipLockout, err := uc.throttle.failIP(ctx, loginInput.IP, now)
+		if err != nil {
+			return nil, err
+		}
+		if lockout := max(accountLockout, ipLockout); lockout > 0 {
+			return tooManyAttempts(lockout), nil
+		}
+
 		return &LoginOuput{
 			IsSucceed: false,
 			ErrorType: PasswordDoesNotMatch,
 		}, nil
 	}
 
+	// Only the account is reset: a client holding one valid password must
+	// not clear the failures of its IP on other accounts.
+	if err := uc.throttle.reset(ctx, accountKey(userToCheck.ID)); err != nil {
+		return nil, err
+	}
+
+	if uc.RequireVerifiedEmail && !userToCheck.IsEmailVerified() {
+		return &LoginOuput{
+			IsSucceed: false,
+			ErrorType: EmailNotVerified,
+		}, nil
+	}
+
+	session, err := uc.issuer.issue(ctx, userToCheck.ID, "")
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &LoginOuput{
 		IsSucceed: true,
+		Session:   session,
 	}, nil
 }
 
+func tooManyAttempts(retryAfter time.Duration) *LoginOuput {
+	return &LoginOuput{
+		IsSucceed:  false,
+		ErrorType:  TooManyAttempts,
+		RetryAfter: retryAfter,
+	}
+}
+
 func checkIsEmail(login string) bool {
 	emailRegex := regexp.MustCompile(domain.EmailRegex)
 	return emailRegex.Match([]byte(login))
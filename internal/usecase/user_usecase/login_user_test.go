@@ -1,40 +1,65 @@
 package user_usecase
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
 	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/infra/throttle"
 	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
+var testLockout = domain.LockoutPolicy{MaxFailures: 3, FailureWindow: time.Minute, BaseLockout: time.Minute, MaxLockout: time.Hour}
+
 func Test_If_UserName_Login_Success(t *testing.T) {
 	loginInput := LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001"}
 	db, mock, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
-	ucLogin := NewLoginUserUseCase(userRepository, &util.DefaultPasswordHasher{})
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
 
-	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "$2a$14$dI1.i3EBN4Zl0FuVj.gDdOA4QzN6Bg9DVrXlsQJCFemwrnTj8OPh.", time.Now())
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows)
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "$2a$14$dI1.i3EBN4Zl0FuVj.gDdOA4QzN6Bg9DVrXlsQJCFemwrnTj8OPh.", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows)
+	mock.ExpectQuery("INSERT INTO refresh_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
-	loginOutput, _ := ucLogin.Execute(loginInput)
+	loginOutput, _ := ucLogin.Execute(context.Background(), loginInput)
 	assert.NotNil(t, loginOutput)
 	assert.True(t, loginOutput.IsSucceed)
+	assert.NotEmpty(t, loginOutput.Session.AccessToken)
+	assert.NotEmpty(t, loginOutput.Session.RefreshToken)
+}
+
+func Test_If_Get_Error_When_Try_Save_Refresh_Token(t *testing.T) {
+	loginInput := LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001"}
+	db, mock, _ := sqlmock.New()
+	userRepository := repository.NewUserRepository(db)
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
+
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "$2a$14$dI1.i3EBN4Zl0FuVj.gDdOA4QzN6Bg9DVrXlsQJCFemwrnTj8OPh.", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows)
+	mock.ExpectQuery("INSERT INTO refresh_token").WillReturnError(errors.New("an internal error"))
+
+	loginOutput, err := ucLogin.Execute(context.Background(), loginInput)
+	assert.Nil(t, loginOutput)
+	assert.NotNil(t, err)
 }
 
 func Test_If_UserName_Login_Is_Empty_Not_Error(t *testing.T) {
 	loginInput := LoginInput{Login: "", Password: "P4$$w0rd001"}
 	db, mock, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
-	ucLogin := NewLoginUserUseCase(userRepository, &util.DefaultPasswordHasher{})
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
 
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 
-	_, err := ucLogin.Execute(loginInput)
+	_, err := ucLogin.Execute(context.Background(), loginInput)
 	assert.Nil(t, err)
 }
 
@@ -42,11 +67,11 @@ func Test_Error_When_UserName_Login_Not_Exists(t *testing.T) {
 	loginInput := LoginInput{Login: "eduardolima", Password: "P4$$w0rd001"}
 	db, mock, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
-	ucLogin := NewLoginUserUseCase(userRepository, &util.DefaultPasswordHasher{})
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
 
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 
-	loginOutput, _ := ucLogin.Execute(loginInput)
+	loginOutput, _ := ucLogin.Execute(context.Background(), loginInput)
 	assert.False(t, loginOutput.IsSucceed)
 	assert.Equal(t, UserLoginNotExists, loginOutput.ErrorType)
 }
@@ -55,11 +80,11 @@ func Test_If_Get_Error_When_Try_Fetch_User(t *testing.T) {
 	loginInput := LoginInput{Login: "eduardolima", Password: "P4$$w0rd001"}
 	db, mock, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
-	ucLogin := NewLoginUserUseCase(userRepository, &util.DefaultPasswordHasher{})
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
 
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(errors.New("an internal error"))
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(errors.New("an internal error"))
 
-	loginOutput, err := ucLogin.Execute(loginInput)
+	loginOutput, err := ucLogin.Execute(context.Background(), loginInput)
 	assert.Nil(t, loginOutput)
 	assert.NotNil(t, err)
 }
@@ -68,11 +93,11 @@ func Test_Error_When_Email_Login_Not_Exists(t *testing.T) {
 	loginInput := LoginInput{Login: "eduardolima.dev.io@gmail.com", Password: "P4$$w0rd001"}
 	db, mock, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
-	ucLogin := NewLoginUserUseCase(userRepository, &util.DefaultPasswordHasher{})
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
 
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnError(sql.ErrNoRows)
 
-	loginOutput, _ := ucLogin.Execute(loginInput)
+	loginOutput, _ := ucLogin.Execute(context.Background(), loginInput)
 	assert.False(t, loginOutput.IsSucceed)
 	assert.Equal(t, EmailNotExists, loginOutput.ErrorType)
 }
@@ -81,12 +106,171 @@ func Test_Error_When_Password_Does_Not_Match(t *testing.T) {
 	loginInput := LoginInput{Login: "eduardolima.dev.io@gmail.com", Password: "P4$$w0rd001Not"}
 	db, mock, _ := sqlmock.New()
 	userRepository := repository.NewUserRepository(db)
-	ucLogin := NewLoginUserUseCase(userRepository, &util.DefaultPasswordHasher{})
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
 
-	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "created"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "$2a$14$dI1.i3EBN4Zl0FuVj.gDdOA4QzN6Bg9DVrXlsQJCFemwrnTj8OPh.", time.Now())
-	mock.ExpectQuery("SELECT id, username, displayname, email, password, created FROM app_user").WillReturnRows(rows)
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "$2a$14$dI1.i3EBN4Zl0FuVj.gDdOA4QzN6Bg9DVrXlsQJCFemwrnTj8OPh.", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT id, username, displayname, email, password, bio, avatar_url, email_verified_at, created, password_changed_at FROM app_user").WillReturnRows(rows)
 
-	loginOutput, _ := ucLogin.Execute(loginInput)
+	loginOutput, _ := ucLogin.Execute(context.Background(), loginInput)
 	assert.False(t, loginOutput.IsSucceed)
 	assert.Equal(t, PasswordDoesNotMatch, loginOutput.ErrorType)
 }
+
+func Test_Error_When_Email_Is_Not_Verified_And_Verification_Is_Required(t *testing.T) {
+	loginInput := LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001"}
+	db, mock, _ := sqlmock.New()
+	userRepository := repository.NewUserRepository(db)
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, true, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
+
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "$2a$14$dI1.i3EBN4Zl0FuVj.gDdOA4QzN6Bg9DVrXlsQJCFemwrnTj8OPh.", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(rows)
+
+	loginOutput, _ := ucLogin.Execute(context.Background(), loginInput)
+	assert.False(t, loginOutput.IsSucceed)
+	assert.Equal(t, EmailNotVerified, loginOutput.ErrorType)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Verified_Email_Login_Success_When_Verification_Is_Required(t *testing.T) {
+	loginInput := LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001"}
+	db, mock, _ := sqlmock.New()
+	userRepository := repository.NewUserRepository(db)
+	ucLogin := NewLoginUserUseCase(userRepository, repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, true, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
+
+	rows := sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "$2a$14$dI1.i3EBN4Zl0FuVj.gDdOA4QzN6Bg9DVrXlsQJCFemwrnTj8OPh.", "", "", time.Now(), time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(rows)
+	mock.ExpectQuery("INSERT INTO refresh_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	loginOutput, _ := ucLogin.Execute(context.Background(), loginInput)
+	assert.True(t, loginOutput.IsSucceed)
+}
+
+func userRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "username", "displayname", "email", "password", "bio", "avatar_url", "email_verified_at", "created", "password_changed_at"}).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+}
+
+func Test_If_Account_Is_Locked_After_Repeated_Failures(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passwordHasher := &util.MockPasswordHasher{}
+	passwordHasher.On("VerifyPassword", "wrong", "hash").Return(false)
+	store := throttle.NewInMemoryStore()
+	ucLogin := NewLoginUserUseCase(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), store, repository.NewAuditLogRepository(db), passwordHasher, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, domain.LockoutPolicy{}, NewMetrics(prometheus.NewRegistry()))
+
+	for i := 1; i < testLockout.MaxFailures; i++ {
+		mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+		loginOutput, err := ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "wrong", IP: "10.0.0.1"})
+		assert.Nil(t, err)
+		assert.Equal(t, PasswordDoesNotMatch, loginOutput.ErrorType)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+	mock.ExpectQuery("INSERT INTO audit_log").WithArgs(domain.AuditLoginLocked, int32(1), "10.0.0.1", "user:1 locked for 1m0s after 3 failed logins", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	loginOutput, err := ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "wrong", IP: "10.0.0.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, TooManyAttempts, loginOutput.ErrorType)
+	assert.Equal(t, time.Minute, loginOutput.RetryAfter)
+
+	// The right password does not get through while the account is locked,
+	// whatever the IP.
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+	loginOutput, err = ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001", IP: "10.0.0.2"})
+	assert.Nil(t, err)
+	assert.Equal(t, TooManyAttempts, loginOutput.ErrorType)
+	assert.True(t, loginOutput.RetryAfter > 0)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Lockout_Doubles_After_It_Ends(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passwordHasher := &util.MockPasswordHasher{}
+	passwordHasher.On("VerifyPassword", "wrong", "hash").Return(false)
+	store := throttle.NewInMemoryStore()
+	ucLogin := NewLoginUserUseCase(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), store, repository.NewAuditLogRepository(db), passwordHasher, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, domain.LockoutPolicy{}, NewMetrics(prometheus.NewRegistry()))
+
+	// The account already failed up to a lockout, which is now over.
+	ended := time.Now().Add(-time.Second)
+	for i := 0; i < testLockout.MaxFailures; i++ {
+		store.RecordFailure(context.Background(), "user:1", ended.Add(-time.Minute), testLockout.FailureWindow)
+	}
+	store.Lock(context.Background(), "user:1", ended)
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+	mock.ExpectQuery("INSERT INTO audit_log").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	loginOutput, err := ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "wrong"})
+	assert.Nil(t, err)
+	assert.Equal(t, TooManyAttempts, loginOutput.ErrorType)
+	assert.Equal(t, 2*time.Minute, loginOutput.RetryAfter)
+}
+
+func Test_If_IP_Is_Locked_After_Failures_On_Several_Accounts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucLogin := NewLoginUserUseCase(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), &util.DefaultPasswordHasher{}, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, domain.LockoutPolicy{}, testLockout, NewMetrics(prometheus.NewRegistry()))
+
+	for i := 1; i < testLockout.MaxFailures; i++ {
+		mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+		loginOutput, _ := ucLogin.Execute(context.Background(), LoginInput{Login: "someone", Password: "wrong", IP: "10.0.0.1"})
+		assert.Equal(t, UserLoginNotExists, loginOutput.ErrorType)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO audit_log").WithArgs(domain.AuditLoginLocked, nil, "10.0.0.1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	loginOutput, _ := ucLogin.Execute(context.Background(), LoginInput{Login: "someone", Password: "wrong", IP: "10.0.0.1"})
+	assert.Equal(t, TooManyAttempts, loginOutput.ErrorType)
+
+	// A locked IP is refused before the user is even looked up.
+	loginOutput, err := ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001", IP: "10.0.0.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, TooManyAttempts, loginOutput.ErrorType)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Successful_Login_Resets_The_Account_Failures(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passwordHasher := &util.MockPasswordHasher{}
+	passwordHasher.On("VerifyPassword", "wrong", "hash").Return(false)
+	passwordHasher.On("VerifyPassword", "P4$$w0rd001", "hash").Return(true)
+	store := throttle.NewInMemoryStore()
+	ucLogin := NewLoginUserUseCase(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), store, repository.NewAuditLogRepository(db), passwordHasher, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, NewMetrics(prometheus.NewRegistry()))
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+	ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "wrong", IP: "10.0.0.1"})
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+	mock.ExpectQuery("INSERT INTO refresh_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	loginOutput, err := ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001", IP: "10.0.0.1"})
+	assert.Nil(t, err)
+	assert.True(t, loginOutput.IsSucceed)
+
+	account, _ := store.Get(context.Background(), "user:1")
+	assert.Equal(t, 0, account.Failures)
+	ip, _ := store.Get(context.Background(), "ip:10.0.0.1")
+	assert.Equal(t, 1, ip.Failures)
+}
+
+func Test_If_Logins_Are_Counted_By_Result(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passwordHasher := &util.MockPasswordHasher{}
+	passwordHasher.On("VerifyPassword", "wrong", "hash").Return(false)
+	passwordHasher.On("VerifyPassword", "P4$$w0rd001", "hash").Return(true)
+	userMetrics := NewMetrics(prometheus.NewRegistry())
+	ucLogin := NewLoginUserUseCase(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), throttle.NewInMemoryStore(), repository.NewAuditLogRepository(db), passwordHasher, util.NewJWTTokenManager("secret", time.Minute), time.Hour, false, testLockout, testLockout, userMetrics)
+
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+	ucLogin.Execute(context.Background(), LoginInput{Login: "nobody", Password: "wrong"})
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+	ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "wrong"})
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(userRows())
+	mock.ExpectQuery("INSERT INTO refresh_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001"})
+	// Errors are not login results.
+	mock.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(errors.New("connection refused"))
+	ucLogin.Execute(context.Background(), LoginInput{Login: "eduardolima806", Password: "P4$$w0rd001"})
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(userMetrics.loginsSucceeded))
+	assert.Equal(t, float64(1), testutil.ToFloat64(userMetrics.loginsFailed.WithLabelValues("password_does_not_match")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(userMetrics.loginsFailed.WithLabelValues("user_login_not_exists")))
+	// Only the two reasons seen have a series.
+	assert.Equal(t, 2, testutil.CollectAndCount(userMetrics.loginsFailed))
+}
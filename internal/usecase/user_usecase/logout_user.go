@@ -0,0 +1,59 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type LogoutInput struct {
+	UserID       int32
+	RefreshToken string
+}
+
+type LogoutUserUseCaseInterface interface {
+	Execute(ctx context.Context, input LogoutInput) error
+}
+
+type LogoutUserUseCase struct {
+	RefreshTokenRepository domain.RefreshTokenRepositoryInterface
+	EventPublisher         domain.EventPublisherInterface
+}
+
+func NewLogoutUserUseCase(refreshTokenRepository domain.RefreshTokenRepositoryInterface, eventPublisher domain.EventPublisherInterface) *LogoutUserUseCase {
+	return &LogoutUserUseCase{
+		RefreshTokenRepository: refreshTokenRepository,
+		EventPublisher:         eventPublisher,
+	}
+}
+
+func (uc *LogoutUserUseCase) Execute(ctx context.Context, input LogoutInput) error {
+	token, err := uc.RefreshTokenRepository.GetByTokenHash(ctx, util.HashToken(input.RefreshToken))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.NewError(domain.ErrUnauthorized, "refresh token is not valid")
+		}
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch refresh token", err)
+	}
+
+	if token.UserID != input.UserID {
+		return domain.NewError(domain.ErrUnauthorized, "refresh token is not valid")
+	}
+
+	if err = uc.RefreshTokenRepository.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to revoke refresh token", err)
+	}
+
+	// Realtime connections were authenticated once, at their handshake, so
+	// they are closed rather than left open past the logout. Access tokens do
+	// not name their session, so the connections of every session of the user
+	// go: the others reconnect with their still valid tokens.
+	if event, err := domain.NewEvent(domain.SessionRevokedEvent, domain.SessionRevokedPayload{Reason: domain.SessionRevokedByLogout}); err == nil {
+		uc.EventPublisher.SendToUsers([]int32{input.UserID}, event)
+	}
+
+	return nil
+}
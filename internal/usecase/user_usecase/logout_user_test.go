@@ -0,0 +1,60 @@
+package user_usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type publishedEvent struct {
+	userIDs []int32
+	event   domain.Event
+}
+
+type recordingPublisher struct {
+	events []publishedEvent
+}
+
+func (p *recordingPublisher) SendToUsers(userIDs []int32, event domain.Event) {
+	p.events = append(p.events, publishedEvent{userIDs: userIDs, event: event})
+}
+
+func Test_If_Logout_Revokes_The_Token_Family(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+	ucLogout := NewLogoutUserUseCase(repository.NewRefreshTokenRepository(db), publisher)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), nil, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WithArgs(util.HashToken("refresh")).WillReturnRows(rows)
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at").WithArgs("family", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := ucLogout.Execute(context.Background(), LogoutInput{UserID: 1, RefreshToken: "refresh"})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// Realtime connections of the user are closed on this event.
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, []int32{1}, publisher.events[0].userIDs)
+	assert.Equal(t, domain.SessionRevokedEvent, publisher.events[0].event.Type)
+	assert.JSONEq(t, `{"reason":"logout"}`, string(publisher.events[0].event.Payload))
+}
+
+func Test_If_Logout_Rejects_Token_Of_Another_User(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	publisher := &recordingPublisher{}
+	ucLogout := NewLogoutUserUseCase(repository.NewRefreshTokenRepository(db), publisher)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 2, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), nil, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnRows(rows)
+
+	err := ucLogout.Execute(context.Background(), LogoutInput{UserID: 1, RefreshToken: "refresh"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Nil(t, mock.ExpectationsWereMet())
+	assert.Empty(t, publisher.events)
+}
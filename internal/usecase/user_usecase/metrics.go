@@ -0,0 +1,32 @@
+package user_usecase
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics counts the signups and logins of the account use cases.
+type Metrics struct {
+	signups         prometheus.Counter
+	loginsSucceeded prometheus.Counter
+	loginsFailed    *prometheus.CounterVec
+}
+
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		signups:         prometheus.NewCounter(prometheus.CounterOpts{Name: "chat_signups_total", Help: "Accounts created."}),
+		loginsSucceeded: prometheus.NewCounter(prometheus.CounterOpts{Name: "chat_logins_succeeded_total", Help: "Logins that opened a session."}),
+		loginsFailed:    prometheus.NewCounterVec(prometheus.CounterOpts{Name: "chat_logins_failed_total", Help: "Logins refused, by reason."}, []string{"reason"}),
+	}
+	registerer.MustRegister(m.signups, m.loginsSucceeded, m.loginsFailed)
+	return m
+}
+
+func (m *Metrics) signedUp() {
+	m.signups.Inc()
+}
+
+func (m *Metrics) loggedIn(output *LoginOuput) {
+	if output.IsSucceed {
+		m.loginsSucceeded.Inc()
+		return
+	}
+	m.loginsFailed.WithLabelValues(output.ErrorType.Reason).Inc()
+}
@@ -0,0 +1,97 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type RefreshSessionInput struct {
+	RefreshToken string
+}
+
+type RefreshSessionUseCaseInterface interface {
+	Execute(ctx context.Context, input RefreshSessionInput) (*SessionOutput, error)
+}
+
+type RefreshSessionUseCase struct {
+	RefreshTokenRepository domain.RefreshTokenRepositoryInterface
+	UnitOfWork             domain.UnitOfWorkInterface
+	issuer                 *sessionIssuer
+}
+
+func NewRefreshSessionUseCase(refreshTokenRepository domain.RefreshTokenRepositoryInterface, tokenManager util.TokenManager, unitOfWork domain.UnitOfWorkInterface, refreshTokenTTL time.Duration) *RefreshSessionUseCase {
+	return &RefreshSessionUseCase{
+		RefreshTokenRepository: refreshTokenRepository,
+		UnitOfWork:             unitOfWork,
+		issuer: &sessionIssuer{
+			refreshTokenRepository: refreshTokenRepository,
+			tokenManager:           tokenManager,
+			refreshTokenTTL:        refreshTokenTTL,
+		},
+	}
+}
+
+func (uc *RefreshSessionUseCase) Execute(ctx context.Context, input RefreshSessionInput) (*SessionOutput, error) {
+	token, err := uc.RefreshTokenRepository.GetByTokenHash(ctx, util.HashToken(input.RefreshToken))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.NewError(domain.ErrUnauthorized, "refresh token is not valid")
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch refresh token", err)
+	}
+
+	if token.IsRevoked() {
+		return nil, domain.NewError(domain.ErrUnauthorized, "refresh token was revoked")
+	}
+
+	if token.IsRotated() {
+		return nil, uc.revokeReusedFamily(ctx, token)
+	}
+
+	if token.IsExpired(time.Now()) {
+		return nil, domain.NewError(domain.ErrUnauthorized, "refresh token is expired")
+	}
+
+	// The old token is only used up along with the new one being saved, so a
+	// failure in between does not log the user out.
+	var session *SessionOutput
+	var reused bool
+	err = uc.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		rotated, err := uc.RefreshTokenRepository.MarkAsRotated(ctx, token.ID)
+		if err != nil {
+			return err
+		}
+
+		// Another request rotated the same token first, so this one is a
+		// reuse.
+		reused = !rotated
+		if reused {
+			return nil
+		}
+
+		session, err = uc.issuer.issue(ctx, token.UserID, token.FamilyID)
+		return err
+	})
+
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to rotate refresh token", err)
+	}
+
+	if reused {
+		return nil, uc.revokeReusedFamily(ctx, token)
+	}
+
+	return session, nil
+}
+
+func (uc *RefreshSessionUseCase) revokeReusedFamily(ctx context.Context, token *domain.RefreshToken) error {
+	if err := uc.RefreshTokenRepository.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to revoke refresh token", err)
+	}
+	return domain.NewError(domain.ErrUnauthorized, "refresh token reuse detected, session revoked")
+}
@@ -0,0 +1,115 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+var refreshTokenColumns = []string{"id", "user_id", "family_id", "token_hash", "expires_at", "rotated_at", "revoked_at", "created"}
+
+func Test_If_Refresh_Token_Is_Rotated(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucRefresh := NewRefreshSessionUseCase(repository.NewRefreshTokenRepository(db), util.NewJWTTokenManager("secret", time.Minute), repository.NewUnitOfWork(db), time.Hour)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), nil, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WithArgs(util.HashToken("refresh")).WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE refresh_token SET rotated_at").WithArgs(int32(3), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO refresh_token").WithArgs(int32(1), "family", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectCommit()
+
+	session, err := ucRefresh.Execute(context.Background(), RefreshSessionInput{RefreshToken: "refresh"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, session.AccessToken)
+	assert.NotEqual(t, "refresh", session.RefreshToken)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Unauthorized_When_Refresh_Token_Does_Not_Exists(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucRefresh := NewRefreshSessionUseCase(repository.NewRefreshTokenRepository(db), util.NewJWTTokenManager("secret", time.Minute), repository.NewUnitOfWork(db), time.Hour)
+
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnError(sql.ErrNoRows)
+
+	_, err := ucRefresh.Execute(context.Background(), RefreshSessionInput{RefreshToken: "refresh"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func Test_If_Get_Unauthorized_When_Refresh_Token_Is_Expired(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucRefresh := NewRefreshSessionUseCase(repository.NewRefreshTokenRepository(db), util.NewJWTTokenManager("secret", time.Minute), repository.NewUnitOfWork(db), time.Hour)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(-time.Hour), nil, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnRows(rows)
+
+	_, err := ucRefresh.Execute(context.Background(), RefreshSessionInput{RefreshToken: "refresh"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Family_Is_Revoked_When_Rotated_Token_Is_Reused(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucRefresh := NewRefreshSessionUseCase(repository.NewRefreshTokenRepository(db), util.NewJWTTokenManager("secret", time.Minute), repository.NewUnitOfWork(db), time.Hour)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), time.Now(), nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnRows(rows)
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at").WithArgs("family", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	_, err := ucRefresh.Execute(context.Background(), RefreshSessionInput{RefreshToken: "refresh"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Family_Is_Revoked_When_Token_Is_Rotated_Concurrently(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucRefresh := NewRefreshSessionUseCase(repository.NewRefreshTokenRepository(db), util.NewJWTTokenManager("secret", time.Minute), repository.NewUnitOfWork(db), time.Hour)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), nil, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE refresh_token SET rotated_at").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at").WithArgs("family", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	_, err := ucRefresh.Execute(context.Background(), RefreshSessionInput{RefreshToken: "refresh"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Token_Is_Not_Rotated_When_The_New_One_Is_Not_Saved(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucRefresh := NewRefreshSessionUseCase(repository.NewRefreshTokenRepository(db), util.NewJWTTokenManager("secret", time.Minute), repository.NewUnitOfWork(db), time.Hour)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), nil, nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE refresh_token SET rotated_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO refresh_token").WillReturnError(errors.New("an internal error"))
+	mock.ExpectRollback()
+
+	session, err := ucRefresh.Execute(context.Background(), RefreshSessionInput{RefreshToken: "refresh"})
+	assert.Nil(t, session)
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Unauthorized_When_Refresh_Token_Is_Revoked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucRefresh := NewRefreshSessionUseCase(repository.NewRefreshTokenRepository(db), util.NewJWTTokenManager("secret", time.Minute), repository.NewUnitOfWork(db), time.Hour)
+
+	rows := sqlmock.NewRows(refreshTokenColumns).AddRow(3, 1, "family", util.HashToken("refresh"), time.Now().Add(time.Hour), nil, time.Now(), time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM refresh_token").WillReturnRows(rows)
+
+	_, err := ucRefresh.Execute(context.Background(), RefreshSessionInput{RefreshToken: "refresh"})
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,71 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type RequestPasswordResetUseCaseInterface interface {
+	Execute(ctx context.Context, email string) error
+}
+
+type RequestPasswordResetUseCase struct {
+	UserRepository               domain.UserRepositoryInterface
+	PasswordResetTokenRepository domain.PasswordResetTokenRepositoryInterface
+	Mailer                       util.Mailer
+	PasswordResetTTL             time.Duration
+}
+
+func NewRequestPasswordResetUseCase(userRepository domain.UserRepositoryInterface, passwordResetTokenRepository domain.PasswordResetTokenRepositoryInterface, mailer util.Mailer, passwordResetTTL time.Duration) *RequestPasswordResetUseCase {
+	return &RequestPasswordResetUseCase{
+		UserRepository:               userRepository,
+		PasswordResetTokenRepository: passwordResetTokenRepository,
+		Mailer:                       mailer,
+		PasswordResetTTL:             passwordResetTTL,
+	}
+}
+
+// Execute mails a reset token to the owner of email. An unknown email is not
+// an error, so the endpoint does not tell which emails have an account.
+func (uc *RequestPasswordResetUseCase) Execute(ctx context.Context, email string) error {
+	user, err := uc.UserRepository.GetUserByUserNameOrEmail(ctx, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	if !strings.EqualFold(user.Email, email) {
+		return nil
+	}
+
+	resetToken, err := util.GenerateSecureToken()
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to issue reset token", err)
+	}
+
+	token := domain.NewPasswordResetToken(user.ID, util.HashToken(resetToken), uc.PasswordResetTTL)
+
+	if _, err = uc.PasswordResetTokenRepository.Save(ctx, token); err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to save reset token", err)
+	}
+
+	err = uc.Mailer.Send(ctx, util.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use this token to choose a new password for %s. It expires in %s.\r\n\r\n%s",
+			user.UserName, uc.PasswordResetTTL, resetToken),
+	})
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to send reset mail", err)
+	}
+
+	return nil
+}
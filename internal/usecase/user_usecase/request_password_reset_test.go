@@ -0,0 +1,86 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRequestPasswordResetUseCase(db *sql.DB, mailer util.Mailer) *RequestPasswordResetUseCase {
+	return NewRequestPasswordResetUseCase(repository.NewUserRepository(db), repository.NewPasswordResetTokenRepository(db), mailer, time.Hour)
+}
+
+func Test_If_A_Reset_Token_Is_Mailed(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucRequest := newRequestPasswordResetUseCase(db, mailerMock)
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WithArgs("Eduardolima.dev.io@gmail.com").WillReturnRows(rows)
+	mockDb.ExpectQuery("INSERT INTO password_reset_token").WithArgs(int32(1), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var sent util.Mail
+	mailerMock.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).(util.Mail)
+	}).Return(nil)
+
+	err := ucRequest.Execute(context.Background(), "Eduardolima.dev.io@gmail.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "eduardolima.dev.io@gmail.com", sent.To)
+	assert.Nil(t, mockDb.ExpectationsWereMet())
+
+	// Only the hash of the mailed token is stored.
+	lines := strings.Split(sent.Body, "\r\n")
+	resetToken := lines[len(lines)-1]
+	assert.NotEmpty(t, resetToken)
+	assert.NotEqual(t, resetToken, util.HashToken(resetToken))
+}
+
+func Test_If_Nothing_Is_Mailed_For_An_Unknown_Email(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucRequest := newRequestPasswordResetUseCase(db, mailerMock)
+
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+	err := ucRequest.Execute(context.Background(), "nobody@gmail.com")
+	assert.Nil(t, err)
+	mailerMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func Test_If_Nothing_Is_Mailed_When_The_Login_Is_A_Username(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucRequest := newRequestPasswordResetUseCase(db, mailerMock)
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(rows)
+
+	err := ucRequest.Execute(context.Background(), "eduardolima806")
+	assert.Nil(t, err)
+	mailerMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func Test_If_Get_Internal_Error_When_The_Mail_Is_Not_Sent(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucRequest := newRequestPasswordResetUseCase(db, mailerMock)
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(rows)
+	mockDb.ExpectQuery("INSERT INTO password_reset_token").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mailerMock.On("Send", mock.Anything, mock.Anything).Return(errors.New("mail server is down"))
+
+	err := ucRequest.Execute(context.Background(), "eduardolima.dev.io@gmail.com")
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+}
@@ -0,0 +1,83 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type ResendEmailVerificationUseCaseInterface interface {
+	Execute(ctx context.Context, email string) error
+}
+
+type ResendEmailVerificationUseCase struct {
+	UserRepository                   domain.UserRepositoryInterface
+	EmailVerificationTokenRepository domain.EmailVerificationTokenRepositoryInterface
+	UnitOfWork                       domain.UnitOfWorkInterface
+	ResendInterval                   time.Duration
+	verifier                         *emailVerifier
+}
+
+func NewResendEmailVerificationUseCase(userRepository domain.UserRepositoryInterface, emailVerificationTokenRepository domain.EmailVerificationTokenRepositoryInterface, mailer util.Mailer, unitOfWork domain.UnitOfWorkInterface, emailVerificationTTL time.Duration, verifyEmailURL string, resendInterval time.Duration) *ResendEmailVerificationUseCase {
+	return &ResendEmailVerificationUseCase{
+		UserRepository:                   userRepository,
+		EmailVerificationTokenRepository: emailVerificationTokenRepository,
+		UnitOfWork:                       unitOfWork,
+		ResendInterval:                   resendInterval,
+		verifier: &emailVerifier{
+			tokenRepository: emailVerificationTokenRepository,
+			mailer:          mailer,
+			ttl:             emailVerificationTTL,
+			verifyURL:       verifyEmailURL,
+		},
+	}
+}
+
+// Execute mails a new verification token to the owner of email. Unknown or
+// verified emails and requests within ResendInterval of the last mail are
+// ignored without an error, so the endpoint neither tells which emails have
+// an account nor can be used to flood a mailbox.
+func (uc *ResendEmailVerificationUseCase) Execute(ctx context.Context, email string) error {
+	user, err := uc.UserRepository.GetUserByUserNameOrEmail(ctx, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	if !strings.EqualFold(user.Email, email) || user.IsEmailVerified() {
+		return nil
+	}
+
+	var verificationToken string
+	err = uc.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		verificationToken = ""
+
+		latest, err := uc.EmailVerificationTokenRepository.GetLatestByUser(ctx, user.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if latest != nil && time.Since(latest.Created) < uc.ResendInterval {
+			return nil
+		}
+
+		verificationToken, err = uc.verifier.issue(ctx, user.ID)
+		return err
+	})
+
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to issue verification token", err)
+	}
+
+	if verificationToken == "" {
+		return nil
+	}
+
+	return uc.verifier.send(ctx, user, verificationToken)
+}
@@ -0,0 +1,84 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newResendEmailVerificationUseCase(db *sql.DB, mailer util.Mailer) *ResendEmailVerificationUseCase {
+	return NewResendEmailVerificationUseCase(repository.NewUserRepository(db), repository.NewEmailVerificationTokenRepository(db), mailer, repository.NewUnitOfWork(db), time.Hour, verifyEmailURL, time.Minute)
+}
+
+func unverifiedUserRows() *sqlmock.Rows {
+	return sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+}
+
+func Test_If_The_Verification_Is_Resent(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucResend := newResendEmailVerificationUseCase(db, mailerMock)
+
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WithArgs("eduardolima.dev.io@gmail.com").WillReturnRows(unverifiedUserRows())
+	mockDb.ExpectBegin()
+	latest := sqlmock.NewRows(emailVerificationTokenColumns).AddRow(4, 1, "hash", time.Now().Add(time.Hour), nil, time.Now().Add(-2*time.Minute))
+	mockDb.ExpectQuery("SELECT (.+) FROM email_verification_token WHERE user_id").WithArgs(int32(1)).WillReturnRows(latest)
+	mockDb.ExpectQuery("INSERT INTO email_verification_token").WithArgs(int32(1), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mockDb.ExpectCommit()
+	mailerMock.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	err := ucResend.Execute(context.Background(), "eduardolima.dev.io@gmail.com")
+	assert.Nil(t, err)
+	mailerMock.AssertNumberOfCalls(t, "Send", 1)
+	assert.Nil(t, mockDb.ExpectationsWereMet())
+}
+
+func Test_If_The_Verification_Is_Not_Resent_Within_The_Interval(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucResend := newResendEmailVerificationUseCase(db, mailerMock)
+
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(unverifiedUserRows())
+	mockDb.ExpectBegin()
+	latest := sqlmock.NewRows(emailVerificationTokenColumns).AddRow(4, 1, "hash", time.Now().Add(time.Hour), nil, time.Now().Add(-10*time.Second))
+	mockDb.ExpectQuery("SELECT (.+) FROM email_verification_token WHERE user_id").WillReturnRows(latest)
+	mockDb.ExpectCommit()
+
+	err := ucResend.Execute(context.Background(), "eduardolima.dev.io@gmail.com")
+	assert.Nil(t, err)
+	mailerMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	assert.Nil(t, mockDb.ExpectationsWereMet())
+}
+
+func Test_If_The_Verification_Is_Not_Resent_To_A_Verified_Email(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucResend := newResendEmailVerificationUseCase(db, mailerMock)
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", time.Now(), time.Now(), nil)
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnRows(rows)
+
+	err := ucResend.Execute(context.Background(), "eduardolima.dev.io@gmail.com")
+	assert.Nil(t, err)
+	mailerMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	assert.Nil(t, mockDb.ExpectationsWereMet())
+}
+
+func Test_If_The_Verification_Is_Not_Resent_To_An_Unknown_Email(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	mailerMock := &util.MockMailer{}
+	ucResend := newResendEmailVerificationUseCase(db, mailerMock)
+
+	mockDb.ExpectQuery("SELECT (.+) FROM app_user").WillReturnError(sql.ErrNoRows)
+
+	err := ucResend.Execute(context.Background(), "nobody@gmail.com")
+	assert.Nil(t, err)
+	mailerMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,112 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type ResetPasswordInput struct {
+	Token       string
+	NewPassword string
+}
+
+type ResetPasswordUseCaseInterface interface {
+	Execute(ctx context.Context, input ResetPasswordInput) error
+}
+
+type ResetPasswordUseCase struct {
+	UserRepository               domain.UserRepositoryInterface
+	PasswordResetTokenRepository domain.PasswordResetTokenRepositoryInterface
+	RefreshTokenRepository       domain.RefreshTokenRepositoryInterface
+	PasswordHasher               util.PasswordHasher
+	UnitOfWork                   domain.UnitOfWorkInterface
+	EventPublisher               domain.EventPublisherInterface
+}
+
+func NewResetPasswordUseCase(userRepository domain.UserRepositoryInterface, passwordResetTokenRepository domain.PasswordResetTokenRepositoryInterface, refreshTokenRepository domain.RefreshTokenRepositoryInterface, passwordHasher util.PasswordHasher, unitOfWork domain.UnitOfWorkInterface, eventPublisher domain.EventPublisherInterface) *ResetPasswordUseCase {
+	return &ResetPasswordUseCase{
+		UserRepository:               userRepository,
+		PasswordResetTokenRepository: passwordResetTokenRepository,
+		RefreshTokenRepository:       refreshTokenRepository,
+		PasswordHasher:               passwordHasher,
+		UnitOfWork:                   unitOfWork,
+		EventPublisher:               eventPublisher,
+	}
+}
+
+// Execute sets the new password, uses up every pending reset token of the
+// user and ends all of the user's sessions: refresh tokens are revoked,
+// access tokens issued until now are rejected and realtime connections are
+// closed.
+func (uc *ResetPasswordUseCase) Execute(ctx context.Context, input ResetPasswordInput) error {
+	if err := domain.ValidatePassword(input.NewPassword); err != nil {
+		return domain.NewFieldError(domain.ErrBadRequest, err.Error(),
+			domain.FieldError{Field: "newPassword", Message: "is not secure"})
+	}
+
+	token, err := uc.PasswordResetTokenRepository.GetByTokenHash(ctx, util.HashToken(input.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errInvalidResetToken()
+		}
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch reset token", err)
+	}
+
+	if token.IsUsed() || token.IsExpired(time.Now()) {
+		return errInvalidResetToken()
+	}
+
+	// The new hash is ready before the token is claimed, so the serializable
+	// transaction below holds the token row only for the updates themselves.
+	passwordHash, err := uc.PasswordHasher.HashPassword(input.NewPassword)
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to hash password", err)
+	}
+
+	err = uc.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		used, err := uc.PasswordResetTokenRepository.MarkAsUsed(ctx, token.ID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errInvalidResetToken()
+		}
+
+		if err := uc.UserRepository.UpdatePassword(ctx, token.UserID, passwordHash); err != nil {
+			return err
+		}
+
+		if err := uc.UserRepository.MarkPasswordAsChanged(ctx, token.UserID, time.Now()); err != nil {
+			return err
+		}
+
+		if err := uc.PasswordResetTokenRepository.InvalidateForUser(ctx, token.UserID); err != nil {
+			return err
+		}
+
+		return uc.RefreshTokenRepository.RevokeAllForUser(ctx, token.UserID)
+	})
+
+	if errors.Is(err, domain.ErrBadRequest) {
+		return err
+	}
+
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to reset password", err)
+	}
+
+	if event, err := domain.NewEvent(domain.SessionRevokedEvent, domain.SessionRevokedPayload{Reason: domain.SessionRevokedByPasswordReset}); err == nil {
+		uc.EventPublisher.SendToUsers([]int32{token.UserID}, event)
+	}
+
+	return nil
+}
+
+func errInvalidResetToken() error {
+	return domain.NewError(domain.ErrBadRequest, "reset token is not valid")
+}
@@ -0,0 +1,108 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+var passwordResetTokenColumns = []string{"id", "user_id", "token_hash", "expires_at", "used_at", "created"}
+
+func newResetPasswordUseCase(db *sql.DB, passwordHasher util.PasswordHasher) *ResetPasswordUseCase {
+	return NewResetPasswordUseCase(repository.NewUserRepository(db), repository.NewPasswordResetTokenRepository(db), repository.NewRefreshTokenRepository(db), passwordHasher, repository.NewUnitOfWork(db), &recordingPublisher{})
+}
+
+func Test_If_The_Password_Is_Reset_And_Sessions_Are_Revoked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passHasherMock := &util.MockPasswordHasher{}
+	ucReset := newResetPasswordUseCase(db, passHasherMock)
+	publisher := &recordingPublisher{}
+	ucReset.EventPublisher = publisher
+
+	rows := sqlmock.NewRows(passwordResetTokenColumns).AddRow(3, 1, util.HashToken("reset"), time.Now().Add(time.Hour), nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM password_reset_token").WithArgs(util.HashToken("reset")).WillReturnRows(rows)
+	passHasherMock.On("HashPassword", "N3w$$w0rd").Return("newHash", nil)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE password_reset_token SET used_at (.+) WHERE id").WithArgs(int32(3), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE app_user SET password =").WithArgs(int32(1), "newHash").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE app_user SET password_changed_at").WithArgs(int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE password_reset_token SET used_at (.+) WHERE user_id").WithArgs(int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE refresh_token SET revoked_at (.+) WHERE user_id").WithArgs(int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := ucReset.Execute(context.Background(), ResetPasswordInput{Token: "reset", NewPassword: "N3w$$w0rd"})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// Realtime connections of the user are closed as well.
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, []int32{1}, publisher.events[0].userIDs)
+	assert.Equal(t, domain.SessionRevokedEvent, publisher.events[0].event.Type)
+	assert.JSONEq(t, `{"reason":"password_reset"}`, string(publisher.events[0].event.Payload))
+}
+
+func Test_If_Get_Error_When_Reset_Token_Is_Not_Valid(t *testing.T) {
+	usedAt := time.Now()
+
+	testsCases := map[string]*sqlmock.Rows{
+		"expired": sqlmock.NewRows(passwordResetTokenColumns).AddRow(3, 1, util.HashToken("reset"), time.Now().Add(-time.Minute), nil, time.Now()),
+		"used":    sqlmock.NewRows(passwordResetTokenColumns).AddRow(3, 1, util.HashToken("reset"), time.Now().Add(time.Hour), usedAt, time.Now()),
+	}
+
+	for name, rows := range testsCases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			ucReset := newResetPasswordUseCase(db, &util.MockPasswordHasher{})
+
+			mock.ExpectQuery("SELECT (.+) FROM password_reset_token").WillReturnRows(rows)
+
+			err := ucReset.Execute(context.Background(), ResetPasswordInput{Token: "reset", NewPassword: "N3w$$w0rd"})
+			assert.ErrorIs(t, err, domain.ErrBadRequest)
+			assert.EqualError(t, err, "reset token is not valid")
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		ucReset := newResetPasswordUseCase(db, &util.MockPasswordHasher{})
+
+		mock.ExpectQuery("SELECT (.+) FROM password_reset_token").WillReturnError(sql.ErrNoRows)
+
+		err := ucReset.Execute(context.Background(), ResetPasswordInput{Token: "reset", NewPassword: "N3w$$w0rd"})
+		assert.EqualError(t, err, "reset token is not valid")
+	})
+}
+
+func Test_If_Get_Error_When_Reset_Token_Is_Used_Concurrently(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	passHasherMock := &util.MockPasswordHasher{}
+	ucReset := newResetPasswordUseCase(db, passHasherMock)
+
+	rows := sqlmock.NewRows(passwordResetTokenColumns).AddRow(3, 1, util.HashToken("reset"), time.Now().Add(time.Hour), nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM password_reset_token").WillReturnRows(rows)
+	passHasherMock.On("HashPassword", "N3w$$w0rd").Return("newHash", nil)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE password_reset_token SET used_at (.+) WHERE id").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := ucReset.Execute(context.Background(), ResetPasswordInput{Token: "reset", NewPassword: "N3w$$w0rd"})
+	assert.EqualError(t, err, "reset token is not valid")
+	assert.Nil(t, mock.ExpectationsWereMet())
+	assert.Empty(t, ucReset.EventPublisher.(*recordingPublisher).events)
+}
+
+func Test_If_Get_Error_When_Reset_Password_Is_Not_Secure(t *testing.T) {
+	ucReset := newResetPasswordUseCase(nil, &util.MockPasswordHasher{})
+
+	err := ucReset.Execute(context.Background(), ResetPasswordInput{Token: "reset", NewPassword: "weak"})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+	assert.EqualError(t, err, "password is not secure")
+}
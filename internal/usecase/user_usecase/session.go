@@ -0,0 +1,56 @@
+package user_usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type SessionOutput struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+type sessionIssuer struct {
+	refreshTokenRepository domain.RefreshTokenRepositoryInterface
+	tokenManager           util.TokenManager
+	refreshTokenTTL        time.Duration
+}
+
+// issue creates an access token and a refresh token for the user. An empty
+// familyID starts a new refresh token family (a new login).
+func (s *sessionIssuer) issue(ctx context.Context, userID int32, familyID string) (*SessionOutput, error) {
+	accessToken, accessTokenExpiresAt, err := s.tokenManager.GenerateToken(userID)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to issue access token", err)
+	}
+
+	if familyID == "" {
+		familyID, err = util.GenerateSecureToken()
+		if err != nil {
+			return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to issue refresh token", err)
+		}
+	}
+
+	refreshToken, err := util.GenerateSecureToken()
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to issue refresh token", err)
+	}
+
+	token := domain.NewRefreshToken(userID, familyID, util.HashToken(refreshToken), s.refreshTokenTTL)
+
+	if _, err = s.refreshTokenRepository.Save(ctx, token); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to save refresh token", err)
+	}
+
+	return &SessionOutput{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: token.ExpiresAt,
+	}, nil
+}
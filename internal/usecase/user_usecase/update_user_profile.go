@@ -0,0 +1,54 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+)
+
+type UpdateProfileInput struct {
+	UserID      int32
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
+}
+
+type UpdateUserProfileUseCaseInterface interface {
+	Execute(ctx context.Context, input UpdateProfileInput) (*domain.User, error)
+}
+
+type UpdateUserProfileUseCase struct {
+	UserRepository domain.UserRepositoryInterface
+}
+
+func NewUpdateUserProfileUseCase(userRepository domain.UserRepositoryInterface) *UpdateUserProfileUseCase {
+	return &UpdateUserProfileUseCase{
+		UserRepository: userRepository,
+	}
+}
+
+func (uc *UpdateUserProfileUseCase) Execute(ctx context.Context, input UpdateProfileInput) (*domain.User, error) {
+	user, err := uc.UserRepository.GetUserById(ctx, input.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.NewError(domain.ErrNotFound, "user does not exists")
+		}
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch user", err)
+	}
+
+	err = user.UpdateProfile(domain.ProfileUpdate{
+		DisplayName: input.DisplayName,
+		Bio:         input.Bio,
+		AvatarURL:   input.AvatarURL,
+	})
+	if err != nil {
+		return nil, domain.NewError(domain.ErrBadRequest, err.Error())
+	}
+
+	if err = uc.UserRepository.UpdateProfile(ctx, user); err != nil {
+		return nil, domain.WrapError(domain.ErrInternalServerError, "could not possible to update user profile", err)
+	}
+
+	return user, nil
+}
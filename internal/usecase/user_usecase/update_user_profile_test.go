@@ -0,0 +1,55 @@
+package user_usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_The_Profile_Is_Updated(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucUpdate := NewUpdateUserProfileUseCase(repository.NewUserRepository(db))
+	bio := "Go developer"
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WithArgs(int32(1)).WillReturnRows(rows)
+	mock.ExpectExec("UPDATE app_user SET displayname").WithArgs(int32(1), "Eduardo Lima", bio, "").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	user, err := ucUpdate.Execute(context.Background(), UpdateProfileInput{UserID: 1, Bio: &bio})
+	assert.Nil(t, err)
+	assert.Equal(t, bio, user.Bio)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Error_To_Update_Invalid_Profile(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucUpdate := NewUpdateUserProfileUseCase(repository.NewUserRepository(db))
+	avatarURL := "ftp://cdn.example.com/eduardo.png"
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WillReturnRows(rows)
+
+	_, err := ucUpdate.Execute(context.Background(), UpdateProfileInput{UserID: 1, AvatarURL: &avatarURL})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+	assert.EqualError(t, err, "avatar url must be an http or https url")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Internal_Error_When_Try_Update_Profile(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucUpdate := NewUpdateUserProfileUseCase(repository.NewUserRepository(db))
+	bio := "Go developer"
+
+	rows := sqlmock.NewRows(userColumns).AddRow(1, "eduardolima806", "Eduardo Lima", "eduardolima.dev.io@gmail.com", "hash", "", "", nil, time.Now(), nil)
+	mock.ExpectQuery("SELECT (.+) FROM app_user WHERE id").WillReturnRows(rows)
+	mock.ExpectExec("UPDATE app_user SET displayname").WillReturnError(errors.New("an internal error"))
+
+	_, err := ucUpdate.Execute(context.Background(), UpdateProfileInput{UserID: 1, Bio: &bio})
+	assert.ErrorIs(t, err, domain.ErrInternalServerError)
+}
@@ -1,18 +1,57 @@
 package user_usecase
 
 import (
+	"time"
+
 	"github.com/eduardolima806/my-chat-server/internal/domain"
 	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/eduardolima806/my-chat-server/pkg/logger"
 )
 
+// UserSettings tunes the account use cases.
+type UserSettings struct {
+	RefreshTokenTTL            time.Duration
+	PasswordResetTTL           time.Duration
+	EmailVerificationTTL       time.Duration
+	VerificationResendInterval time.Duration
+	// VerifyEmailURL is the address of the verify-email endpoint mailed to
+	// users.
+	VerifyEmailURL       string
+	RequireVerifiedEmail bool
+	// AccountLockout and IPLockout lock out, respectively, an account and a
+	// client IP after repeated failed logins.
+	AccountLockout domain.LockoutPolicy
+	IPLockout      domain.LockoutPolicy
+}
+
 type UserBaseUserCase struct {
-	CreateUserUseCase CreateUserUseCaseInterface
-	LoginUserUseCase  LoginUserUseCaseInterface
+	CreateUserUseCase              CreateUserUseCaseInterface
+	LoginUserUseCase               LoginUserUseCaseInterface
+	AuthenticateUserUseCase        AuthenticateUserUseCaseInterface
+	RefreshSessionUseCase          RefreshSessionUseCaseInterface
+	LogoutUserUseCase              LogoutUserUseCaseInterface
+	GetUserProfileUseCase          GetUserProfileUseCaseInterface
+	UpdateUserProfileUseCase       UpdateUserProfileUseCaseInterface
+	ChangePasswordUseCase          ChangePasswordUseCaseInterface
+	RequestPasswordResetUseCase    RequestPasswordResetUseCaseInterface
+	ResetPasswordUseCase           ResetPasswordUseCaseInterface
+	VerifyEmailUseCase             VerifyEmailUseCaseInterface
+	ResendEmailVerificationUseCase ResendEmailVerificationUseCaseInterface
 }
 
-func NewUserBaseUserCase(userRepository domain.UserRepositoryInterface, passwordHasher util.PasswordHasher) *UserBaseUserCase {
+func NewUserBaseUserCase(userRepository domain.UserRepositoryInterface, refreshTokenRepository domain.RefreshTokenRepositoryInterface, passwordResetTokenRepository domain.PasswordResetTokenRepositoryInterface, emailVerificationTokenRepository domain.EmailVerificationTokenRepositoryInterface, loginAttemptStore domain.LoginAttemptStoreInterface, auditLogRepository domain.AuditLogRepositoryInterface, passwordHasher util.PasswordHasher, tokenManager util.TokenManager, mailer util.Mailer, unitOfWork domain.UnitOfWorkInterface, eventPublisher domain.EventPublisherInterface, settings UserSettings, m *Metrics, l logger.Interface) *UserBaseUserCase {
 	return &UserBaseUserCase{
-		CreateUserUseCase: NewCreateUserUseCase(userRepository, passwordHasher),
-		LoginUserUseCase:  NewLoginUserUseCase(userRepository, passwordHasher),
+		CreateUserUseCase:              NewCreateUserUseCase(userRepository, emailVerificationTokenRepository, passwordHasher, mailer, unitOfWork, settings.EmailVerificationTTL, settings.VerifyEmailURL, m, l),
+		LoginUserUseCase:               NewLoginUserUseCase(userRepository, refreshTokenRepository, loginAttemptStore, auditLogRepository, passwordHasher, tokenManager, settings.RefreshTokenTTL, settings.RequireVerifiedEmail, settings.AccountLockout, settings.IPLockout, m),
+		AuthenticateUserUseCase:        NewAuthenticateUserUseCase(userRepository, tokenManager),
+		RefreshSessionUseCase:          NewRefreshSessionUseCase(refreshTokenRepository, tokenManager, unitOfWork, settings.RefreshTokenTTL),
+		LogoutUserUseCase:              NewLogoutUserUseCase(refreshTokenRepository, eventPublisher),
+		GetUserProfileUseCase:          NewGetUserProfileUseCase(userRepository),
+		UpdateUserProfileUseCase:       NewUpdateUserProfileUseCase(userRepository),
+		ChangePasswordUseCase:          NewChangePasswordUseCase(userRepository, passwordHasher),
+		RequestPasswordResetUseCase:    NewRequestPasswordResetUseCase(userRepository, passwordResetTokenRepository, mailer, settings.PasswordResetTTL),
+		ResetPasswordUseCase:           NewResetPasswordUseCase(userRepository, passwordResetTokenRepository, refreshTokenRepository, passwordHasher, unitOfWork, eventPublisher),
+		VerifyEmailUseCase:             NewVerifyEmailUseCase(userRepository, emailVerificationTokenRepository, unitOfWork),
+		ResendEmailVerificationUseCase: NewResendEmailVerificationUseCase(userRepository, emailVerificationTokenRepository, mailer, unitOfWork, settings.EmailVerificationTTL, settings.VerifyEmailURL, settings.VerificationResendInterval),
 	}
 }
@@ -0,0 +1,75 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+)
+
+type VerifyEmailUseCaseInterface interface {
+	Execute(ctx context.Context, verificationToken string) error
+}
+
+type VerifyEmailUseCase struct {
+	UserRepository                   domain.UserRepositoryInterface
+	EmailVerificationTokenRepository domain.EmailVerificationTokenRepositoryInterface
+	UnitOfWork                       domain.UnitOfWorkInterface
+}
+
+func NewVerifyEmailUseCase(userRepository domain.UserRepositoryInterface, emailVerificationTokenRepository domain.EmailVerificationTokenRepositoryInterface, unitOfWork domain.UnitOfWorkInterface) *VerifyEmailUseCase {
+	return &VerifyEmailUseCase{
+		UserRepository:                   userRepository,
+		EmailVerificationTokenRepository: emailVerificationTokenRepository,
+		UnitOfWork:                       unitOfWork,
+	}
+}
+
+// Execute marks the email of the token owner as verified and uses up every
+// other verification token mailed to the user.
+func (uc *VerifyEmailUseCase) Execute(ctx context.Context, verificationToken string) error {
+	token, err := uc.EmailVerificationTokenRepository.GetByTokenHash(ctx, util.HashToken(verificationToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errInvalidVerificationToken()
+		}
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to fetch verification token", err)
+	}
+
+	if token.IsUsed() || token.IsExpired(time.Now()) {
+		return errInvalidVerificationToken()
+	}
+
+	err = uc.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		used, err := uc.EmailVerificationTokenRepository.MarkAsUsed(ctx, token.ID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errInvalidVerificationToken()
+		}
+
+		if err := uc.UserRepository.MarkEmailAsVerified(ctx, token.UserID); err != nil {
+			return err
+		}
+
+		return uc.EmailVerificationTokenRepository.InvalidateForUser(ctx, token.UserID)
+	})
+
+	if errors.Is(err, domain.ErrBadRequest) {
+		return err
+	}
+
+	if err != nil {
+		return domain.WrapError(domain.ErrInternalServerError, "could not possible to verify email", err)
+	}
+
+	return nil
+}
+
+func errInvalidVerificationToken() error {
+	return domain.NewError(domain.ErrBadRequest, "verification token is not valid")
+}
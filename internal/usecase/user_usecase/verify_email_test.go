@@ -0,0 +1,70 @@
+package user_usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/eduardolima806/my-chat-server/internal/domain"
+	"github.com/eduardolima806/my-chat-server/internal/infra/repository"
+	"github.com/eduardolima806/my-chat-server/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+var emailVerificationTokenColumns = []string{"id", "user_id", "token_hash", "expires_at", "used_at", "created"}
+
+func newVerifyEmailUseCase(db *sql.DB) *VerifyEmailUseCase {
+	return NewVerifyEmailUseCase(repository.NewUserRepository(db), repository.NewEmailVerificationTokenRepository(db), repository.NewUnitOfWork(db))
+}
+
+func Test_If_The_Email_Is_Verified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	ucVerify := newVerifyEmailUseCase(db)
+
+	rows := sqlmock.NewRows(emailVerificationTokenColumns).AddRow(4, 1, util.HashToken("verify"), time.Now().Add(time.Hour), nil, time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM email_verification_token WHERE token_hash").WithArgs(util.HashToken("verify")).WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE email_verification_token SET used_at (.+) WHERE id").WithArgs(int32(4), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE app_user SET email_verified_at").WithArgs(int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE email_verification_token SET used_at (.+) WHERE user_id").WithArgs(int32(1), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := ucVerify.Execute(context.Background(), "verify")
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_If_Get_Error_When_Verification_Token_Is_Not_Valid(t *testing.T) {
+	usedAt := time.Now()
+
+	testsCases := map[string]*sqlmock.Rows{
+		"expired": sqlmock.NewRows(emailVerificationTokenColumns).AddRow(4, 1, util.HashToken("verify"), time.Now().Add(-time.Minute), nil, time.Now()),
+		"used":    sqlmock.NewRows(emailVerificationTokenColumns).AddRow(4, 1, util.HashToken("verify"), time.Now().Add(time.Hour), usedAt, time.Now()),
+	}
+
+	for name, rows := range testsCases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			ucVerify := newVerifyEmailUseCase(db)
+
+			mock.ExpectQuery("SELECT (.+) FROM email_verification_token").WillReturnRows(rows)
+
+			err := ucVerify.Execute(context.Background(), "verify")
+			assert.ErrorIs(t, err, domain.ErrBadRequest)
+			assert.EqualError(t, err, "verification token is not valid")
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		ucVerify := newVerifyEmailUseCase(db)
+
+		mock.ExpectQuery("SELECT (.+) FROM email_verification_token").WillReturnError(sql.ErrNoRows)
+
+		err := ucVerify.Execute(context.Background(), "verify")
+		assert.EqualError(t, err, "verification token is not valid")
+	})
+}
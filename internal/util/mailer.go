@@ -0,0 +1,72 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers mails to users. There is no SMTP implementation yet: the
+// log and file mailers let mails be read locally.
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// LogMailer writes every mail to Out.
+type LogMailer struct {
+	From string
+	Out  io.Writer
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{
+		From: from,
+		Out:  os.Stdout,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, mail Mail) error {
+	_, err := io.WriteString(m.Out, formatMail(m.From, mail, time.Now()))
+	return err
+}
+
+// FileMailer writes every mail to a file of its own in Dir.
+type FileMailer struct {
+	From string
+	Dir  string
+}
+
+func NewFileMailer(from string, dir string) *FileMailer {
+	return &FileMailer{
+		From: from,
+		Dir:  dir,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, mail Mail) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(m.Dir, "mail-*.eml")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.WriteString(file, formatMail(m.From, mail, time.Now()))
+	return err
+}
+
+func formatMail(from string, mail Mail, date time.Time) string {
+	return fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		from, mail.To, mail.Subject, date.Format(time.RFC1123Z), mail.Body)
+}
@@ -0,0 +1,16 @@
+package util
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(arg1 context.Context, arg2 Mail) error {
+	args := m.Called(arg1, arg2)
+	return args.Error(0)
+}
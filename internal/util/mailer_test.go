@@ -0,0 +1,45 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var resetMail = Mail{
+	To:      "eduardolima.dev.io@gmail.com",
+	Subject: "Reset your password",
+	Body:    "token",
+}
+
+func Test_If_Log_Mailer_Writes_The_Mail(t *testing.T) {
+	var out bytes.Buffer
+	mailer := &LogMailer{From: "no-reply@chat.local", Out: &out}
+
+	err := mailer.Send(context.Background(), resetMail)
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "From: no-reply@chat.local\r\n")
+	assert.Contains(t, out.String(), "To: eduardolima.dev.io@gmail.com\r\n")
+	assert.Contains(t, out.String(), "Subject: Reset your password\r\n")
+	assert.Contains(t, out.String(), "\r\n\r\ntoken\r\n")
+}
+
+func Test_If_File_Mailer_Writes_A_File_Per_Mail(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	mailer := NewFileMailer("no-reply@chat.local", dir)
+
+	assert.Nil(t, mailer.Send(context.Background(), resetMail))
+	assert.Nil(t, mailer.Send(context.Background(), resetMail))
+
+	files, err := filepath.Glob(filepath.Join(dir, "mail-*.eml"))
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+
+	content, err := os.ReadFile(files[0])
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "To: eduardolima.dev.io@gmail.com\r\n")
+}
@@ -1,6 +1,11 @@
 package util
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/bcrypt"
+)
 
 type PasswordHasher interface {
 	HashPassword(password string) (string, error)
@@ -21,3 +26,41 @@ func (p *DefaultPasswordHasher) VerifyPassword(password string, hash string) boo
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// hashingBuckets suit bcrypt, whose cost makes hashing take up to seconds.
+var hashingBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 4, 8}
+
+// TimedPasswordHasher observes how long Hasher takes to hash and verify
+// passwords, as bcrypt is slow on purpose and its cost must suit the host.
+type TimedPasswordHasher struct {
+	Hasher   PasswordHasher
+	duration *prometheus.HistogramVec
+}
+
+func NewTimedPasswordHasher(hasher PasswordHasher, registerer prometheus.Registerer) *TimedPasswordHasher {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chat_password_hashing_duration_seconds",
+		Help:    "Time taken to hash and verify passwords, by operation.",
+		Buckets: hashingBuckets,
+	}, []string{"operation"})
+	registerer.MustRegister(duration)
+
+	return &TimedPasswordHasher{
+		Hasher:   hasher,
+		duration: duration,
+	}
+}
+
+func (p *TimedPasswordHasher) HashPassword(password string) (string, error) {
+	defer p.observe("hash", time.Now())
+	return p.Hasher.HashPassword(password)
+}
+
+func (p *TimedPasswordHasher) VerifyPassword(password string, hash string) bool {
+	defer p.observe("verify", time.Now())
+	return p.Hasher.VerifyPassword(password, hash)
+}
+
+func (p *TimedPasswordHasher) observe(operation string, start time.Time) {
+	p.duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
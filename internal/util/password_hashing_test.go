@@ -3,6 +3,7 @@ package util
 import (
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,3 +20,38 @@ func Test_If_Password_Matched_With_Hash(t *testing.T) {
 	hash, _ := passHasher.HashPassword(pass)
 	assert.True(t, passHasher.VerifyPassword(pass, hash))
 }
+
+func Test_If_Password_Hashing_Is_Timed(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	hasherMock := &MockPasswordHasher{}
+	hasherMock.On("HashPassword", "P4$$w0rd").Return("hash", nil)
+	hasherMock.On("VerifyPassword", "P4$$w0rd", "hash").Return(true)
+	timedHasher := NewTimedPasswordHasher(hasherMock, registry)
+
+	hash, err := timedHasher.HashPassword("P4$$w0rd")
+	assert.Nil(t, err)
+	assert.True(t, timedHasher.VerifyPassword("P4$$w0rd", hash))
+	assert.True(t, timedHasher.VerifyPassword("P4$$w0rd", hash))
+
+	assert.Equal(t, uint64(1), observations(t, registry, "chat_password_hashing_duration_seconds", "hash"))
+	assert.Equal(t, uint64(2), observations(t, registry, "chat_password_hashing_duration_seconds", "verify"))
+}
+
+// observations returns how many observations the histogram name holds for
+// the series whose only label has the given value.
+func observations(t *testing.T, gatherer prometheus.Gatherer, name string, labelValue string) uint64 {
+	families, err := gatherer.Gather()
+	assert.Nil(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if labels := metric.GetLabel(); len(labels) == 1 && labels[0].GetValue() == labelValue {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Secure_Tokens_Are_Unique(t *testing.T) {
+	first, err := GenerateSecureToken()
+	assert.Nil(t, err)
+	second, err := GenerateSecureToken()
+	assert.Nil(t, err)
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
+
+func Test_If_Token_Hash_Is_Deterministic(t *testing.T) {
+	assert.Equal(t, HashToken("token"), HashToken("token"))
+	assert.NotEqual(t, HashToken("token"), HashToken("another token"))
+	assert.Len(t, HashToken("token"), 64)
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type TokenClaims struct {
+	UserID    int32
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type TokenManager interface {
+	GenerateToken(userID int32) (string, time.Time, error)
+	ParseToken(token string) (*TokenClaims, error)
+}
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+type JWTTokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewJWTTokenManager(secret string, ttl time.Duration) *JWTTokenManager {
+	return &JWTTokenManager{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+func (m *JWTTokenManager) GenerateToken(userID int32) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.ttl)
+
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.FormatInt(int64(userID), 10),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (m *JWTTokenManager) ParseToken(token string) (*TokenClaims, error) {
+	claims := &jwt.RegisteredClaims{}
+
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	tokenClaims := &TokenClaims{
+		UserID:    int32(userID),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		tokenClaims.IssuedAt = claims.IssuedAt.Time
+	}
+
+	return tokenClaims, nil
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTokenManager struct {
+	mock.Mock
+}
+
+func (m *MockTokenManager) GenerateToken(arg1 int32) (string, time.Time, error) {
+	args := m.Called(arg1)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockTokenManager) ParseToken(arg1 string) (*TokenClaims, error) {
+	args := m.Called(arg1)
+	claims, _ := args.Get(0).(*TokenClaims)
+	return claims, args.Error(1)
+}
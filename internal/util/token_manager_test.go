@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Token_Is_Generated_And_Parsed(t *testing.T) {
+	tokenManager := NewJWTTokenManager("secret", time.Minute)
+
+	token, expiresAt, err := tokenManager.GenerateToken(42)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+
+	claims, err := tokenManager.ParseToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(42), claims.UserID)
+	assert.Equal(t, expiresAt.Unix(), claims.ExpiresAt.Unix())
+	assert.WithinDuration(t, time.Now(), claims.IssuedAt, time.Second)
+}
+
+func Test_If_Get_Error_When_Token_Is_Expired(t *testing.T) {
+	tokenManager := NewJWTTokenManager("secret", -time.Minute)
+
+	token, _, _ := tokenManager.GenerateToken(42)
+
+	_, err := tokenManager.ParseToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func Test_If_Get_Error_When_Token_Is_Signed_With_Another_Key(t *testing.T) {
+	token, _, _ := NewJWTTokenManager("another secret", time.Minute).GenerateToken(42)
+
+	_, err := NewJWTTokenManager("secret", time.Minute).ParseToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func Test_If_Get_Error_When_Token_Is_Malformed(t *testing.T) {
+	_, err := NewJWTTokenManager("secret", time.Minute).ParseToken("not.a.token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,58 @@
+package httpserver
+
+import (
+	"net"
+	"time"
+)
+
+// Option tunes a Server.
+type Option func(*Server)
+
+func Port(port string) Option {
+	return func(s *Server) {
+		s.server.Addr = net.JoinHostPort("", port)
+	}
+}
+
+// ReadHeaderTimeout bounds how long a client may take to send the request
+// headers, which protects against slow clients holding connections.
+func ReadHeaderTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.server.ReadHeaderTimeout = timeout
+	}
+}
+
+func ReadTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.server.ReadTimeout = timeout
+	}
+}
+
+// WriteTimeout bounds how long writing a response may take. Long-lived
+// responses, such as event streams, must lift it themselves.
+func WriteTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.server.WriteTimeout = timeout
+	}
+}
+
+func IdleTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.server.IdleTimeout = timeout
+	}
+}
+
+// ShutdownTimeout bounds how long Shutdown waits for requests and drained
+// connections before closing them forcibly.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// Drain runs drainer on Shutdown.
+func Drain(drainer Drainer) Option {
+	return func(s *Server) {
+		s.drainers = append(s.drainers, drainer)
+	}
+}
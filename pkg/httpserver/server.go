@@ -0,0 +1,109 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultAddr              = ":80"
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultReadTimeout       = 15 * time.Second
+	defaultWriteTimeout      = 15 * time.Second
+	defaultIdleTimeout       = 60 * time.Second
+	defaultShutdownTimeout   = 30 * time.Second
+)
+
+// Drainer closes connections that Shutdown does not wait for on its own,
+// such as hijacked WebSockets and event streams that never end, and returns
+// once they are closed or ctx is done.
+type Drainer func(ctx context.Context) error
+
+// Server serves HTTP in the background and shuts down gracefully.
+type Server struct {
+	server          *http.Server
+	listener        net.Listener
+	notify          chan error
+	shutdownTimeout time.Duration
+	drainers        []Drainer
+}
+
+func New(handler http.Handler, opts ...Option) *Server {
+	s := &Server{
+		server: &http.Server{
+			Handler:           handler,
+			Addr:              defaultAddr,
+			ReadHeaderTimeout: defaultReadHeaderTimeout,
+			ReadTimeout:       defaultReadTimeout,
+			WriteTimeout:      defaultWriteTimeout,
+			IdleTimeout:       defaultIdleTimeout,
+		},
+		notify:          make(chan error, 1),
+		shutdownTimeout: defaultShutdownTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Start listens right away, so a port already in use fails here, then
+// serves in the background.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			s.notify <- err
+		}
+		close(s.notify)
+	}()
+
+	return nil
+}
+
+// Addr is the address the server listens on, once started.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Notify reports the error the server stopped with, if it stopped before
+// Shutdown.
+func (s *Server) Notify() <-chan error {
+	return s.notify
+}
+
+// Shutdown stops accepting connections and waits, up to the shutdown
+// timeout, for in-flight requests and drainers to finish. Drainers run
+// alongside, as the requests of event streams only end once drained.
+// Whatever is left after the timeout is closed forcibly.
+func (s *Server) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	drained := make(chan error, len(s.drainers))
+	for _, drainer := range s.drainers {
+		go func(drainer Drainer) {
+			drained <- drainer(ctx)
+		}(drainer)
+	}
+
+	err := s.server.Shutdown(ctx)
+	for range s.drainers {
+		err = errors.Join(err, <-drained)
+	}
+
+	if err != nil {
+		err = errors.Join(err, s.server.Close())
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_If_Shutdown_Waits_For_In_Flight_Requests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	}), Port("0"))
+	assert.Nil(t, server.Start())
+
+	responses := make(chan string, 1)
+	go func() {
+		response, err := http.Get("http://" + server.Addr().String())
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		responses <- string(body)
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- server.Shutdown()
+	}()
+
+	select {
+	case <-shutdown:
+		t.Fatal("shutdown returned before the request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Nil(t, <-shutdown)
+	assert.Equal(t, "done", <-responses)
+
+	_, err := http.Get("http://" + server.Addr().String())
+	assert.NotNil(t, err)
+}
+
+func Test_If_Shutdown_Runs_Drainers_Alongside_Requests(t *testing.T) {
+	stream := make(chan struct{})
+	started := make(chan struct{})
+	server := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		// A long-lived response only ends once the drainer closes it.
+		<-stream
+	}), Port("0"), Drain(func(ctx context.Context) error {
+		close(stream)
+		return nil
+	}))
+	assert.Nil(t, server.Start())
+
+	go func() {
+		if response, err := http.Get("http://" + server.Addr().String()); err == nil {
+			response.Body.Close()
+		}
+	}()
+	<-started
+
+	assert.Nil(t, server.Shutdown())
+}
+
+func Test_If_Shutdown_Gives_Up_After_The_Timeout(t *testing.T) {
+	started := make(chan struct{})
+	server := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}), Port("0"), ShutdownTimeout(50*time.Millisecond), Drain(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	assert.Nil(t, server.Start())
+
+	go func() {
+		if response, err := http.Get("http://" + server.Addr().String()); err == nil {
+			response.Body.Close()
+		}
+	}()
+	<-started
+
+	err := server.Shutdown()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func Test_If_Start_Fails_When_The_Port_Is_Taken(t *testing.T) {
+	server := New(http.NotFoundHandler(), Port("0"))
+	assert.Nil(t, server.Start())
+	defer server.Shutdown()
+
+	_, port, _ := net.SplitHostPort(server.Addr().String())
+	assert.NotNil(t, New(http.NotFoundHandler(), Port(port)).Start())
+}
+
+func Test_If_Timeouts_Are_Applied(t *testing.T) {
+	server := New(http.NotFoundHandler(), Port("8081"), ReadHeaderTimeout(time.Second), ReadTimeout(2*time.Second), WriteTimeout(3*time.Second), IdleTimeout(4*time.Second), ShutdownTimeout(5*time.Second))
+
+	assert.Equal(t, ":8081", server.server.Addr)
+	assert.Equal(t, time.Second, server.server.ReadHeaderTimeout)
+	assert.Equal(t, 2*time.Second, server.server.ReadTimeout)
+	assert.Equal(t, 3*time.Second, server.server.WriteTimeout)
+	assert.Equal(t, 4*time.Second, server.server.IdleTimeout)
+	assert.Equal(t, 5*time.Second, server.shutdownTimeout)
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Interface is the logger handed to the layers of the server. Every call
+// takes the context of the work it logs, so entries carry the attributes
+// stored in it with WithAttrs, such as the request id.
+type Interface interface {
+	Debug(ctx context.Context, msg string, args ...any)
+	Info(ctx context.Context, msg string, args ...any)
+	Warn(ctx context.Context, msg string, args ...any)
+	Error(ctx context.Context, msg string, args ...any)
+}
+
+// Logger writes JSON entries through log/slog.
+type Logger struct {
+	logger *slog.Logger
+}
+
+// New logs entries of level and above, one JSON object per line. Levels are
+// debug, info, warn and error; anything else is info.
+func New(level string, out io.Writer) *Logger {
+	handler := slog.NewJSONHandler(out, &slog.HandlerOptions{
+		Level:       parseLevel(level),
+		ReplaceAttr: redact,
+	})
+	return &Logger{logger: slog.New(contextHandler{handler})}
+}
+
+// Discard drops every entry, for tests.
+func Discard() *Logger {
+	return New("error", io.Discard)
+}
+
+func (l *Logger) Debug(ctx context.Context, msg string, args ...any) {
+	l.logger.DebugContext(ctx, msg, args...)
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, args ...any) {
+	l.logger.InfoContext(ctx, msg, args...)
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, args ...any) {
+	l.logger.WarnContext(ctx, msg, args...)
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, args ...any) {
+	l.logger.ErrorContext(ctx, msg, args...)
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeEntries(t *testing.T, out *bytes.Buffer) []map[string]any {
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("entry is not JSON: %s", line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func Test_If_Entries_Are_Written_As_JSON(t *testing.T) {
+	var out bytes.Buffer
+	l := New("info", &out)
+
+	l.Info(context.Background(), "user created", "user_id", 1)
+
+	entries := decodeEntries(t, &out)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "INFO", entries[0]["level"])
+	assert.Equal(t, "user created", entries[0]["msg"])
+	assert.Equal(t, float64(1), entries[0]["user_id"])
+}
+
+func Test_If_Entries_Below_The_Level_Are_Dropped(t *testing.T) {
+	var out bytes.Buffer
+	l := New("warn", &out)
+
+	l.Debug(context.Background(), "debug")
+	l.Info(context.Background(), "info")
+	l.Warn(context.Background(), "warn")
+	l.Error(context.Background(), "error")
+
+	entries := decodeEntries(t, &out)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "warn", entries[0]["msg"])
+	assert.Equal(t, "error", entries[1]["msg"])
+}
+
+func Test_If_Entries_Carry_The_Context_Attributes(t *testing.T) {
+	var out bytes.Buffer
+	l := New("info", &out)
+
+	ctx := WithAttrs(context.Background(), slog.String("request_id", "abc"))
+	ctx = WithAttrs(ctx, slog.Int("user_id", 7))
+	l.Info(ctx, "room created")
+	l.Info(context.Background(), "outside any request")
+
+	entries := decodeEntries(t, &out)
+	assert.Equal(t, "abc", entries[0]["request_id"])
+	assert.Equal(t, float64(7), entries[0]["user_id"])
+	assert.NotContains(t, entries[1], "request_id")
+}
+
+func Test_If_Secrets_Are_Redacted(t *testing.T) {
+	var out bytes.Buffer
+	l := New("info", &out)
+
+	l.Info(context.Background(), "login", "login", "eduardo", "password", "P4$$w0rd", "newPassword", "P4$$w0rd2",
+		"refresh_token", "abc", slog.Group("headers", slog.String("Authorization", "Bearer abc")))
+
+	assert.NotContains(t, out.String(), "P4$$w0rd")
+	assert.NotContains(t, out.String(), "abc")
+	entries := decodeEntries(t, &out)
+	assert.Equal(t, "eduardo", entries[0]["login"])
+	assert.Equal(t, redacted, entries[0]["password"])
+	assert.Equal(t, redacted, entries[0]["newPassword"])
+	assert.Equal(t, redacted, entries[0]["refresh_token"])
+	assert.Equal(t, map[string]any{"Authorization": redacted}, entries[0]["headers"])
+}
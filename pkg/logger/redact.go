@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// secretKeys are the parts of attribute keys whose values are never logged,
+// whatever their case or separators: password, newPassword, refresh_token,
+// Authorization...
+var secretKeys = []string{"password", "secret", "token", "authorization", "cookie", "apikey"}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if isSecret(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+	return attr
+}
+
+func isSecret(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, secret := range secretKeys {
+		if strings.Contains(normalized, secret) {
+			return true
+		}
+	}
+	return false
+}